@@ -2,12 +2,16 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"net/http"
 	"os"
 	"os/signal"
 	"time"
+	"uhuaha/computers-management/internal/cache"
+	"uhuaha/computers-management/internal/config"
 	"uhuaha/computers-management/internal/db"
 	"uhuaha/computers-management/internal/db/postgres"
+	"uhuaha/computers-management/internal/events"
 	"uhuaha/computers-management/internal/handler"
 	"uhuaha/computers-management/internal/router"
 	"uhuaha/computers-management/internal/service"
@@ -17,19 +21,129 @@ import (
 
 const PORT = ":8081"
 
+// eventBufferSize is the number of computer events buffered per client stream.
+const eventBufferSize = 256
+
+// allocationExpiryInterval is the maximum time between two releases of expired IP allocations.
+const allocationExpiryInterval = time.Hour
+
+// eventRelayInterval is the maximum time between two reads of the event log by the event relay.
+const eventRelayInterval = 5 * time.Second
+
+// eventCleanupInterval is the maximum time between two deletions of expired computer events.
+const eventCleanupInterval = time.Hour
+
 func main() {
-	dbConnection, err := db.NewConnection()
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(os.Args[2:]); err != nil {
+			log.Fatalf("Failed to run migrate command: %v", err)
+		}
+
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	dbConnection, err := db.NewConnection(cfg.Database)
 	if err != nil {
 		log.Fatalf("Failed to connect to DB: %v", err)
 	}
-	repository := postgres.NewRepository(dbConnection)
 
-	notifierConnection := "http://localhost:8080"
-	notifier := service.NewNotifier(notifierConnection)
+	if cfg.AutoMigrate {
+		err = db.RunMigrations(dbConnection)
+	} else {
+		err = db.CheckSchema(dbConnection)
+	}
+
+	if err != nil {
+		log.Fatalf("Failed to prepare DB schema: %v", err)
+	}
+
+	var repositoryOpts []postgres.RepositoryOption
+
+	if cfg.Database.ReplicaURL != "" {
+		replicaConnection, err := db.NewReplicaConnection(cfg.Database)
+		if err != nil {
+			log.Fatalf("Failed to connect to read replica: %v", err)
+		}
+
+		defer replicaConnection.Close()
+
+		repositoryOpts = append(repositoryOpts, postgres.WithReplica(replicaConnection))
+	}
+
+	repository := postgres.NewRepository(dbConnection, repositoryOpts...)
+	defer repository.Close()
 
-	computerMgmtService := service.NewComputerMgmtService(repository, notifier)
-	handler := handler.New(computerMgmtService)
-	router := router.New(handler)
+	var computerRepository service.ComputerRepository = repository
+
+	if cfg.Cache.Enabled {
+		cachedRepository := cache.NewRepository(repository, cache.NewLRUStore(cfg.Cache.Size), cfg.Cache.TTL)
+		computerRepository = cachedRepository
+
+		// The hit and miss counters are served at /debug/vars.
+		expvar.Publish("computer_cache", expvar.Func(func() any { return cachedRepository.Stats() }))
+	}
+
+	notificationSender, err := newNotificationSender(cfg.Notifications)
+	if err != nil {
+		log.Fatalf("Failed to configure notifications: %v", err)
+	}
+
+	notificationTemplates, err := newNotificationTemplates(cfg.Notifications)
+	if err != nil {
+		log.Fatalf("Failed to load notification templates: %v", err)
+	}
+
+	notifier := service.NewNotifier(notificationSender, notificationTemplates, cfg.Notifications.Locale)
+
+	eventBus := events.NewBus(eventBufferSize)
+	webhookMgmtService := service.NewWebhookMgmtService(repository)
+
+	eventRelay, err := service.NewEventRelay(context.Background(), repository, eventBus, webhookMgmtService)
+	if err != nil {
+		log.Fatalf("Failed to start the event relay: %v", err)
+	}
+
+	subnetMgmtService := service.NewSubnetMgmtService(repository, cfg.IPAllocationTTL)
+	computerMgmtService := service.NewComputerMgmtService(computerRepository, notifier,
+		service.WithIPConflictPolicy(service.IPConflictPolicy{
+			Mode:   service.IPConflictMode(cfg.IPConflictMode),
+			Scopes: cfg.IPConflictScopes,
+		}),
+		service.WithNotificationCooldown(cfg.Notifications.Cooldown),
+		service.WithEventPublisher(eventRelay),
+	)
+	warrantyMgmtService := service.NewWarrantyMgmtService(repository, notifier, cfg.WarrantyWindowDays)
+	eventMgmtService := service.NewEventMgmtService(repository, eventBus, cfg.EventsRetention)
+
+	computerHandler := handler.New(computerMgmtService)
+	subnetHandler := handler.NewSubnetHandler(subnetMgmtService)
+	warrantyHandler := handler.NewWarrantyHandler(warrantyMgmtService)
+	eventHandler := handler.NewEventHandler(eventMgmtService, cfg.EventsHeartbeatInterval)
+	webhookHandler := handler.NewWebhookHandler(webhookMgmtService)
+	notificationHandler := handler.NewNotificationHandler(notifier)
+	router := router.New(computerHandler, subnetHandler, warrantyHandler, eventHandler, webhookHandler,
+		notificationHandler)
+	router.Handle("/debug/vars", expvar.Handler()).Methods("GET")
+
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+
+	go warrantyMgmtService.RunScheduler(schedulerCtx, cfg.WarrantyCheckInterval)
+	go webhookMgmtService.Run(schedulerCtx)
+	go eventRelay.Run(schedulerCtx, eventRelayInterval)
+
+	if cfg.IPAllocationTTL > 0 {
+		go subnetMgmtService.RunAllocationExpiry(schedulerCtx, min(cfg.IPAllocationTTL, allocationExpiryInterval))
+	}
+
+	if cfg.EventsRetention > 0 {
+		go eventMgmtService.RunEventCleanup(schedulerCtx, min(cfg.EventsRetention, eventCleanupInterval))
+	}
 
 	server := &http.Server{
 		Addr:    PORT,
@@ -52,6 +166,8 @@ func main() {
 
 	log.Info("Shutting down server...")
 
+	stopScheduler()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"uhuaha/computers-management/internal/config"
+	"uhuaha/computers-management/internal/db"
+
+	"github.com/bdlm/log"
+	"github.com/golang-migrate/migrate/v4"
+)
+
+const migrateUsage = "usage: migrate up|down|version|force <version>"
+
+// runMigrateCommand executes the "migrate" subcommand with the given arguments:
+//
+//	migrate up               applies all pending migrations
+//	migrate down             rolls back the most recent migration
+//	migrate version          prints the current schema version
+//	migrate force <version>  sets the schema version and clears the dirty flag
+func runMigrateCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	dbConfig, err := config.LoadDatabase()
+	if err != nil {
+		return fmt.Errorf("failed to load database configuration: %w", err)
+	}
+
+	dbConnection, err := db.NewConnection(dbConfig)
+	if err != nil {
+		return fmt.Errorf("failed to connect to DB: %w", err)
+	}
+
+	defer dbConnection.Close()
+
+	migrator, err := db.NewMigrator(dbConnection)
+	if err != nil {
+		return err
+	}
+
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		if err := migrator.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			return fmt.Errorf("failed to migrate up: %w", err)
+		}
+	case "down":
+		if err := migrator.Steps(-1); err != nil {
+			return fmt.Errorf("failed to migrate down: %w", err)
+		}
+	case "version":
+		version, dirty, err := migrator.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			log.Info("no migration has been applied yet")
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to get schema version: %w", err)
+		}
+
+		log.Infof("schema version: %d (dirty: %t)", version, dirty)
+		return nil
+	case "force":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", args[1], err)
+		}
+
+		if err := migrator.Force(version); err != nil {
+			return fmt.Errorf("failed to force version %d: %w", version, err)
+		}
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	log.Info("migrate " + args[0] + " finished successfully")
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"net"
+	"net/smtp"
+	"os"
+	"slices"
+	"text/template"
+	"uhuaha/computers-management/internal/config"
+	"uhuaha/computers-management/internal/notify"
+	"uhuaha/computers-management/templates/notifications"
+)
+
+// defaultNotificationTemplates are the templates of the channels that are not overridden by the configuration.
+// The notify service receives the message unchanged.
+var defaultNotificationTemplates = map[string]string{
+	"smtp":   notify.DefaultSMTPTemplate,
+	"chat":   notify.DefaultChatTemplate,
+	"syslog": notify.DefaultSyslogTemplate,
+}
+
+// newNotificationSender creates the channels selected by the configuration and a fan-out sender dispatching
+// notifications to them by their level.
+func newNotificationSender(cfg config.NotificationConfig) (*notify.FanOut, error) {
+	selected := append([]string(nil), cfg.Channels...)
+	for _, channels := range cfg.LevelChannels {
+		selected = append(selected, channels...)
+	}
+
+	var targets []notify.Target
+
+	for _, name := range config.NotificationChannels {
+		if !slices.Contains(selected, name) {
+			continue
+		}
+
+		channel, err := newNotificationChannel(name, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s notification channel: %w", name, err)
+		}
+
+		target := notify.Target{Name: name, Channel: channel}
+
+		text, ok := cfg.Templates[name]
+		if !ok {
+			text, ok = defaultNotificationTemplates[name]
+		}
+
+		if ok {
+			target.Template, err = template.New(name).Parse(text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid template of %s notification channel: %w", name, err)
+			}
+		}
+
+		targets = append(targets, target)
+	}
+
+	return notify.NewFanOut(targets, cfg.Channels, cfg.LevelChannels)
+}
+
+func newNotificationChannel(name string, cfg config.NotificationConfig) (notify.Channel, error) {
+	switch name {
+	case "smtp":
+		var auth smtp.Auth
+
+		if cfg.SMTP.Username != "" {
+			host, _, _ := net.SplitHostPort(cfg.SMTP.Addr)
+			auth = smtp.PlainAuth("", cfg.SMTP.Username, cfg.SMTP.Password, host)
+		}
+
+		return notify.NewSMTPChannel(cfg.SMTP.Addr, cfg.SMTP.From, cfg.SMTP.To, auth)
+	case "chat":
+		return notify.NewChatChannel(cfg.ChatWebhookURL), nil
+	case "syslog":
+		return notify.NewSyslogChannel(cfg.Syslog.Network, cfg.Syslog.Addr, cfg.Syslog.Tag)
+	default:
+		return notify.NewHTTPChannel(cfg.NotifyURL), nil
+	}
+}
+
+// newNotificationTemplates loads the notification templates from the configured directory, or the built-in ones
+// without it, and checks that they contain the configured locale.
+func newNotificationTemplates(cfg config.NotificationConfig) (*notify.Templates, error) {
+	var fsys fs.FS = notifications.FS
+	if cfg.TemplatesDir != "" {
+		fsys = os.DirFS(cfg.TemplatesDir)
+	}
+
+	templates, err := notify.LoadTemplates(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	if !templates.HasLocale(cfg.Locale) {
+		return nil, fmt.Errorf("no notification templates for locale %q", cfg.Locale)
+	}
+
+	return templates, nil
+}
@@ -1,3 +1,8 @@
 #!/bin/bash
 
 mockgen -source=internal/handler/computer_management.go -destination=internal/mocks/computer_management_service.go -package=mocks
+mockgen -source=internal/handler/subnet_management.go -destination=internal/mocks/subnet_management_service.go -package=mocks
+mockgen -source=internal/handler/warranty_management.go -destination=internal/mocks/warranty_management_service.go -package=mocks
+mockgen -source=internal/handler/event_management.go -destination=internal/mocks/event_management_service.go -package=mocks
+mockgen -source=internal/handler/webhook_management.go -destination=internal/mocks/webhook_management_service.go -package=mocks
+mockgen -source=internal/handler/notification_management.go -destination=internal/mocks/notification_management_service.go -package=mocks
@@ -0,0 +1,358 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+	"uhuaha/computers-management/internal/consistency"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/service"
+
+	"github.com/bdlm/log"
+)
+
+var _ service.ComputerRepository = (*Repository)(nil)
+
+// Stats holds the number of cache hits and misses of a Repository.
+type Stats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// Repository decorates a service.ComputerRepository with a read-through cache for GetComputer and
+// GetComputersByEmployee. Every write through the repository invalidates the cached computers it changes as well
+// as the cached computer lists of their previous and new employees. Within WithSnapshot, the entries are cached per
+// version of the computers instead, so that they match the snapshot. All other methods are passed through.
+//
+// The subnet of a computer is derived from the subnets, which are not changed through this repository. A
+// computer cached outside of WithSnapshot may therefore show an outdated subnet until its time to live has passed.
+type Repository struct {
+	service.ComputerRepository
+
+	store Store
+	ttl   time.Duration
+	stats *counters
+
+	// pending collects the keys to invalidate once the surrounding transaction has ended. It is nil outside of WithTx.
+	pending *pendingKeys
+	// version is the version of the computers seen by the snapshot. It is nil outside of WithSnapshot.
+	version *dbo.ComputersVersion
+}
+
+type counters struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type pendingKeys struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+// NewRepository returns repo decorated with a cache whose entries are held in store for the given time to live.
+func NewRepository(repo service.ComputerRepository, store Store, ttl time.Duration) *Repository {
+	return &Repository{
+		ComputerRepository: repo,
+		store:              store,
+		ttl:                ttl,
+		stats:              &counters{},
+	}
+}
+
+// Stats returns the number of cache hits and misses since the repository has been created.
+func (r *Repository) Stats() Stats {
+	return Stats{
+		Hits:   r.stats.hits.Load(),
+		Misses: r.stats.misses.Load(),
+	}
+}
+
+func computerKey(computerID int) string {
+	return fmt.Sprintf("computer:%d", computerID)
+}
+
+func employeeKey(employee string) string {
+	return "employee:" + employee
+}
+
+// GetComputer returns the cached computer or retrieves it from the decorated repository and caches it.
+func (r *Repository) GetComputer(computerID int) (dbo.Computer, error) {
+	var computer dbo.Computer
+
+	err := r.readThrough(context.Background(), computerKey(computerID), &computer, func() (any, error) {
+		return r.ComputerRepository.GetComputer(computerID)
+	})
+
+	return computer, err
+}
+
+// GetComputersByEmployee returns the cached computers of the employee or retrieves them from the decorated
+// repository and caches them. Requests that have to read their own writes bypass the cache.
+func (r *Repository) GetComputersByEmployee(ctx context.Context, employee string) ([]dbo.Computer, error) {
+	var computers []dbo.Computer
+
+	err := r.readThrough(ctx, employeeKey(employee), &computers, func() (any, error) {
+		return r.ComputerRepository.GetComputersByEmployee(ctx, employee)
+	})
+
+	return computers, err
+}
+
+// GetComputersVersion returns the version seen by the snapshot within WithSnapshot and retrieves the current version
+// from the decorated repository otherwise.
+func (r *Repository) GetComputersVersion(ctx context.Context) (dbo.ComputersVersion, error) {
+	if r.version != nil {
+		return *r.version, nil
+	}
+
+	return r.ComputerRepository.GetComputersVersion(ctx)
+}
+
+// readThrough decodes the cached value of key into dest. On a miss, it loads the value, caches it and decodes it
+// into dest. The cache is bypassed within transactions, since they may see uncommitted changes, and for requests
+// that have to read their own writes unless the key belongs to the version of a snapshot. Failures of the store are
+// logged and treated as a miss.
+func (r *Repository) readThrough(ctx context.Context, key string, dest any, load func() (any, error)) error {
+	useCache := r.pending == nil && (r.version != nil || !consistency.ReadYourWrites(ctx))
+
+	if r.version != nil {
+		// Every change of the computers increments the version, so entries of a version never become outdated.
+		key = fmt.Sprintf("%s@%d", key, r.version.Version)
+	}
+
+	if useCache {
+		value, found, err := r.store.Get(key)
+		if err != nil {
+			log.Warnf("failed to get %s from cache: %v", key, err)
+		} else if found {
+			if err := json.Unmarshal(value, dest); err == nil {
+				r.stats.hits.Add(1)
+				return nil
+			}
+
+			log.Warnf("failed to decode %s from cache: %v", key, err)
+		}
+
+		r.stats.misses.Add(1)
+	}
+
+	loaded, err := load()
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(loaded)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+
+	if useCache {
+		if err := r.store.Set(key, value, r.ttl); err != nil {
+			log.Warnf("failed to set %s in cache: %v", key, err)
+		}
+	}
+
+	return json.Unmarshal(value, dest)
+}
+
+// WithTx runs fn with a cached repository using the transaction of the decorated repository. The keys changed
+// within the transaction are invalidated once it has ended.
+func (r *Repository) WithTx(ctx context.Context, fn func(repo service.ComputerRepository) error) error {
+	if r.pending != nil {
+		return fn(r)
+	}
+
+	pending := &pendingKeys{}
+
+	err := r.ComputerRepository.WithTx(ctx, func(repo service.ComputerRepository) error {
+		return fn(&Repository{
+			ComputerRepository: repo,
+			store:              r.store,
+			ttl:                r.ttl,
+			stats:              r.stats,
+			pending:            pending,
+		})
+	})
+
+	// The keys are invalidated after a rollback as well since the cache may have been filled concurrently.
+	r.deleteKeys(pending.keys)
+
+	return err
+}
+
+// WithSnapshot runs fn with a cached repository reading from a snapshot of the decorated repository. Its cache entries
+// are keyed by the version of the computers seen by the snapshot. Within WithTx, fn runs within the transaction.
+func (r *Repository) WithSnapshot(ctx context.Context, fn func(repo service.ComputerRepository) error) error {
+	if r.pending != nil || r.version != nil {
+		return fn(r)
+	}
+
+	return r.ComputerRepository.WithSnapshot(ctx, func(repo service.ComputerRepository) error {
+		version, err := repo.GetComputersVersion(ctx)
+		if err != nil {
+			return err
+		}
+
+		return fn(&Repository{
+			ComputerRepository: repo,
+			store:              r.store,
+			ttl:                r.ttl,
+			stats:              r.stats,
+			version:            &version,
+		})
+	})
+}
+
+func (r *Repository) AddComputer(computer dbo.Computer) (int, error) {
+	var computerID int
+
+	err := r.invalidate(nil, []sql.NullString{computer.EmployeeAbbreviation}, func() (err error) {
+		computerID, err = r.ComputerRepository.AddComputer(computer)
+		return err
+	})
+
+	return computerID, err
+}
+
+func (r *Repository) UpdateComputer(computerID int, data dbo.Computer) error {
+	return r.invalidate([]int{computerID}, nil, func() error {
+		return r.ComputerRepository.UpdateComputer(computerID, data)
+	})
+}
+
+func (r *Repository) DeleteComputer(computerID int) error {
+	return r.invalidate([]int{computerID}, nil, func() error {
+		return r.ComputerRepository.DeleteComputer(computerID)
+	})
+}
+
+func (r *Repository) AddStatusTransition(transition dbo.StatusTransition) (dbo.StatusTransition, error) {
+	var added dbo.StatusTransition
+
+	err := r.invalidate([]int{transition.ComputerID}, nil, func() (err error) {
+		added, err = r.ComputerRepository.AddStatusTransition(transition)
+		return err
+	})
+
+	return added, err
+}
+
+func (r *Repository) AddNetworkInterface(iface dbo.NetworkInterface) (int, error) {
+	var interfaceID int
+
+	err := r.invalidate([]int{iface.ComputerID}, nil, func() (err error) {
+		interfaceID, err = r.ComputerRepository.AddNetworkInterface(iface)
+		return err
+	})
+
+	return interfaceID, err
+}
+
+func (r *Repository) UpdateNetworkInterface(computerID, interfaceID int, data dbo.NetworkInterface) error {
+	return r.invalidate([]int{computerID}, nil, func() error {
+		return r.ComputerRepository.UpdateNetworkInterface(computerID, interfaceID, data)
+	})
+}
+
+func (r *Repository) DeleteNetworkInterface(computerID, interfaceID int) error {
+	return r.invalidate([]int{computerID}, nil, func() error {
+		return r.ComputerRepository.DeleteNetworkInterface(computerID, interfaceID)
+	})
+}
+
+func (r *Repository) CheckOutComputer(assignment dbo.Assignment, transition dbo.StatusTransition) (dbo.Assignment, error) {
+	var started dbo.Assignment
+
+	employee := sql.NullString{String: assignment.EmployeeAbbreviation, Valid: true}
+
+	err := r.invalidate([]int{assignment.ComputerID}, []sql.NullString{employee}, func() (err error) {
+		started, err = r.ComputerRepository.CheckOutComputer(assignment, transition)
+		return err
+	})
+
+	return started, err
+}
+
+func (r *Repository) CheckInComputer(transition dbo.StatusTransition) (dbo.Assignment, error) {
+	var ended dbo.Assignment
+
+	err := r.invalidate([]int{transition.ComputerID}, nil, func() (err error) {
+		ended, err = r.ComputerRepository.CheckInComputer(transition)
+		return err
+	})
+
+	return ended, err
+}
+
+func (r *Repository) UpdateComputers(computerIDs []int, changes dbo.ComputerChanges) error {
+	return r.invalidate(computerIDs, nil, func() error {
+		return r.ComputerRepository.UpdateComputers(computerIDs, changes)
+	})
+}
+
+func (r *Repository) DeleteComputers(computerIDs []int) error {
+	return r.invalidate(computerIDs, nil, func() error {
+		return r.ComputerRepository.DeleteComputers(computerIDs)
+	})
+}
+
+// invalidate runs write and removes the given computers, the computer lists of their current employees and the
+// computer lists of the given new employees from the cache. Within a transaction, the keys are removed once the
+// transaction has ended.
+func (r *Repository) invalidate(computerIDs []int, newEmployees []sql.NullString, write func() error) error {
+	var keys []string
+
+	if len(computerIDs) > 0 {
+		// The current employees are looked up before the write since it may change or delete them.
+		computers, err := r.ComputerRepository.GetComputersBySelector(dbo.ComputerSelector{IDs: computerIDs})
+		if err != nil {
+			return fmt.Errorf("failed to get computers to invalidate: %w", err)
+		}
+
+		for _, computer := range computers {
+			if computer.EmployeeAbbreviation.Valid {
+				keys = append(keys, employeeKey(computer.EmployeeAbbreviation.String))
+			}
+		}
+
+		for _, computerID := range computerIDs {
+			keys = append(keys, computerKey(computerID))
+		}
+	}
+
+	for _, employee := range newEmployees {
+		if employee.Valid {
+			keys = append(keys, employeeKey(employee.String))
+		}
+	}
+
+	if err := write(); err != nil {
+		return err
+	}
+
+	if r.pending != nil {
+		r.pending.mu.Lock()
+		r.pending.keys = append(r.pending.keys, keys...)
+		r.pending.mu.Unlock()
+
+		return nil
+	}
+
+	r.deleteKeys(keys)
+
+	return nil
+}
+
+func (r *Repository) deleteKeys(keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	if err := r.store.Delete(keys...); err != nil {
+		log.Errorf("failed to invalidate cache entries %v: %v", keys, err)
+	}
+}
@@ -0,0 +1,229 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/consistency"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepository implements the parts of service.ComputerRepository used by the tests and counts the lookups.
+// Calling any other method panics.
+type fakeRepository struct {
+	service.ComputerRepository
+
+	computers map[int]dbo.Computer
+	version   int64
+	lookups   int
+}
+
+func (r *fakeRepository) GetComputer(computerID int) (dbo.Computer, error) {
+	r.lookups++
+	return r.computers[computerID], nil
+}
+
+func (r *fakeRepository) GetComputersByEmployee(ctx context.Context, employee string) ([]dbo.Computer, error) {
+	r.lookups++
+
+	var computers []dbo.Computer
+	for _, computer := range r.computers {
+		if computer.EmployeeAbbreviation.String == employee {
+			computers = append(computers, computer)
+		}
+	}
+
+	return computers, nil
+}
+
+func (r *fakeRepository) GetComputersBySelector(selector dbo.ComputerSelector) ([]dbo.Computer, error) {
+	var computers []dbo.Computer
+	for _, id := range selector.IDs {
+		if computer, ok := r.computers[id]; ok {
+			computers = append(computers, computer)
+		}
+	}
+
+	return computers, nil
+}
+
+func (r *fakeRepository) UpdateComputer(computerID int, data dbo.Computer) error {
+	data.ID = computerID
+	data.EmployeeAbbreviation = r.computers[computerID].EmployeeAbbreviation
+	r.computers[computerID] = data
+
+	return nil
+}
+
+func (r *fakeRepository) CheckOutComputer(assignment dbo.Assignment, transition dbo.StatusTransition) (dbo.Assignment, error) {
+	computer := r.computers[assignment.ComputerID]
+	computer.EmployeeAbbreviation = sql.NullString{String: assignment.EmployeeAbbreviation, Valid: true}
+	r.computers[assignment.ComputerID] = computer
+
+	return assignment, nil
+}
+
+func (r *fakeRepository) GetComputersVersion(ctx context.Context) (dbo.ComputersVersion, error) {
+	return dbo.ComputersVersion{Version: r.version}, nil
+}
+
+func (r *fakeRepository) WithTx(ctx context.Context, fn func(repo service.ComputerRepository) error) error {
+	return fn(r)
+}
+
+func (r *fakeRepository) WithSnapshot(ctx context.Context, fn func(repo service.ComputerRepository) error) error {
+	return fn(r)
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		computers: map[int]dbo.Computer{
+			1: {ID: 1, Name: "PC1", EmployeeAbbreviation: sql.NullString{String: "ABC", Valid: true}},
+		},
+	}
+}
+
+func TestRepositoryCachesLookups(t *testing.T) {
+	fake := newFakeRepository()
+	repo := NewRepository(fake, NewLRUStore(10), time.Minute)
+
+	for range 2 {
+		computer, err := repo.GetComputer(1)
+		require.NoError(t, err)
+		assert.Equal(t, "PC1", computer.Name)
+
+		computers, err := repo.GetComputersByEmployee(context.Background(), "ABC")
+		require.NoError(t, err)
+		assert.Len(t, computers, 1)
+	}
+
+	assert.Equal(t, 2, fake.lookups)
+	assert.Equal(t, Stats{Hits: 2, Misses: 2}, repo.Stats())
+}
+
+func TestRepositoryInvalidatesOnWrite(t *testing.T) {
+	fake := newFakeRepository()
+	repo := NewRepository(fake, NewLRUStore(10), time.Minute)
+
+	_, err := repo.GetComputer(1)
+	require.NoError(t, err)
+
+	_, err = repo.GetComputersByEmployee(context.Background(), "ABC")
+	require.NoError(t, err)
+
+	_, err = repo.GetComputersByEmployee(context.Background(), "XYZ")
+	require.NoError(t, err)
+
+	err = repo.UpdateComputer(1, dbo.Computer{Name: "PC1 renamed"})
+	require.NoError(t, err)
+
+	_, err = repo.CheckOutComputer(dbo.Assignment{ComputerID: 1, EmployeeAbbreviation: "XYZ"}, dbo.StatusTransition{ComputerID: 1})
+	require.NoError(t, err)
+
+	computer, err := repo.GetComputer(1)
+	require.NoError(t, err)
+	assert.Equal(t, "PC1 renamed", computer.Name)
+
+	previous, err := repo.GetComputersByEmployee(context.Background(), "ABC")
+	require.NoError(t, err)
+	assert.Empty(t, previous)
+
+	current, err := repo.GetComputersByEmployee(context.Background(), "XYZ")
+	require.NoError(t, err)
+	assert.True(t, slices.ContainsFunc(current, func(c dbo.Computer) bool { return c.ID == 1 }))
+
+	assert.Equal(t, Stats{Hits: 0, Misses: 6}, repo.Stats())
+}
+
+func TestRepositoryInvalidatesAfterTransaction(t *testing.T) {
+	fake := newFakeRepository()
+	store := NewLRUStore(10)
+	repo := NewRepository(fake, store, time.Minute)
+
+	_, err := repo.GetComputer(1)
+	require.NoError(t, err)
+
+	err = repo.WithTx(context.Background(), func(tx service.ComputerRepository) error {
+		if err := tx.UpdateComputer(1, dbo.Computer{Name: "PC1 renamed"}); err != nil {
+			return err
+		}
+
+		// Reads within the transaction bypass the cache.
+		computer, err := tx.GetComputer(1)
+		require.NoError(t, err)
+		assert.Equal(t, "PC1 renamed", computer.Name)
+
+		_, found, _ := store.Get(computerKey(1))
+		assert.True(t, found, "invalidated before the end of the transaction")
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, found, _ := store.Get(computerKey(1))
+	assert.False(t, found)
+}
+
+func TestRepositoryBypassesCacheForReadYourWrites(t *testing.T) {
+	fake := newFakeRepository()
+	repo := NewRepository(fake, NewLRUStore(10), time.Minute)
+
+	ctx := consistency.WithReadYourWrites(context.Background())
+
+	for range 2 {
+		_, err := repo.GetComputersByEmployee(ctx, "ABC")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, fake.lookups)
+	assert.Equal(t, Stats{}, repo.Stats())
+}
+
+func TestRepositoryCachesSnapshotsByVersion(t *testing.T) {
+	fake := newFakeRepository()
+	repo := NewRepository(fake, NewLRUStore(10), time.Minute)
+
+	// Snapshots read from the primary use the cache as well, since their entries match the version they have seen.
+	ctx := consistency.WithReadYourWrites(context.Background())
+
+	read := func() (dbo.ComputersVersion, dbo.Computer) {
+		var (
+			version  dbo.ComputersVersion
+			computer dbo.Computer
+		)
+
+		err := repo.WithSnapshot(ctx, func(snapshot service.ComputerRepository) (err error) {
+			if version, err = snapshot.GetComputersVersion(ctx); err != nil {
+				return err
+			}
+
+			computer, err = snapshot.GetComputer(1)
+
+			return err
+		})
+		require.NoError(t, err)
+
+		return version, computer
+	}
+
+	read()
+	version, computer := read()
+	assert.Equal(t, int64(0), version.Version)
+	assert.Equal(t, "PC1", computer.Name)
+	assert.Equal(t, Stats{Hits: 1, Misses: 1}, repo.Stats())
+
+	// A change of the computers leads to a new version and therefore to new entries.
+	fake.version++
+	fake.computers[1] = dbo.Computer{ID: 1, Name: "PC1 renamed"}
+
+	version, computer = read()
+	assert.Equal(t, int64(1), version.Version)
+	assert.Equal(t, "PC1 renamed", computer.Name)
+	assert.Equal(t, 2, fake.lookups)
+}
@@ -0,0 +1,115 @@
+// Package cache provides a read-through cache for computer lookups that decorates a service.ComputerRepository.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Store holds encoded values for a limited time. It is implemented by LRUStore and can be implemented by a client
+// of a Redis-compatible store to share the cache between several instances of the service.
+type Store interface {
+	// Get returns the value of the key and whether it was found and has not expired.
+	Get(key string) ([]byte, bool, error)
+	// Set stores the value of the key for the given time to live.
+	Set(key string, value []byte, ttl time.Duration) error
+	// Delete removes the keys. Missing keys are ignored.
+	Delete(keys ...string) error
+}
+
+// LRUStore is an in-memory Store holding up to a fixed number of entries. If it is full, the least recently used
+// entry is evicted.
+type LRUStore struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	// order holds the entries from the most to the least recently used one.
+	order *list.List
+	now   func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRUStore returns an empty LRUStore holding up to capacity entries.
+func NewLRUStore(capacity int) *LRUStore {
+	return &LRUStore{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (s *LRUStore) Get(key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !s.now().Before(entry.expiresAt) {
+		s.remove(element)
+		return nil, false, nil
+	}
+
+	s.order.MoveToFront(element)
+
+	return entry.value, true, nil
+}
+
+func (s *LRUStore) Set(key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt := s.now().Add(ttl)
+
+	if element, ok := s.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		s.order.MoveToFront(element)
+
+		return nil
+	}
+
+	s.entries[key] = s.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+
+	if s.order.Len() > s.capacity {
+		s.remove(s.order.Back())
+	}
+
+	return nil
+}
+
+func (s *LRUStore) Delete(keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := s.entries[key]; ok {
+			s.remove(element)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of entries including the expired ones that have not been evicted yet.
+func (s *LRUStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
+
+func (s *LRUStore) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.entries, element.Value.(*lruEntry).key)
+}
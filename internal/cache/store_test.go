@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUStore(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store := NewLRUStore(2)
+	store.now = func() time.Time { return now }
+
+	get := func(key string) string {
+		value, found, err := store.Get(key)
+		require.NoError(t, err)
+
+		if !found {
+			return ""
+		}
+
+		return string(value)
+	}
+
+	t.Run("evicts the least recently used entry", func(t *testing.T) {
+		require.NoError(t, store.Set("a", []byte("A"), time.Minute))
+		require.NoError(t, store.Set("b", []byte("B"), time.Minute))
+
+		assert.Equal(t, "A", get("a"))
+
+		require.NoError(t, store.Set("c", []byte("C"), time.Minute))
+
+		assert.Equal(t, "A", get("a"))
+		assert.Empty(t, get("b"))
+		assert.Equal(t, "C", get("c"))
+		assert.Equal(t, 2, store.Len())
+	})
+
+	t.Run("expires entries after their time to live", func(t *testing.T) {
+		require.NoError(t, store.Set("a", []byte("A"), time.Minute))
+
+		now = now.Add(time.Minute)
+
+		assert.Empty(t, get("a"))
+	})
+
+	t.Run("deletes entries", func(t *testing.T) {
+		require.NoError(t, store.Set("d", []byte("D"), time.Minute))
+		require.NoError(t, store.Delete("d", "missing"))
+
+		assert.Empty(t, get("d"))
+	})
+}
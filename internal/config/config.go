@@ -0,0 +1,502 @@
+// Package config reads the service configuration from environment variables.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the runtime configuration of the service.
+type Config struct {
+	// AutoMigrate enables applying the embedded database migrations on startup.
+	AutoMigrate bool
+	// IPConflictMode is one of "off", "warn" or "enforce" and defines how duplicate IP addresses are handled.
+	IPConflictMode string
+	// IPConflictScopes restricts IP conflict detection to the given networks. Empty means all addresses.
+	IPConflictScopes []*net.IPNet
+	// IPAllocationTTL is the time after which an allocated IP address that has not been used is released. Zero
+	// keeps allocations until they are used or released explicitly.
+	IPAllocationTTL time.Duration
+	// WarrantyWindowDays is the number of days ahead within which ending warranties are reported.
+	WarrantyWindowDays int
+	// WarrantyCheckInterval is the time between two scheduled warranty checks.
+	WarrantyCheckInterval time.Duration
+	// EventsHeartbeatInterval is the time after which a comment is sent on an idle event stream.
+	EventsHeartbeatInterval time.Duration
+	// EventsRetention is the time after which a recorded computer event is deleted. Zero keeps the events forever.
+	EventsRetention time.Duration
+	// Database configures the connection to the PostgreSQL database.
+	Database DatabaseConfig
+	// Cache configures the cache of computer lookups.
+	Cache CacheConfig
+	// Notifications configures the channels notifications are sent to.
+	Notifications NotificationConfig
+}
+
+// CacheConfig holds the configuration of the in-memory cache of computer lookups.
+type CacheConfig struct {
+	// Enabled enables caching computers by ID and by employee.
+	Enabled bool
+	// TTL is the time after which a cached entry expires.
+	TTL time.Duration
+	// Size is the maximum number of cached entries.
+	Size int
+}
+
+// NotificationLevels are the levels of the notifications sent by the service.
+var NotificationLevels = []string{"warning", "resolved", "warranty"}
+
+// NotificationChannels are the names of the supported notification channels.
+var NotificationChannels = []string{"notify", "smtp", "chat", "syslog"}
+
+// NotificationConfig holds the notification channels and the selection of channels per notification level.
+type NotificationConfig struct {
+	// Cooldown is the time within which an employee is not warned again about the 3-computer threshold unless the
+	// number of their computers has increased.
+	Cooldown time.Duration
+	// Channels are the names of the channels notifications are sent to unless their level selects other ones.
+	Channels []string
+	// LevelChannels selects the channels per notification level.
+	LevelChannels map[string][]string
+	// Templates holds the text/template per channel name that renders the message of a notification. Channels
+	// without one use their default template.
+	Templates map[string]string
+	// TemplatesDir is the directory of the notification templates per locale and event type. Without one, the
+	// templates built into the binary are used.
+	TemplatesDir string
+	// Locale selects the language of the notification templates.
+	Locale string
+	// NotifyURL is the base URL of the notify service.
+	NotifyURL string
+	// SMTP configures the email channel.
+	SMTP SMTPConfig
+	// ChatWebhookURL is the incoming webhook URL of the chat channel.
+	ChatWebhookURL string
+	// Syslog configures the syslog channel.
+	Syslog SyslogConfig
+}
+
+// SMTPConfig holds the settings of the email channel.
+type SMTPConfig struct {
+	// Addr is the address of the SMTP server, e.g. "mail.example.com:587".
+	Addr string
+	// Username and Password are used for PLAIN authentication. Without username, no authentication is used.
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// SyslogConfig holds the settings of the syslog channel.
+type SyslogConfig struct {
+	// Network is either "udp" or "tcp".
+	Network string
+	Addr    string
+	// Tag is sent as the application name.
+	Tag string
+}
+
+// DatabaseConfig holds the connection settings and the pool configuration of the PostgreSQL database.
+type DatabaseConfig struct {
+	// URL is a complete connection string, either a postgres:// URL or key=value pairs. If set, the individual
+	// connection settings below are ignored.
+	URL      string
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Name     string
+	// SSLMode is one of the sslmode values of PostgreSQL, e.g. "disable" or "verify-full".
+	SSLMode string
+	// SSLRootCert is the path of the CA certificate used to verify the server certificate.
+	SSLRootCert string
+	// SSLCert and SSLKey are the paths of the client certificate and its key.
+	SSLCert string
+	SSLKey  string
+	// MaxOpenConns limits the number of open connections. Zero means unlimited.
+	MaxOpenConns int
+	// MaxIdleConns limits the number of idle connections kept in the pool.
+	MaxIdleConns int
+	// ConnMaxLifetime is the maximum time a connection is reused. Zero means forever.
+	ConnMaxLifetime time.Duration
+	// ConnectTimeout is the time within which the database has to become reachable on startup.
+	ConnectTimeout time.Duration
+	// ReplicaURL is the complete connection string of an optional read-only replica. The pool configuration
+	// applies to it as well.
+	ReplicaURL string
+}
+
+// Load reads the configuration from the environment, falling back to defaults for unset variables.
+func Load() (Config, error) {
+	autoMigrate, err := getEnvBool("AUTO_MIGRATE", true)
+	if err != nil {
+		return Config{}, err
+	}
+
+	ipConflictMode := getEnv("IP_CONFLICT_MODE", "warn")
+	switch ipConflictMode {
+	case "off", "warn", "enforce":
+	default:
+		return Config{}, fmt.Errorf("invalid value %q for IP_CONFLICT_MODE: must be one of off, warn, enforce", ipConflictMode)
+	}
+
+	ipConflictScopes, err := getEnvCIDRs("IP_CONFLICT_SCOPES")
+	if err != nil {
+		return Config{}, err
+	}
+
+	warrantyWindowDays, err := getEnvInt("WARRANTY_WINDOW_DAYS", 30)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if warrantyWindowDays < 0 {
+		return Config{}, fmt.Errorf("invalid value %d for WARRANTY_WINDOW_DAYS: must not be negative", warrantyWindowDays)
+	}
+
+	ipAllocationTTL, err := getEnvDuration("IP_ALLOCATION_TTL", 24*time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if ipAllocationTTL < 0 {
+		return Config{}, fmt.Errorf("invalid value %s for IP_ALLOCATION_TTL: must not be negative", ipAllocationTTL)
+	}
+
+	warrantyCheckInterval, err := getEnvDuration("WARRANTY_CHECK_INTERVAL", 24*time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if warrantyCheckInterval <= 0 {
+		return Config{}, fmt.Errorf("invalid value %s for WARRANTY_CHECK_INTERVAL: must be positive", warrantyCheckInterval)
+	}
+
+	eventsHeartbeatInterval, err := getEnvDuration("EVENTS_HEARTBEAT_INTERVAL", 15*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if eventsHeartbeatInterval <= 0 {
+		return Config{}, fmt.Errorf("invalid value %s for EVENTS_HEARTBEAT_INTERVAL: must be positive", eventsHeartbeatInterval)
+	}
+
+	eventsRetention, err := getEnvDuration("EVENTS_RETENTION", 7*24*time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if eventsRetention < 0 {
+		return Config{}, fmt.Errorf("invalid value %s for EVENTS_RETENTION: must not be negative", eventsRetention)
+	}
+
+	database, err := LoadDatabase()
+	if err != nil {
+		return Config{}, err
+	}
+
+	cacheEnabled, err := getEnvBool("CACHE_ENABLED", false)
+	if err != nil {
+		return Config{}, err
+	}
+
+	cacheTTL, err := getEnvDuration("CACHE_TTL", 30*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if cacheTTL <= 0 {
+		return Config{}, fmt.Errorf("invalid value %s for CACHE_TTL: must be positive", cacheTTL)
+	}
+
+	cacheSize, err := getEnvInt("CACHE_SIZE", 10000)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if cacheSize <= 0 {
+		return Config{}, fmt.Errorf("invalid value %d for CACHE_SIZE: must be positive", cacheSize)
+	}
+
+	notifications, err := loadNotifications()
+	if err != nil {
+		return Config{}, err
+	}
+
+	return Config{
+		AutoMigrate:             autoMigrate,
+		IPConflictMode:          ipConflictMode,
+		IPConflictScopes:        ipConflictScopes,
+		IPAllocationTTL:         ipAllocationTTL,
+		WarrantyWindowDays:      warrantyWindowDays,
+		WarrantyCheckInterval:   warrantyCheckInterval,
+		EventsHeartbeatInterval: eventsHeartbeatInterval,
+		EventsRetention:         eventsRetention,
+		Database:                database,
+		Cache: CacheConfig{
+			Enabled: cacheEnabled,
+			TTL:     cacheTTL,
+			Size:    cacheSize,
+		},
+		Notifications: notifications,
+	}, nil
+}
+
+// loadNotifications reads the notification configuration and checks that every selected channel is configured.
+func loadNotifications() (NotificationConfig, error) {
+	channels, err := getEnvChannels("NOTIFY_CHANNELS", []string{"notify"})
+	if err != nil {
+		return NotificationConfig{}, err
+	}
+
+	cooldown, err := getEnvDuration("NOTIFY_COOLDOWN", 24*time.Hour)
+	if err != nil {
+		return NotificationConfig{}, err
+	}
+
+	if cooldown < 0 {
+		return NotificationConfig{}, fmt.Errorf("invalid value %s for NOTIFY_COOLDOWN: must not be negative", cooldown)
+	}
+
+	selected := append([]string(nil), channels...)
+	levelChannels := make(map[string][]string)
+
+	for _, level := range NotificationLevels {
+		key := "NOTIFY_CHANNELS_" + strings.ToUpper(level)
+		if os.Getenv(key) == "" {
+			continue
+		}
+
+		levelChannels[level], err = getEnvChannels(key, nil)
+		if err != nil {
+			return NotificationConfig{}, err
+		}
+
+		selected = append(selected, levelChannels[level]...)
+	}
+
+	templates := make(map[string]string)
+
+	for _, channel := range NotificationChannels {
+		if template := os.Getenv("NOTIFY_TEMPLATE_" + strings.ToUpper(channel)); template != "" {
+			templates[channel] = template
+		}
+	}
+
+	cfg := NotificationConfig{
+		Cooldown:      cooldown,
+		Channels:      channels,
+		LevelChannels: levelChannels,
+		Templates:     templates,
+		TemplatesDir:  os.Getenv("NOTIFY_TEMPLATES_DIR"),
+		Locale:        getEnv("NOTIFY_LOCALE", "en"),
+		NotifyURL:     getEnv("NOTIFY_URL", "http://localhost:8080"),
+		SMTP: SMTPConfig{
+			Addr:     os.Getenv("NOTIFY_SMTP_ADDR"),
+			Username: os.Getenv("NOTIFY_SMTP_USERNAME"),
+			Password: os.Getenv("NOTIFY_SMTP_PASSWORD"),
+			From:     os.Getenv("NOTIFY_SMTP_FROM"),
+			To:       getEnvList("NOTIFY_SMTP_TO"),
+		},
+		ChatWebhookURL: os.Getenv("NOTIFY_CHAT_WEBHOOK_URL"),
+		Syslog: SyslogConfig{
+			Network: getEnv("NOTIFY_SYSLOG_NETWORK", "udp"),
+			Addr:    getEnv("NOTIFY_SYSLOG_ADDR", "localhost:514"),
+			Tag:     getEnv("NOTIFY_SYSLOG_TAG", "computers-management"),
+		},
+	}
+
+	for _, channel := range selected {
+		switch channel {
+		case "smtp":
+			if cfg.SMTP.Addr == "" || cfg.SMTP.From == "" || len(cfg.SMTP.To) == 0 {
+				return NotificationConfig{}, errors.New("the smtp notification channel requires NOTIFY_SMTP_ADDR, NOTIFY_SMTP_FROM and NOTIFY_SMTP_TO")
+			}
+		case "chat":
+			if cfg.ChatWebhookURL == "" {
+				return NotificationConfig{}, errors.New("the chat notification channel requires NOTIFY_CHAT_WEBHOOK_URL")
+			}
+		case "syslog":
+			if cfg.Syslog.Network != "udp" && cfg.Syslog.Network != "tcp" {
+				return NotificationConfig{}, fmt.Errorf("invalid value %q for NOTIFY_SYSLOG_NETWORK: must be one of udp, tcp", cfg.Syslog.Network)
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// LoadDatabase reads the database configuration from the environment, falling back to defaults that match the
+// database of the docker-compose file.
+func LoadDatabase() (DatabaseConfig, error) {
+	port, err := getEnvInt("DB_PORT", 5432)
+	if err != nil {
+		return DatabaseConfig{}, err
+	}
+
+	sslMode := getEnv("DB_SSLMODE", "disable")
+	switch sslMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		return DatabaseConfig{}, fmt.Errorf("invalid value %q for DB_SSLMODE: must be one of disable, allow, prefer, require, verify-ca, verify-full", sslMode)
+	}
+
+	maxOpenConns, err := getEnvInt("DB_MAX_OPEN_CONNS", 25)
+	if err != nil {
+		return DatabaseConfig{}, err
+	}
+
+	maxIdleConns, err := getEnvInt("DB_MAX_IDLE_CONNS", 5)
+	if err != nil {
+		return DatabaseConfig{}, err
+	}
+
+	if maxOpenConns < 0 || maxIdleConns < 0 {
+		return DatabaseConfig{}, fmt.Errorf("invalid values %d for DB_MAX_OPEN_CONNS and %d for DB_MAX_IDLE_CONNS: must not be negative", maxOpenConns, maxIdleConns)
+	}
+
+	connMaxLifetime, err := getEnvDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute)
+	if err != nil {
+		return DatabaseConfig{}, err
+	}
+
+	if connMaxLifetime < 0 {
+		return DatabaseConfig{}, fmt.Errorf("invalid value %s for DB_CONN_MAX_LIFETIME: must not be negative", connMaxLifetime)
+	}
+
+	connectTimeout, err := getEnvDuration("DB_CONNECT_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return DatabaseConfig{}, err
+	}
+
+	if connectTimeout <= 0 {
+		return DatabaseConfig{}, fmt.Errorf("invalid value %s for DB_CONNECT_TIMEOUT: must be positive", connectTimeout)
+	}
+
+	return DatabaseConfig{
+		URL:             os.Getenv("DB_URL"),
+		Host:            getEnv("DB_HOST", "localhost"),
+		Port:            port,
+		User:            getEnv("DB_USER", "postgres"),
+		Password:        getEnv("DB_PASSWORD", "mypassword"),
+		Name:            getEnv("DB_NAME", "computers"),
+		SSLMode:         sslMode,
+		SSLRootCert:     os.Getenv("DB_SSLROOTCERT"),
+		SSLCert:         os.Getenv("DB_SSLCERT"),
+		SSLKey:          os.Getenv("DB_SSLKEY"),
+		MaxOpenConns:    maxOpenConns,
+		MaxIdleConns:    maxIdleConns,
+		ConnMaxLifetime: connMaxLifetime,
+		ConnectTimeout:  connectTimeout,
+		ReplicaURL:      os.Getenv("DB_REPLICA_URL"),
+	}, nil
+}
+
+func getEnv(key, defaultValue string) string {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+func getEnvBool(key string, defaultValue bool) (bool, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return defaultValue, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value %q for %s: %w", value, key, err)
+	}
+
+	return b, nil
+}
+
+func getEnvInt(key string, defaultValue int) (int, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return defaultValue, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q for %s: %w", value, key, err)
+	}
+
+	return i, nil
+}
+
+// getEnvDuration parses a duration such as "24h" or "90m".
+func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return defaultValue, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q for %s: %w", value, key, err)
+	}
+
+	return d, nil
+}
+
+// getEnvList parses a comma-separated list, ignoring empty entries.
+func getEnvList(key string) []string {
+	var values []string
+
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// getEnvChannels parses a comma-separated list of notification channels, e.g. "notify,chat".
+func getEnvChannels(key string, defaultValue []string) ([]string, error) {
+	channels := getEnvList(key)
+	if len(channels) == 0 {
+		return defaultValue, nil
+	}
+
+	for _, channel := range channels {
+		if !slices.Contains(NotificationChannels, channel) {
+			return nil, fmt.Errorf("invalid value %q for %s: channels must be one of %s", os.Getenv(key), key,
+				strings.Join(NotificationChannels, ", "))
+		}
+	}
+
+	return channels, nil
+}
+
+// getEnvCIDRs parses a comma-separated list of networks in CIDR notation, e.g. "10.0.0.0/8,192.168.1.0/24".
+func getEnvCIDRs(key string) ([]*net.IPNet, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	var networks []*net.IPNet
+
+	for _, cidr := range strings.Split(value, ",") {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for %s: %w", value, key, err)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
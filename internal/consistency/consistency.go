@@ -0,0 +1,18 @@
+// Package consistency marks requests that have to see their own writes and therefore must not be answered
+// from a read replica that may lag behind the primary database.
+package consistency
+
+import "context"
+
+type readYourWritesKey struct{}
+
+// WithReadYourWrites returns a copy of ctx whose reads have to be served by the primary database.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readYourWritesKey{}, true)
+}
+
+// ReadYourWrites reports whether the reads of ctx have to be served by the primary database.
+func ReadYourWrites(ctx context.Context) bool {
+	readYourWrites, _ := ctx.Value(readYourWritesKey{}).(bool)
+	return readYourWrites
+}
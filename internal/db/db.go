@@ -1,28 +1,138 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
+	"time"
+	"uhuaha/computers-management/internal/config"
 
 	_ "github.com/lib/pq"
 )
 
-// NewConnection establishes a new connection to the PostgreSQL database.
+// The delay between two connection attempts on startup starts at initialRetryDelay and doubles after every
+// failed attempt up to maxRetryDelay.
+var (
+	initialRetryDelay = 500 * time.Millisecond
+	maxRetryDelay     = 5 * time.Second
+)
+
+// NewConnection establishes a new connection pool to the PostgreSQL database. Since the database may still be
+// starting, it is pinged repeatedly until it is reachable or cfg.ConnectTimeout has passed.
 // It returns a pointer to the sql.DB object or an error if the connection fails.
-func NewConnection() (*sql.DB, error) {
-	connStr := "host=localhost port=5432 user=postgres password=mypassword dbname=computers sslmode=disable"
+func NewConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
+	conn, err := open(connectionString(cfg), cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	conn, err := sql.Open("postgres", connStr)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
+	defer cancel()
+
+	if err := waitForDatabase(ctx, conn.PingContext); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	log.Println("Successfully connected to the database.")
+
+	return conn, nil
+}
+
+// NewReplicaConnection establishes a new connection pool to the read replica at cfg.ReplicaURL. Unlike
+// NewConnection it does not wait for the replica since the repository falls back to the primary database
+// while the replica is unreachable.
+func NewReplicaConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
+	conn, err := open(cfg.ReplicaURL, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open connection to DB: %w", err)
+		return nil, err
 	}
 
 	if err := conn.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		log.Printf("Read replica is not reachable yet: %v", err)
+	} else {
+		log.Println("Successfully connected to the read replica.")
 	}
 
-	log.Println("Successfully connected to the database.")
+	return conn, nil
+}
+
+// open opens a connection pool configured as given by cfg without connecting yet.
+func open(connStr string, cfg config.DatabaseConfig) (*sql.DB, error) {
+	conn, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open connection to DB: %w", err)
+	}
+
+	conn.SetMaxOpenConns(cfg.MaxOpenConns)
+	conn.SetMaxIdleConns(cfg.MaxIdleConns)
+	conn.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
 	return conn, nil
 }
+
+// waitForDatabase calls ping until it succeeds, waiting with exponential backoff in between.
+// It gives up once ctx is done and returns the last error of ping.
+func waitForDatabase(ctx context.Context, ping func(ctx context.Context) error) error {
+	delay := initialRetryDelay
+
+	for {
+		err := ping(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to ping database: %w", err)
+		}
+
+		log.Printf("Database is not reachable yet, retrying in %s: %v", delay, err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to ping database: %w", err)
+		case <-time.After(delay):
+		}
+
+		delay = min(2*delay, maxRetryDelay)
+	}
+}
+
+// connectionString returns cfg.URL if set. Otherwise it builds a key=value connection string from the individual
+// settings, omitting the empty ones.
+func connectionString(cfg config.DatabaseConfig) string {
+	if cfg.URL != "" {
+		return cfg.URL
+	}
+
+	settings := []struct {
+		key, value string
+	}{
+		{"host", cfg.Host},
+		{"port", strconv.Itoa(cfg.Port)},
+		{"user", cfg.User},
+		{"password", cfg.Password},
+		{"dbname", cfg.Name},
+		{"sslmode", cfg.SSLMode},
+		{"sslrootcert", cfg.SSLRootCert},
+		{"sslcert", cfg.SSLCert},
+		{"sslkey", cfg.SSLKey},
+	}
+
+	var pairs []string
+	for _, setting := range settings {
+		if setting.value != "" {
+			pairs = append(pairs, setting.key+"="+quoteValue(setting.value))
+		}
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// quoteValue quotes a value of a key=value connection string, escaping backslashes and single quotes.
+func quoteValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionString(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.DatabaseConfig
+		want string
+	}{
+		{
+			name: "URL takes precedence",
+			cfg: config.DatabaseConfig{
+				URL:  "postgres://app@db.example.com:5432/computers?sslmode=verify-full&sslrootcert=/etc/ssl/ca.pem",
+				Host: "localhost",
+			},
+			want: "postgres://app@db.example.com:5432/computers?sslmode=verify-full&sslrootcert=/etc/ssl/ca.pem",
+		},
+		{
+			name: "individual settings without certificates",
+			cfg: config.DatabaseConfig{
+				Host:     "localhost",
+				Port:     5432,
+				User:     "postgres",
+				Password: "mypassword",
+				Name:     "computers",
+				SSLMode:  "disable",
+			},
+			want: "host='localhost' port='5432' user='postgres' password='mypassword' dbname='computers' sslmode='disable'",
+		},
+		{
+			name: "verify-full with certificates and a password to be escaped",
+			cfg: config.DatabaseConfig{
+				Host:        "db.example.com",
+				Port:        5433,
+				User:        "app",
+				Password:    `it's a \secret`,
+				Name:        "computers",
+				SSLMode:     "verify-full",
+				SSLRootCert: "/etc/ssl/ca.pem",
+				SSLCert:     "/etc/ssl/client.pem",
+				SSLKey:      "/etc/ssl/client.key",
+			},
+			want: `host='db.example.com' port='5433' user='app' password='it\'s a \\secret' dbname='computers' ` +
+				`sslmode='verify-full' sslrootcert='/etc/ssl/ca.pem' sslcert='/etc/ssl/client.pem' sslkey='/etc/ssl/client.key'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, connectionString(tt.cfg))
+		})
+	}
+}
+
+func TestWaitForDatabase(t *testing.T) {
+	initialRetryDelay, maxRetryDelay = time.Millisecond, 2*time.Millisecond
+
+	errNotReady := errors.New("connection refused")
+
+	t.Run("retries until the database is reachable", func(t *testing.T) {
+		attempts := 0
+		ping := func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errNotReady
+			}
+
+			return nil
+		}
+
+		err := waitForDatabase(context.Background(), ping)
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("gives up after the timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := waitForDatabase(ctx, func(ctx context.Context) error { return errNotReady })
+		require.ErrorIs(t, err, errNotReady)
+	})
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"uhuaha/computers-management/migrations"
+
+	"github.com/bdlm/log"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// NewMigrator creates a migrator that applies the embedded migrations to the given database.
+// It holds a dedicated connection of the pool which is released by calling Close on the migrator.
+func NewMigrator(dbConn *sql.DB) (*migrate.Migrate, error) {
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedded migrations: %w", err)
+	}
+
+	conn, err := dbConn.Conn(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DB connection for migrations: %w", err)
+	}
+
+	// WithConnection is used instead of WithInstance because closing the driver would otherwise
+	// close the shared *sql.DB as well.
+	driver, err := postgres.WithConnection(context.Background(), conn, &postgres.Config{})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	migrator, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+
+	return migrator, nil
+}
+
+// RunMigrations applies all pending embedded migrations. It refuses to do so if the schema is dirty.
+func RunMigrations(dbConn *sql.DB) error {
+	migrator, err := NewMigrator(dbConn)
+	if err != nil {
+		return err
+	}
+
+	defer closeMigrator(migrator)
+
+	if err := ensureClean(migrator); err != nil {
+		return err
+	}
+
+	if err := migrator.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	return nil
+}
+
+// CheckSchema returns an error if the schema was left dirty by a previously failed migration.
+func CheckSchema(dbConn *sql.DB) error {
+	migrator, err := NewMigrator(dbConn)
+	if err != nil {
+		return err
+	}
+
+	defer closeMigrator(migrator)
+
+	return ensureClean(migrator)
+}
+
+func ensureClean(migrator *migrate.Migrate) error {
+	version, dirty, err := migrator.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	if dirty {
+		return fmt.Errorf("schema is dirty at version %d: fix it manually and run 'migrate force <version>'", version)
+	}
+
+	return nil
+}
+
+func closeMigrator(migrator *migrate.Migrate) {
+	if srcErr, dbErr := migrator.Close(); srcErr != nil || dbErr != nil {
+		log.Errorf("failed to close migrator: source: %v, database: %v", srcErr, dbErr)
+	}
+}
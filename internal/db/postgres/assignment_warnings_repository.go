@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ClaimAssignmentWarning records that the employee is warned about count actively assigned computers. It reports
+// false without changing anything if the employee has already been warned about at least count computers within
+// the cool-down, so that concurrent or repeated evaluations send a warning at most once.
+func (r *Repository) ClaimAssignmentWarning(employee string, count int, cooldown time.Duration) (bool, error) {
+	stmt, err := r.prepare(`
+		INSERT INTO assignment_warnings (employee_abbreviation, computer_count)
+		VALUES ($1, $2)
+		ON CONFLICT (employee_abbreviation) DO UPDATE
+		SET computer_count = EXCLUDED.computer_count, notified_at = now()
+		WHERE assignment_warnings.computer_count < EXCLUDED.computer_count
+			OR assignment_warnings.notified_at <= now() - make_interval(secs => $3)
+		RETURNING employee_abbreviation;`)
+	if err != nil {
+		return false, dbError("failed to prepare claim statement", err)
+	}
+
+	var claimed string
+
+	err = stmt.QueryRow(employee, count, cooldown.Seconds()).Scan(&claimed)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, dbError("failed to claim assignment warning", err)
+	}
+
+	return true, nil
+}
+
+// ReleaseAssignmentWarning removes the warning of the employee if it still is the one claimed about count computers,
+// so that the next call of ClaimAssignmentWarning claims it again. A warning claimed about more computers in the
+// meantime is kept.
+func (r *Repository) ReleaseAssignmentWarning(employee string, count int) error {
+	stmt, err := r.prepare(`DELETE FROM assignment_warnings WHERE employee_abbreviation = $1 AND computer_count = $2;`)
+	if err != nil {
+		return dbError("failed to prepare delete statement", err)
+	}
+
+	if _, err := stmt.Exec(employee, count); err != nil {
+		return dbError("failed to release assignment warning", err)
+	}
+
+	return nil
+}
+
+// ResolveAssignmentWarning removes the warning of the employee. It reports whether the employee had been warned.
+func (r *Repository) ResolveAssignmentWarning(employee string) (bool, error) {
+	stmt, err := r.prepare(`DELETE FROM assignment_warnings WHERE employee_abbreviation = $1 RETURNING employee_abbreviation;`)
+	if err != nil {
+		return false, dbError("failed to prepare resolve statement", err)
+	}
+
+	var resolved string
+
+	err = stmt.QueryRow(employee).Scan(&resolved)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, dbError("failed to resolve assignment warning", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"database/sql"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/errors"
+)
+
+// CheckOutComputer assigns a computer to assignment.EmployeeAbbreviation, changes its status as described by the
+// transition and starts a new assignment, all within one transaction. It returns a conflict error if the computer's
+// status is no longer transition.FromStatus. The started assignment is returned.
+func (r *Repository) CheckOutComputer(assignment dbo.Assignment, transition dbo.StatusTransition) (dbo.Assignment, error) {
+	tx, err := r.begin()
+	if err != nil {
+		return dbo.Assignment{}, dbError("failed to begin transaction", err)
+	}
+
+	defer tx.Rollback() // no-op after a successful commit
+
+	err = tx.QueryRow(`
+		UPDATE computers SET employee_abbreviation = $1, status = $2
+		WHERE id = $3 AND status = $4
+		RETURNING name;`,
+		assignment.EmployeeAbbreviation, transition.ToStatus, assignment.ComputerID, transition.FromStatus,
+	).Scan(&assignment.ComputerName)
+	if err == sql.ErrNoRows {
+		return dbo.Assignment{}, errors.NewConflict("status of the computer has been changed concurrently")
+	} else if err != nil {
+		return dbo.Assignment{}, dbError("failed to update computer", err)
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO assignments (computer_id, employee_abbreviation)
+		VALUES ($1, $2)
+		RETURNING id, started_at;`,
+		assignment.ComputerID, assignment.EmployeeAbbreviation,
+	).Scan(&assignment.ID, &assignment.StartedAt)
+	if err != nil {
+		return dbo.Assignment{}, dbError("failed to insert assignment", err)
+	}
+
+	if _, err := insertStatusTransition(tx.Tx, transition); err != nil {
+		return dbo.Assignment{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbo.Assignment{}, dbError("failed to commit transaction", err)
+	}
+
+	return assignment, nil
+}
+
+// CheckInComputer ends the active assignment of a computer, removes its employee and changes its status as described
+// by the transition, all within one transaction. It returns a conflict error if the computer's status is no longer
+// transition.FromStatus or if it has no active assignment. The ended assignment is returned.
+func (r *Repository) CheckInComputer(transition dbo.StatusTransition) (dbo.Assignment, error) {
+	tx, err := r.begin()
+	if err != nil {
+		return dbo.Assignment{}, dbError("failed to begin transaction", err)
+	}
+
+	defer tx.Rollback() // no-op after a successful commit
+
+	var computerName string
+	err = tx.QueryRow(`
+		UPDATE computers SET employee_abbreviation = NULL, status = $1
+		WHERE id = $2 AND status = $3
+		RETURNING name;`,
+		transition.ToStatus, transition.ComputerID, transition.FromStatus,
+	).Scan(&computerName)
+	if err == sql.ErrNoRows {
+		return dbo.Assignment{}, errors.NewConflict("status of the computer has been changed concurrently")
+	} else if err != nil {
+		return dbo.Assignment{}, dbError("failed to update computer", err)
+	}
+
+	assignment := dbo.Assignment{ComputerName: computerName}
+	err = tx.QueryRow(`
+		UPDATE assignments SET ended_at = now()
+		WHERE computer_id = $1 AND ended_at IS NULL
+		RETURNING id, computer_id, employee_abbreviation, started_at, ended_at;`,
+		transition.ComputerID,
+	).Scan(&assignment.ID, &assignment.ComputerID, &assignment.EmployeeAbbreviation, &assignment.StartedAt, &assignment.EndedAt)
+	if err == sql.ErrNoRows {
+		return dbo.Assignment{}, errors.NewConflict("the computer has no active assignment")
+	} else if err != nil {
+		return dbo.Assignment{}, dbError("failed to end active assignment", err)
+	}
+
+	if _, err := insertStatusTransition(tx.Tx, transition); err != nil {
+		return dbo.Assignment{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbo.Assignment{}, dbError("failed to commit transaction", err)
+	}
+
+	return assignment, nil
+}
+
+// GetAssignmentsByEmployee retrieves the current and past assignments of an employee ordered from newest to oldest.
+// Assignments of deleted computers have the computer ID 0 and the name the computer had when it was deleted.
+func (r *Repository) GetAssignmentsByEmployee(employee string) ([]dbo.Assignment, error) {
+	stmt, err := r.prepare(`
+		SELECT a.id, coalesce(a.computer_id, 0), coalesce(c.name, a.computer_name), a.employee_abbreviation,
+			a.started_at, a.ended_at
+		FROM assignments a
+		LEFT JOIN computers c ON c.id = a.computer_id
+		WHERE a.employee_abbreviation = $1
+		ORDER BY a.started_at DESC, a.id DESC
+		LIMIT 100;`)
+	if err != nil {
+		return nil, dbError("failed to prepare select statement", err)
+	}
+
+	rows, err := stmt.Query(employee)
+	if err != nil {
+		return nil, dbError("failed to query assignments for an employee", err)
+	}
+	defer rows.Close()
+
+	var assignments []dbo.Assignment
+
+	for rows.Next() {
+		var a dbo.Assignment
+
+		if err := rows.Scan(&a.ID, &a.ComputerID, &a.ComputerName, &a.EmployeeAbbreviation, &a.StartedAt, &a.EndedAt); err != nil {
+			return nil, dbError("failed to scan row", err)
+		}
+
+		assignments = append(assignments, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to iterate rows", err)
+	}
+
+	return assignments, nil
+}
+
+// reassignComputer ends the active assignment of a computer within the given transaction and starts a new one
+// if employee is set.
+func reassignComputer(tx *sql.Tx, computerID int, employee sql.NullString) error {
+	if _, err := tx.Exec(`UPDATE assignments SET ended_at = now() WHERE computer_id = $1 AND ended_at IS NULL;`, computerID); err != nil {
+		return dbError("failed to end active assignment", err)
+	}
+
+	if !employee.Valid {
+		return nil
+	}
+
+	if _, err := tx.Exec(`INSERT INTO assignments (computer_id, employee_abbreviation) VALUES ($1, $2);`, computerID, employee); err != nil {
+		return dbError("failed to insert assignment", err)
+	}
+
+	return nil
+}
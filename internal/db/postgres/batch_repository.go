@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"fmt"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/errors"
+
+	"github.com/lib/pq"
+)
+
+// GetComputersBySelector retrieves all computers with one of the selected IDs or, if no IDs are given,
+// the computers matching the selector's filter. Unlike GetAllComputers the result is only limited by the
+// selector's Limit.
+func (r *Repository) GetComputersBySelector(selector dbo.ComputerSelector) ([]dbo.Computer, error) {
+	var (
+		where string
+		args  []any
+	)
+
+	switch {
+	case len(selector.IDs) > 0:
+		where, args = ` WHERE id = ANY($1)`, []any{pq.Array(selector.IDs)}
+	case selector.Filter != nil:
+		where, args = buildComputerFilter(*selector.Filter)
+	}
+
+	limit := ""
+	if selector.Limit > 0 && len(selector.IDs) == 0 {
+		args = append(args, selector.Limit)
+		limit = fmt.Sprintf(` LIMIT $%d`, len(args))
+	}
+
+	stmt, err := r.prepare(`SELECT ` + computerColumns + ` FROM computers` + where + ` ORDER BY id` + limit + `;`)
+	if err != nil {
+		return nil, dbError("failed to prepare select statement", err)
+	}
+
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return nil, dbError("failed to query computers", err)
+	}
+	defer rows.Close()
+
+	return scanComputers(rows)
+}
+
+// UpdateComputers sets the valid fields of changes on all given computers within one transaction. It returns
+// a conflict error and changes nothing if one of the computers has been deleted in the meantime.
+func (r *Repository) UpdateComputers(computerIDs []int, changes dbo.ComputerChanges) error {
+	tx, err := r.begin()
+	if err != nil {
+		return dbError("failed to begin transaction", err)
+	}
+
+	defer tx.Rollback() // no-op after a successful commit
+
+	result, err := tx.Exec(`
+		UPDATE computers
+		SET description = coalesce($1, description),
+			vendor = coalesce($2, vendor),
+			model = coalesce($3, model),
+			operating_system = coalesce($4, operating_system),
+			location = coalesce($5, location)
+		WHERE id = ANY($6);`,
+		changes.Description, changes.Vendor, changes.Model, changes.OperatingSystem, changes.Location,
+		pq.Array(computerIDs),
+	)
+	if err != nil {
+		return dbError("failed to execute update statement", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError("failed to get affected rows", err)
+	} else if n != int64(len(computerIDs)) {
+		return errors.NewConflict("the selected computers have been changed concurrently")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbError("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// DeleteComputers removes all given computers from the database. It returns a conflict error and deletes nothing
+// if one of the computers has been deleted in the meantime.
+func (r *Repository) DeleteComputers(computerIDs []int) error {
+	tx, err := r.begin()
+	if err != nil {
+		return dbError("failed to begin transaction", err)
+	}
+
+	defer tx.Rollback() // no-op after a successful commit
+
+	result, err := tx.Exec(`DELETE FROM computers WHERE id = ANY($1);`, pq.Array(computerIDs))
+	if err != nil {
+		return dbError("failed to execute delete statement", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError("failed to get affected rows", err)
+	} else if n != int64(len(computerIDs)) {
+		return errors.NewConflict("the selected computers have been changed concurrently")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbError("failed to commit transaction", err)
+	}
+
+	return nil
+}
@@ -2,41 +2,103 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"uhuaha/computers-management/internal/db/postgres/dbo"
 	"uhuaha/computers-management/internal/errors"
+
+	"github.com/lib/pq"
 )
 
+// computerColumns lists the selected columns of the computers table in the order expected by scanComputer.
+// The IP and MAC address are the ones of the primary network interface in their canonical text representation.
+// The subnet is the one containing the IP address.
+const computerColumns = `id, name,
+	coalesce((SELECT host(ip_address) FROM network_interfaces WHERE computer_id = computers.id AND is_primary), '') AS ip_address,
+	coalesce((SELECT upper(mac_address::TEXT) FROM network_interfaces WHERE computer_id = computers.id AND is_primary), '') AS mac_address,
+	employee_abbreviation, description,
+	(SELECT subnets.id FROM subnets JOIN network_interfaces ON network_interfaces.ip_address <<= subnets.cidr
+		WHERE network_interfaces.computer_id = computers.id AND network_interfaces.is_primary) AS subnet_id,
+	status, serial_number, purchase_date, warranty_end, vendor, model, operating_system, location`
+
+// primaryInterfaceName is the name of the interface created for the addresses a computer is added with.
+const primaryInterfaceName = "primary"
+
+// likeEscaper escapes the wildcard characters of a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Repository stores computers and subnets in a PostgreSQL database. A repository passed to the callback of
+// WithTx runs all its queries within the same transaction.
 type Repository struct {
-	dbConn *sql.DB
+	db      *sql.DB
+	dbConn  dbtx
+	tx      *sql.Tx
+	stmts   *statementCache
+	replica *replica
 }
 
-func NewRepository(dbConn *sql.DB) *Repository {
-	return &Repository{
+// NewRepository returns a repository that prepares each of its statements once and reuses it. Close releases
+// the prepared statements.
+func NewRepository(dbConn *sql.DB, opts ...RepositoryOption) *Repository {
+	r := &Repository{
+		db:     dbConn,
 		dbConn: dbConn,
+		stmts:  newStatementCache(dbConn),
+	}
+
+	for _, opt := range opts {
+		opt(r)
 	}
+
+	return r
 }
 
-// AddComputer inserts a new computer into the database and returns its generated ID,
-// or an error if the insertion fails.
+// AddComputer inserts a new computer together with its primary network interface and, if it has an employee,
+// its active assignment into the database and returns its generated ID. It returns a conflict error if the
+// MAC address is already used by another network interface.
 func (r *Repository) AddComputer(computer dbo.Computer) (int, error) {
-	query := `
-		INSERT INTO computers (name, ip_address, mac_address, employee_abbreviation, description)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id;`
-
-	stmt, err := r.dbConn.Prepare(query)
+	tx, err := r.begin()
 	if err != nil {
-		return 0, fmt.Errorf("failed to prepare insert statement: %w", err)
+		return 0, dbError("failed to begin transaction", err)
 	}
 
-	defer stmt.Close()
+	defer tx.Rollback() // no-op after a successful commit
 
 	var computerID int
-	err = stmt.QueryRow(computer.Name, computer.IPAddress, computer.MACAddress, computer.EmployeeAbbreviation, computer.Description).Scan(&computerID)
+	err = tx.QueryRow(`
+		INSERT INTO computers (name, employee_abbreviation, description,
+			serial_number, purchase_date, warranty_end, vendor, model, operating_system, location, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id;`,
+		computer.Name, computer.EmployeeAbbreviation, computer.Description,
+		computer.SerialNumber, computer.PurchaseDate, computer.WarrantyEnd, computer.Vendor, computer.Model,
+		computer.OperatingSystem, computer.Location, computer.Status,
+	).Scan(&computerID)
 	if err != nil {
-		return 0, fmt.Errorf("failed to insert computer: %w", err)
+		return 0, dbError("failed to insert computer", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO network_interfaces (computer_id, name, mac_address, ip_address, is_primary)
+		VALUES ($1, $2, $3, $4, true);`,
+		computerID, primaryInterfaceName, computer.MACAddress, computer.IPAddress,
+	)
+	if isConstraintViolation(err, uniqueViolation) {
+		return 0, errors.NewConflict(fmt.Sprintf("MAC address %s is already used by another network interface", computer.MACAddress))
+	} else if err != nil {
+		return 0, dbError("failed to insert primary network interface", err)
+	}
+
+	if computer.EmployeeAbbreviation.Valid {
+		if err := reassignComputer(tx.Tx, computerID, computer.EmployeeAbbreviation); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, dbError("failed to commit transaction", err)
 	}
 
 	return computerID, nil
@@ -45,150 +107,416 @@ func (r *Repository) AddComputer(computer dbo.Computer) (int, error) {
 // GetComputer retrieves a computer by its ID from the database.
 // It returns the computer or an error if the record is not found or the query fails.
 func (r *Repository) GetComputer(computerID int) (dbo.Computer, error) {
-	stmt, err := r.dbConn.Prepare(`SELECT * FROM computers WHERE id = $1;`)
+	stmt, err := r.prepare(`SELECT ` + computerColumns + ` FROM computers WHERE id = $1;`)
 	if err != nil {
-		return dbo.Computer{}, fmt.Errorf("failed to prepare select statement: %w", err)
+		return dbo.Computer{}, dbError("failed to prepare select statement", err)
 	}
 
-	defer stmt.Close()
-
-	var computerDBO dbo.Computer
-	err = stmt.QueryRow(computerID).Scan(
-		&computerDBO.ID,
-		&computerDBO.Name,
-		&computerDBO.IPAddress,
-		&computerDBO.MACAddress,
-		&computerDBO.EmployeeAbbreviation,
-		&computerDBO.Description,
-	)
+	computerDBO, err := scanComputer(stmt.QueryRow(computerID))
 	if err == sql.ErrNoRows {
 		return dbo.Computer{}, errors.NewNotFound("computer not found")
 	} else if err != nil {
-		return dbo.Computer{}, fmt.Errorf("failed to query computer: %w", err)
+		return dbo.Computer{}, dbError("failed to query computer", err)
 	}
 
 	return computerDBO, nil
 }
 
-// GetAllComputers retrieves all computers from the database. The number of records returned is limited to 100.
-// It returns a list of computers or an error if the query fails.
-func (r *Repository) GetAllComputers() ([]dbo.Computer, error) {
-	stmt, err := r.dbConn.Prepare(`SELECT * FROM computers LIMIT 100;`)
+// GetAllComputers retrieves all computers matching the given filter from the database, preferably from the replica.
+// The number of records returned is limited to 100. It returns a list of computers or an error if the query fails.
+func (r *Repository) GetAllComputers(ctx context.Context, filter dbo.ComputerFilter) ([]dbo.Computer, error) {
+	where, args := buildComputerFilter(filter)
+
+	rows, err := r.queryRead(ctx, `SELECT `+computerColumns+` FROM computers`+where+` ORDER BY id LIMIT 100;`, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to prepare select statement: %w", err)
+		return nil, dbError("failed to query computers", err)
 	}
+	defer rows.Close()
 
-	defer stmt.Close()
+	return scanComputers(rows)
+}
 
-	rows, err := stmt.Query()
+// GetComputersVersion retrieves the version of the computers, preferably from the replica. Within WithSnapshot, it
+// matches the computers read from the same snapshot. The version is incremented by every change of the computers,
+// their network interfaces and the subnets.
+func (r *Repository) GetComputersVersion(ctx context.Context) (dbo.ComputersVersion, error) {
+	rows, err := r.queryRead(ctx, `SELECT version, changed_at FROM computers_version;`)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query computers: %w", err)
+		return dbo.ComputersVersion{}, dbError("failed to query computers version", err)
 	}
 	defer rows.Close()
 
-	var computerDBOs []dbo.Computer
+	var version dbo.ComputersVersion
 
-	for rows.Next() {
-		var c dbo.Computer
-
-		if err := rows.Scan(
-			&c.ID,
-			&c.Name,
-			&c.IPAddress,
-			&c.MACAddress,
-			&c.EmployeeAbbreviation,
-			&c.Description,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return dbo.ComputersVersion{}, dbError("failed to iterate rows", err)
 		}
 
-		computerDBOs = append(computerDBOs, c)
+		return dbo.ComputersVersion{}, errors.NewNotFound("computers version not found")
+	}
+
+	if err := rows.Scan(&version.Version, &version.ChangedAt); err != nil {
+		return dbo.ComputersVersion{}, dbError("failed to scan row", err)
+	}
+
+	return version, nil
+}
+
+// ComputerExists reports whether the computer with the given ID exists, preferably reading from the replica.
+func (r *Repository) ComputerExists(ctx context.Context, computerID int) (bool, error) {
+	rows, err := r.queryRead(ctx, `SELECT EXISTS (SELECT 1 FROM computers WHERE id = $1);`, computerID)
+	if err != nil {
+		return false, dbError("failed to query computer", err)
+	}
+	defer rows.Close()
+
+	var exists bool
+
+	if rows.Next() {
+		if err := rows.Scan(&exists); err != nil {
+			return false, dbError("failed to scan row", err)
+		}
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+		return false, dbError("failed to iterate rows", err)
 	}
 
-	return computerDBOs, nil
+	return exists, nil
 }
 
-// UpdateComputer updates an existing computer's details in the database. It returns an error if the update fails.
+// UpdateComputer updates an existing computer's details and the addresses of its primary network interface in the
+// database. The employee and the status are left untouched since they are only changed by AddStatusTransition,
+// CheckOutComputer and CheckInComputer. It returns a not found error if the computer does not exist and a conflict
+// error if the MAC address is already used by another network interface.
 func (r *Repository) UpdateComputer(computerID int, data dbo.Computer) error {
-	stmt, err := r.dbConn.Prepare(`
-		UPDATE computers 
-		SET name = $1, ip_address = $2, mac_address = $3, employee_abbreviation = $4, description = $5
-		WHERE id = $6;
-	`)
+	tx, err := r.begin()
 	if err != nil {
-		return fmt.Errorf("failed to prepare update statement: %w", err)
+		return dbError("failed to begin transaction", err)
 	}
 
-	defer stmt.Close()
+	defer tx.Rollback() // no-op after a successful commit
+
+	result, err := tx.Exec(`
+		UPDATE computers
+		SET name = $1, description = $2,
+			serial_number = $3, purchase_date = $4, warranty_end = $5, vendor = $6, model = $7,
+			operating_system = $8, location = $9
+		WHERE id = $10;`,
+		data.Name, data.Description,
+		data.SerialNumber, data.PurchaseDate, data.WarrantyEnd, data.Vendor, data.Model,
+		data.OperatingSystem, data.Location, computerID,
+	)
+	if err != nil {
+		return dbError("failed to execute update statement", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError("failed to get affected rows", err)
+	} else if n == 0 {
+		return errors.NewNotFound("computer not found")
+	}
+
+	_, err = tx.Exec(`
+		UPDATE network_interfaces
+		SET mac_address = $1, ip_address = $2
+		WHERE computer_id = $3 AND is_primary;`,
+		data.MACAddress, data.IPAddress, computerID,
+	)
+	if isConstraintViolation(err, uniqueViolation) {
+		return errors.NewConflict(fmt.Sprintf("MAC address %s is already used by another network interface", data.MACAddress))
+	} else if err != nil {
+		return dbError("failed to update primary network interface", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbError("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// GetComputersByEmployee retrieves all computers associated with a specific employee abbreviation, preferably from
+// the replica. It returns a list of computers or an error if the query fails.
+func (r *Repository) GetComputersByEmployee(ctx context.Context, employee string) ([]dbo.Computer, error) {
+	rows, err := r.queryRead(ctx, `SELECT `+computerColumns+` FROM computers WHERE employee_abbreviation = $1 ORDER BY id LIMIT 100;`, employee)
+	if err != nil {
+		return nil, dbError("failed to query computers for an employee", err)
+	}
+	defer rows.Close()
+
+	return scanComputers(rows)
+}
+
+// CountComputersByEmployee returns the number of computers actively assigned to a specific employee abbreviation.
+// Past assignments are not counted.
+func (r *Repository) CountComputersByEmployee(employee string) (int, error) {
+	stmt, err := r.prepare(`SELECT count(*) FROM assignments WHERE employee_abbreviation = $1 AND ended_at IS NULL;`)
+	if err != nil {
+		return 0, dbError("failed to prepare count statement", err)
+	}
+
+	var count int
+	if err := stmt.QueryRow(employee).Scan(&count); err != nil {
+		return 0, dbError("failed to count computers for an employee", err)
+	}
+
+	return count, nil
+}
+
+// DeleteComputer removes a computer from the database by its ID.
+// It returns an error if the deletion fails.
+func (r *Repository) DeleteComputer(computerID int) error {
+	stmt, err := r.prepare(`DELETE FROM computers WHERE id = $1;`)
+	if err != nil {
+		return dbError("failed to prepare delete statement", err)
+	}
+
+	_, err = stmt.Exec(computerID)
+	if err != nil {
+		return dbError("failed to execute delete statement", err)
+	}
+
+	return nil
+}
 
-	_, err = stmt.Exec(data.Name, data.IPAddress, data.MACAddress, data.EmployeeAbbreviation, data.Description, computerID)
+// LockIPAddress takes a transaction-level advisory lock on the given IP address, so that concurrent transactions
+// checking and storing the same address are serialized. The lock is released when the surrounding transaction
+// of WithTx ends; outside of it, the lock is released immediately.
+func (r *Repository) LockIPAddress(ipAddress string) error {
+	stmt, err := r.prepare(`SELECT pg_advisory_xact_lock($1, hashtext(host($2::inet)));`)
 	if err != nil {
-		return fmt.Errorf("failed to execute update statement: %w", err)
+		return dbError("failed to prepare lock statement", err)
+	}
+
+	if _, err := stmt.Exec(ipAddressLockClass, ipAddress); err != nil {
+		return dbError("failed to lock IP address", err)
 	}
 
 	return nil
 }
 
-// GetComputersByEmployee retrieves all computers associated with a specific employee abbreviation.
+// GetComputersByIPAddress retrieves all computers that use the given IP address on any of their network interfaces.
 // It returns a list of computers or an error if the query fails.
-func (r *Repository) GetComputersByEmployee(employee string) ([]dbo.Computer, error) {
-	stmt, err := r.dbConn.Prepare(`SELECT * FROM computers WHERE employee_abbreviation = $1 LIMIT 100;`)
+func (r *Repository) GetComputersByIPAddress(ipAddress string) ([]dbo.Computer, error) {
+	stmt, err := r.prepare(`
+		SELECT ` + computerColumns + ` FROM computers
+		WHERE id IN (SELECT computer_id FROM network_interfaces WHERE ip_address = $1)
+		ORDER BY id;`)
+	if err != nil {
+		return nil, dbError("failed to prepare select statement", err)
+	}
+
+	rows, err := stmt.Query(ipAddress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to prepare select statement: %w", err)
+		return nil, dbError("failed to query computers by IP address", err)
 	}
+	defer rows.Close()
 
-	defer stmt.Close()
+	return scanComputers(rows)
+}
+
+// GetComputersWithDuplicateIPAddress retrieves every IP address that is used by the network interfaces of at least two
+// computers together with these computers. The results are ordered by IP address so that computers sharing an address
+// are adjacent.
+func (r *Repository) GetComputersWithDuplicateIPAddress() ([]dbo.IPAddressUsage, error) {
+	stmt, err := r.prepare(`
+		SELECT ` + computerColumns + `, host(duplicates.ip_address)
+		FROM computers JOIN (
+			SELECT DISTINCT computer_id, ip_address FROM network_interfaces
+			WHERE ip_address IN (
+				SELECT ip_address FROM network_interfaces
+				GROUP BY ip_address HAVING count(DISTINCT computer_id) > 1
+			)
+		) AS duplicates ON duplicates.computer_id = computers.id
+		ORDER BY duplicates.ip_address, computers.id;
+	`)
+	if err != nil {
+		return nil, dbError("failed to prepare select statement", err)
+	}
 
-	rows, err := stmt.Query(employee)
+	rows, err := stmt.Query()
 	if err != nil {
-		return nil, fmt.Errorf("failed to query computers for an employee: %w", err)
+		return nil, dbError("failed to query computers with duplicate IP addresses", err)
 	}
 	defer rows.Close()
 
-	var computerDBOs []dbo.Computer
+	var usages []dbo.IPAddressUsage
 
 	for rows.Next() {
-		var c dbo.Computer
-
-		if err := rows.Scan(
-			&c.ID,
-			&c.Name,
-			&c.IPAddress,
-			&c.MACAddress,
-			&c.EmployeeAbbreviation,
-			&c.Description,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+		var ipAddress string
+
+		c, err := scanComputer(rows, &ipAddress)
+		if err != nil {
+			return nil, dbError("failed to scan row", err)
 		}
 
-		computerDBOs = append(computerDBOs, c)
+		usages = append(usages, dbo.IPAddressUsage{IPAddress: ipAddress, Computer: c})
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+		return nil, dbError("failed to iterate rows", err)
 	}
 
-	return computerDBOs, nil
+	return usages, nil
 }
 
-// DeleteComputer removes a computer from the database by its ID.
-// It returns an error if the deletion fails.
-func (r *Repository) DeleteComputer(computerID int) error {
-	stmt, err := r.dbConn.Prepare(`DELETE FROM computers WHERE id = $1;`)
+// SearchComputers retrieves the computers whose name, description, employee abbreviation or the MAC address of any of
+// their network interfaces match the given query, either as whole words, as a substring or by trigram similarity. Every
+// result includes the MAC addresses of all interfaces of the computer. The results are ordered by descending relevance
+// and limited to 100 records. The search is preferably executed on the replica.
+func (r *Repository) SearchComputers(ctx context.Context, query string) ([]dbo.ComputerSearchResult, error) {
+	rows, err := r.queryRead(ctx, `
+		SELECT `+computerColumns+`,
+			ts_rank(search_vector, plainto_tsquery('simple', $1)) + greatest(
+				similarity(name, $1),
+				similarity(coalesce(description, ''), $1),
+				similarity(coalesce(employee_abbreviation, ''), $1),
+				coalesce((SELECT max(similarity(mac_address::TEXT, $1)) FROM network_interfaces WHERE computer_id = computers.id), 0)
+			) AS rank,
+			ARRAY(SELECT upper(mac_address::TEXT) FROM network_interfaces WHERE computer_id = computers.id
+				ORDER BY is_primary DESC, id) AS mac_addresses
+		FROM computers
+		WHERE search_vector @@ plainto_tsquery('simple', $1)
+			OR name ILIKE $2 OR description ILIKE $2 OR employee_abbreviation ILIKE $2
+			OR EXISTS (SELECT 1 FROM network_interfaces WHERE computer_id = computers.id AND mac_address::TEXT ILIKE $2)
+			OR name % $1 OR description % $1
+		ORDER BY rank DESC, id
+		LIMIT 100;
+	`, query, "%"+likeEscaper.Replace(query)+"%")
 	if err != nil {
-		return fmt.Errorf("failed to prepare delete statement: %w", err)
+		return nil, dbError("failed to search computers", err)
 	}
+	defer rows.Close()
 
-	defer stmt.Close()
+	var results []dbo.ComputerSearchResult
 
-	_, err = stmt.Exec(computerID)
-	if err != nil {
-		return fmt.Errorf("failed to execute delete statement: %w", err)
+	for rows.Next() {
+		var (
+			rank         float64
+			macAddresses []string
+		)
+
+		c, err := scanComputer(rows, &rank, pq.Array(&macAddresses))
+		if err != nil {
+			return nil, dbError("failed to scan row", err)
+		}
+
+		results = append(results, dbo.ComputerSearchResult{Computer: c, Rank: rank, MACAddresses: macAddresses})
 	}
 
-	return nil
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to iterate rows", err)
+	}
+
+	return results, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanComputer scans a row selected with computerColumns into a computer DBO.
+// Additional columns selected after computerColumns are scanned into extra.
+func scanComputer(row rowScanner, extra ...any) (dbo.Computer, error) {
+	var c dbo.Computer
+
+	dest := []any{
+		&c.ID,
+		&c.Name,
+		&c.IPAddress,
+		&c.MACAddress,
+		&c.EmployeeAbbreviation,
+		&c.Description,
+		&c.SubnetID,
+		&c.Status,
+		&c.SerialNumber,
+		&c.PurchaseDate,
+		&c.WarrantyEnd,
+		&c.Vendor,
+		&c.Model,
+		&c.OperatingSystem,
+		&c.Location,
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+
+	return c, err
+}
+
+// buildComputerFilter returns a WHERE clause with positional parameters and its arguments for the given filter.
+// It returns an empty clause if the filter has no conditions.
+func buildComputerFilter(filter dbo.ComputerFilter) (string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.SerialNumber != nil {
+		add("lower(serial_number) = lower($%d)", *filter.SerialNumber)
+	}
+
+	if filter.Vendor != nil {
+		add("lower(vendor) = lower($%d)", *filter.Vendor)
+	}
+
+	if filter.Model != nil {
+		add("lower(model) = lower($%d)", *filter.Model)
+	}
+
+	if filter.OperatingSystem != nil {
+		add("lower(operating_system) = lower($%d)", *filter.OperatingSystem)
+	}
+
+	if filter.Location != nil {
+		add("lower(location) = lower($%d)", *filter.Location)
+	}
+
+	if filter.PurchasedAfter != nil {
+		add("purchase_date >= $%d", *filter.PurchasedAfter)
+	}
+
+	if filter.PurchasedBefore != nil {
+		add("purchase_date <= $%d", *filter.PurchasedBefore)
+	}
+
+	if filter.WarrantyEndsAfter != nil {
+		add("warranty_end >= $%d", *filter.WarrantyEndsAfter)
+	}
+
+	if filter.WarrantyEndsBefore != nil {
+		add("warranty_end <= $%d", *filter.WarrantyEndsBefore)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// scanComputers scans all rows selected with computerColumns into computer DBOs.
+func scanComputers(rows *sql.Rows) ([]dbo.Computer, error) {
+	var computerDBOs []dbo.Computer
+
+	for rows.Next() {
+		c, err := scanComputer(rows)
+		if err != nil {
+			return nil, dbError("failed to scan row", err)
+		}
+
+		computerDBOs = append(computerDBOs, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to iterate rows", err)
+	}
+
+	return computerDBOs, nil
 }
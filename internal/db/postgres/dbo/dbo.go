@@ -1,9 +1,13 @@
 // Package dbo provides database object representations
 package dbo
 
-import "database/sql"
+import (
+	"database/sql"
+	"time"
+)
 
 // Computer holds network and employee data for a computer.
+// IPAddress and MACAddress hold the text representation of the addresses of the primary network interface.
 type Computer struct {
 	ID                   int            `db:"id"`
 	Name                 string         `db:"name"`
@@ -11,4 +15,144 @@ type Computer struct {
 	MACAddress           string         `db:"mac_address"`
 	EmployeeAbbreviation sql.NullString `db:"employee_abbreviation"`
 	Description          sql.NullString `db:"description"`
+	SubnetID             sql.NullInt64  `db:"subnet_id"`
+	Status               string         `db:"status"`
+	SerialNumber         sql.NullString `db:"serial_number"`
+	PurchaseDate         sql.NullTime   `db:"purchase_date"`
+	WarrantyEnd          sql.NullTime   `db:"warranty_end"`
+	Vendor               sql.NullString `db:"vendor"`
+	Model                sql.NullString `db:"model"`
+	OperatingSystem      sql.NullString `db:"operating_system"`
+	Location             sql.NullString `db:"location"`
+}
+
+// ComputerFilter holds the optional conditions a listed computer has to match.
+type ComputerFilter struct {
+	SerialNumber       *string
+	Vendor             *string
+	Model              *string
+	OperatingSystem    *string
+	Location           *string
+	PurchasedAfter     *time.Time
+	PurchasedBefore    *time.Time
+	WarrantyEndsAfter  *time.Time
+	WarrantyEndsBefore *time.Time
+}
+
+// ComputerSelector selects computers either by their IDs or by a filter. A positive Limit restricts the number of
+// computers selected by the filter.
+type ComputerSelector struct {
+	IDs    []int
+	Filter *ComputerFilter
+	Limit  int
+}
+
+// ComputerChanges holds the attributes set on several computers at once. Invalid fields are left unchanged.
+type ComputerChanges struct {
+	Description     sql.NullString
+	Vendor          sql.NullString
+	Model           sql.NullString
+	OperatingSystem sql.NullString
+	Location        sql.NullString
+}
+
+// StatusTransition holds a recorded change of a computer's status.
+type StatusTransition struct {
+	ID         int       `db:"id"`
+	ComputerID int       `db:"computer_id"`
+	FromStatus string    `db:"from_status"`
+	ToStatus   string    `db:"to_status"`
+	Reason     string    `db:"reason"`
+	Actor      string    `db:"actor"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// Assignment holds the period during which a computer was assigned to an employee.
+type Assignment struct {
+	ID                   int          `db:"id"`
+	ComputerID           int          `db:"computer_id"`
+	ComputerName         string       `db:"computer_name"`
+	EmployeeAbbreviation string       `db:"employee_abbreviation"`
+	StartedAt            time.Time    `db:"started_at"`
+	EndedAt              sql.NullTime `db:"ended_at"`
+}
+
+// ComputerEvent holds a recorded change of a computer.
+type ComputerEvent struct {
+	ID                   int64          `db:"id"`
+	Type                 string         `db:"type"`
+	ComputerID           int            `db:"computer_id"`
+	EmployeeAbbreviation sql.NullString `db:"employee_abbreviation"`
+	OccurredAt           time.Time      `db:"occurred_at"`
+}
+
+// WebhookSubscription holds an endpoint notified about computer events.
+type WebhookSubscription struct {
+	ID         int       `db:"id"`
+	URL        string    `db:"url"`
+	EventTypes []string  `db:"event_types"`
+	Secret     string    `db:"secret"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// WebhookDelivery holds one attempt to deliver an event to a webhook subscription.
+type WebhookDelivery struct {
+	ID             int64          `db:"id"`
+	SubscriptionID int            `db:"subscription_id"`
+	EventID        sql.NullInt64  `db:"event_id"`
+	EventType      string         `db:"event_type"`
+	Attempt        int            `db:"attempt"`
+	StatusCode     sql.NullInt64  `db:"status_code"`
+	Error          sql.NullString `db:"error"`
+	Succeeded      bool           `db:"succeeded"`
+	DurationMS     int            `db:"duration_ms"`
+	DeliveredAt    time.Time      `db:"delivered_at"`
+}
+
+// NetworkInterface holds the addresses of one network interface of a computer.
+type NetworkInterface struct {
+	ID         int            `db:"id"`
+	ComputerID int            `db:"computer_id"`
+	Name       string         `db:"name"`
+	MACAddress string         `db:"mac_address"`
+	IPAddress  sql.NullString `db:"ip_address"`
+	Type       string         `db:"type"`
+	IsPrimary  bool           `db:"is_primary"`
+}
+
+// ComputersVersion holds the version of the computers table and the time of its last change.
+type ComputersVersion struct {
+	Version   int64     `db:"version"`
+	ChangedAt time.Time `db:"changed_at"`
+}
+
+// IPAddressUsage holds a computer together with one of the IP addresses of its network interfaces.
+type IPAddressUsage struct {
+	IPAddress string `db:"ip_address"`
+	Computer  Computer
+}
+
+// ComputerSearchResult holds a computer matching a search query together with its relevance.
+type ComputerSearchResult struct {
+	Computer Computer
+	Rank     float64 `db:"rank"`
+	// MACAddresses are the MAC addresses of all network interfaces of the computer, the primary one first.
+	MACAddresses []string `db:"mac_addresses"`
+}
+
+// Subnet holds the address plan data of a network.
+type Subnet struct {
+	ID             int            `db:"id"`
+	Name           string         `db:"name"`
+	CIDR           string         `db:"cidr"`
+	Gateway        sql.NullString `db:"gateway"`
+	VLAN           sql.NullInt64  `db:"vlan"`
+	ReservedRanges []IPRange
+}
+
+// IPRange holds an inclusive range of reserved IP addresses of a subnet.
+type IPRange struct {
+	Start       string         `db:"start_address"`
+	End         string         `db:"end_address"`
+	Description sql.NullString `db:"description"`
 }
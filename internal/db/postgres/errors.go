@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/lib/pq"
+)
+
+// SQLSTATE codes of errors that are handled by the repository methods themselves.
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+	exclusionViolation  = "23P01"
+)
+
+// SQLSTATE codes and classes used to classify the remaining errors, see
+// https://www.postgresql.org/docs/current/errcodes-appendix.html.
+const (
+	dataExceptionClass         = "22"
+	connectionExceptionClass   = "08"
+	insufficientResourcesClass = "53"
+
+	notNullViolation        = "23502"
+	checkViolation          = "23514"
+	readOnlySQLTransaction  = "25006"
+	serializationFailure    = "40001"
+	deadlockDetected        = "40P01"
+	insufficientPrivilege   = "42501"
+	objectNotInPrerequisite = "55000"
+	lockNotAvailable        = "55P03"
+	queryCanceled           = "57014"
+	adminShutdown           = "57P01"
+	crashShutdown           = "57P02"
+	cannotConnectNow        = "57P03"
+)
+
+// isConstraintViolation reports whether err is a PostgreSQL error with the given SQLSTATE code.
+func isConstraintViolation(err error, code pq.ErrorCode) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == code
+}
+
+// dbError wraps err with msg like fmt.Errorf after classifying it with classifyError.
+func dbError(msg string, err error) error {
+	return fmt.Errorf("%s: %w", msg, classifyError(err))
+}
+
+// classifyError wraps a failure of the database into the typed error of internal/errors describing it, so that
+// callers can tell invalid or conflicting data from an unavailable database. Errors that are typed already and
+// errors that cannot be classified are returned unchanged.
+func classifyError(err error) error {
+	if isTyped(err) {
+		return err
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errs.NewTimeout("the database has not responded in time", err)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return errs.NewTimeout("the database has not responded in time", err)
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return classifyPQError(pqErr, err)
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.As(err, new(*net.OpError)) {
+		return errs.NewUnavailable("the database is unavailable", err)
+	}
+
+	return err
+}
+
+func classifyPQError(pqErr *pq.Error, err error) error {
+	switch pqErr.Code {
+	case serializationFailure, deadlockDetected, lockNotAvailable:
+		return errs.NewConflict("the data has been changed concurrently, please retry")
+	case insufficientPrivilege:
+		return errs.NewForbidden("the database does not permit the operation")
+	case objectNotInPrerequisite:
+		return errs.NewPreconditionFailed(pqErr.Message)
+	case queryCanceled:
+		return errs.NewTimeout("the database has canceled the query", err)
+	case readOnlySQLTransaction, adminShutdown, crashShutdown, cannotConnectNow:
+		return errs.NewUnavailable("the database is unavailable", err)
+	case uniqueViolation, exclusionViolation, foreignKeyViolation:
+		// The data conflicts with other rows, e.g. a duplicate key or a computer that is still referenced.
+		if pqErr.Detail != "" {
+			return errs.NewConflict("conflicting data: " + pqErr.Detail)
+		}
+
+		return errs.NewConflict("conflicting data: " + pqErr.Message)
+	case notNullViolation, checkViolation:
+		// The data is invalid on its own, independent of other rows.
+		return errs.NewValidation("invalid data: " + pqErr.Message)
+	}
+
+	switch pqErr.Code.Class() {
+	case dataExceptionClass:
+		return errs.NewValidation("invalid data: " + pqErr.Message)
+	case connectionExceptionClass, insufficientResourcesClass:
+		return errs.NewUnavailable("the database is unavailable", err)
+	}
+
+	return err
+}
+
+// isTyped reports whether err already is or wraps a typed error of internal/errors.
+func isTyped(err error) bool {
+	return errors.As(err, new(*errs.NotFoundError)) ||
+		errors.As(err, new(*errs.ConflictError)) ||
+		errors.As(err, new(*errs.InvalidTransitionError)) ||
+		errors.As(err, new(*errs.ValidationError)) ||
+		errors.As(err, new(*errs.PreconditionFailedError)) ||
+		errors.As(err, new(*errs.ForbiddenError)) ||
+		errors.As(err, new(*errs.UnavailableError)) ||
+		errors.As(err, new(*errs.TimeoutError))
+}
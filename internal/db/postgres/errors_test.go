@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"syscall"
+	"testing"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedType   error
+		expectedDetail string
+	}{
+		{
+			name:           "unique violation",
+			err:            &pq.Error{Code: uniqueViolation, Message: "duplicate key", Detail: "Key (mac_address)=(aa:bb:cc:dd:ee:ff) already exists."},
+			expectedType:   &errs.ConflictError{},
+			expectedDetail: "conflicting data: Key (mac_address)=(aa:bb:cc:dd:ee:ff) already exists.",
+		},
+		{
+			name:           "foreign key violation without detail",
+			err:            &pq.Error{Code: foreignKeyViolation, Message: "violates foreign key constraint"},
+			expectedType:   &errs.ConflictError{},
+			expectedDetail: "conflicting data: violates foreign key constraint",
+		},
+		{
+			name:           "exclusion violation",
+			err:            &pq.Error{Code: exclusionViolation, Message: "conflicting key value violates exclusion constraint", Detail: "Key (cidr)=(10.0.0.0/25) conflicts with existing key (cidr)=(10.0.0.0/24)."},
+			expectedType:   &errs.ConflictError{},
+			expectedDetail: "conflicting data: Key (cidr)=(10.0.0.0/25) conflicts with existing key (cidr)=(10.0.0.0/24).",
+		},
+		{
+			name:           "check violation",
+			err:            &pq.Error{Code: checkViolation, Message: `new row for relation "computers" violates check constraint "computers_employee_abbreviation_check"`},
+			expectedType:   &errs.ValidationError{},
+			expectedDetail: `invalid data: new row for relation "computers" violates check constraint "computers_employee_abbreviation_check"`,
+		},
+		{
+			name:           "not null violation",
+			err:            &pq.Error{Code: notNullViolation, Message: `null value in column "name" of relation "computers" violates not-null constraint`},
+			expectedType:   &errs.ValidationError{},
+			expectedDetail: `invalid data: null value in column "name" of relation "computers" violates not-null constraint`,
+		},
+		{
+			name:           "serialization failure",
+			err:            &pq.Error{Code: serializationFailure},
+			expectedType:   &errs.ConflictError{},
+			expectedDetail: "the data has been changed concurrently, please retry",
+		},
+		{
+			name:         "deadlock",
+			err:          &pq.Error{Code: deadlockDetected},
+			expectedType: &errs.ConflictError{},
+		},
+		{
+			name:           "data exception",
+			err:            &pq.Error{Code: "22001", Message: "value too long for type character varying(255)"},
+			expectedType:   &errs.ValidationError{},
+			expectedDetail: "invalid data: value too long for type character varying(255)",
+		},
+		{
+			name:         "insufficient privilege",
+			err:          &pq.Error{Code: insufficientPrivilege, Message: "permission denied for table computers"},
+			expectedType: &errs.ForbiddenError{},
+		},
+		{
+			name:           "object not in prerequisite state",
+			err:            &pq.Error{Code: objectNotInPrerequisite, Message: "subnet is locked"},
+			expectedType:   &errs.PreconditionFailedError{},
+			expectedDetail: "subnet is locked",
+		},
+		{
+			name:         "statement timeout",
+			err:          &pq.Error{Code: queryCanceled},
+			expectedType: &errs.TimeoutError{},
+		},
+		{
+			name:         "context deadline",
+			err:          fmt.Errorf("query: %w", context.DeadlineExceeded),
+			expectedType: &errs.TimeoutError{},
+		},
+		{
+			name:         "shutdown",
+			err:          &pq.Error{Code: adminShutdown},
+			expectedType: &errs.UnavailableError{},
+		},
+		{
+			name:         "connection failure",
+			err:          &pq.Error{Code: "08006"},
+			expectedType: &errs.UnavailableError{},
+		},
+		{
+			name:         "too many connections",
+			err:          &pq.Error{Code: "53300"},
+			expectedType: &errs.UnavailableError{},
+		},
+		{
+			name:         "read-only transaction after failover",
+			err:          &pq.Error{Code: readOnlySQLTransaction},
+			expectedType: &errs.UnavailableError{},
+		},
+		{
+			name:         "refused connection",
+			err:          dialErr,
+			expectedType: &errs.UnavailableError{},
+		},
+		{
+			name:         "bad connection",
+			err:          driver.ErrBadConn,
+			expectedType: &errs.UnavailableError{},
+		},
+		{
+			name:         "connection closed by the server",
+			err:          io.ErrUnexpectedEOF,
+			expectedType: &errs.UnavailableError{},
+		},
+		{
+			name:         "typed error is kept",
+			err:          fmt.Errorf("failed to update computer: %w", errs.NewNotFound("computer not found")),
+			expectedType: &errs.NotFoundError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := dbError("failed to query computers", tt.err)
+
+			// Assert
+			assert.True(t, wrapsType(err, tt.expectedType), "%v does not wrap a %T", err, tt.expectedType)
+
+			if tt.expectedDetail != "" {
+				assert.Equal(t, "failed to query computers: "+tt.expectedDetail, err.Error())
+			}
+
+			// Unavailable and timeout errors keep their cause since it is logged.
+			switch tt.expectedType.(type) {
+			case *errs.UnavailableError, *errs.TimeoutError:
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestClassifyErrorKeepsUnknownErrors(t *testing.T) {
+	// Arrange
+	unknown := errors.New("sql: Scan error on column index 1")
+
+	// Act
+	err := classifyError(unknown)
+
+	// Assert
+	assert.Same(t, unknown, err)
+}
+
+// wrapsType reports whether err or one of the errors it wraps has the type of target.
+func wrapsType(err, target error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if reflect.TypeOf(err) == reflect.TypeOf(target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"time"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+)
+
+// AddComputerEvents records the events and returns them together with their generated IDs and times. Called
+// within WithTx, the events become visible together with the changes they report.
+//
+// Clients resume from the ID of the last event they have received, so the IDs have to follow the order in which
+// the events become visible. A transaction-level advisory lock therefore serializes the transactions recording
+// events: a transaction only draws IDs once the previous one has been committed or rolled back.
+func (r *Repository) AddComputerEvents(events []dbo.ComputerEvent) ([]dbo.ComputerEvent, error) {
+	tx, err := r.begin()
+	if err != nil {
+		return nil, dbError("failed to begin transaction", err)
+	}
+
+	defer tx.Rollback() // no-op after a successful commit
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1, 0);`, eventLogLockClass); err != nil {
+		return nil, dbError("failed to lock the event log", err)
+	}
+
+	stored := make([]dbo.ComputerEvent, len(events))
+
+	for i, event := range events {
+		err := tx.QueryRow(`
+			INSERT INTO computer_events (type, computer_id, employee_abbreviation)
+			VALUES ($1, $2, $3)
+			RETURNING id, occurred_at;`,
+			event.Type, event.ComputerID, event.EmployeeAbbreviation,
+		).Scan(&event.ID, &event.OccurredAt)
+		if err != nil {
+			return nil, dbError("failed to insert computer event", err)
+		}
+
+		stored[i] = event
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError("failed to commit transaction", err)
+	}
+
+	return stored, nil
+}
+
+// GetComputerEventsSince retrieves at most limit events recorded after the event with the ID afterID ordered by
+// their IDs. The events are read from the primary database since a client resuming a stream has usually received
+// the latest events just before.
+func (r *Repository) GetComputerEventsSince(ctx context.Context, afterID int64, limit int) ([]dbo.ComputerEvent, error) {
+	stmt, err := r.prepare(`
+		SELECT id, type, computer_id, employee_abbreviation, occurred_at
+		FROM computer_events
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2;`)
+	if err != nil {
+		return nil, dbError("failed to prepare select statement", err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, afterID, limit)
+	if err != nil {
+		return nil, dbError("failed to query computer events", err)
+	}
+	defer rows.Close()
+
+	var events []dbo.ComputerEvent
+
+	for rows.Next() {
+		var e dbo.ComputerEvent
+
+		if err := rows.Scan(&e.ID, &e.Type, &e.ComputerID, &e.EmployeeAbbreviation, &e.OccurredAt); err != nil {
+			return nil, dbError("failed to scan row", err)
+		}
+
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to iterate rows", err)
+	}
+
+	return events, nil
+}
+
+// GetLastComputerEventID returns the ID of the latest recorded event or 0 if no event has been recorded.
+func (r *Repository) GetLastComputerEventID(ctx context.Context) (int64, error) {
+	stmt, err := r.prepare(`SELECT coalesce(max(id), 0) FROM computer_events;`)
+	if err != nil {
+		return 0, dbError("failed to prepare select statement", err)
+	}
+
+	var id int64
+
+	if err := stmt.QueryRowContext(ctx).Scan(&id); err != nil {
+		return 0, dbError("failed to query the last computer event ID", err)
+	}
+
+	return id, nil
+}
+
+// DeleteComputerEventsBefore deletes all events that occurred before the given time and returns their number.
+func (r *Repository) DeleteComputerEventsBefore(before time.Time) (int, error) {
+	stmt, err := r.prepare(`DELETE FROM computer_events WHERE occurred_at < $1;`)
+	if err != nil {
+		return 0, dbError("failed to prepare delete statement", err)
+	}
+
+	result, err := stmt.Exec(before)
+	if err != nil {
+		return 0, dbError("failed to execute delete statement", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, dbError("failed to get affected rows", err)
+	}
+
+	return int(n), nil
+}
@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"database/sql"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/errors"
+)
+
+// networkInterfaceColumns lists the selected columns of the network_interfaces table in the order expected by
+// scanNetworkInterface.
+const networkInterfaceColumns = `id, computer_id, name, upper(mac_address::TEXT), host(ip_address), type, is_primary`
+
+// GetNetworkInterfaces retrieves all network interfaces of a computer with the primary interface first.
+func (r *Repository) GetNetworkInterfaces(computerID int) ([]dbo.NetworkInterface, error) {
+	stmt, err := r.prepare(`
+		SELECT ` + networkInterfaceColumns + ` FROM network_interfaces
+		WHERE computer_id = $1
+		ORDER BY is_primary DESC, id;`)
+	if err != nil {
+		return nil, dbError("failed to prepare select statement", err)
+	}
+
+	rows, err := stmt.Query(computerID)
+	if err != nil {
+		return nil, dbError("failed to query network interfaces", err)
+	}
+	defer rows.Close()
+
+	var interfaces []dbo.NetworkInterface
+
+	for rows.Next() {
+		iface, err := scanNetworkInterface(rows)
+		if err != nil {
+			return nil, dbError("failed to scan row", err)
+		}
+
+		interfaces = append(interfaces, iface)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to iterate rows", err)
+	}
+
+	return interfaces, nil
+}
+
+// GetNetworkInterface retrieves a network interface of a computer by its ID.
+// It returns a not found error if the computer has no such interface.
+func (r *Repository) GetNetworkInterface(computerID, interfaceID int) (dbo.NetworkInterface, error) {
+	stmt, err := r.prepare(`SELECT ` + networkInterfaceColumns + ` FROM network_interfaces WHERE id = $1 AND computer_id = $2;`)
+	if err != nil {
+		return dbo.NetworkInterface{}, dbError("failed to prepare select statement", err)
+	}
+
+	iface, err := scanNetworkInterface(stmt.QueryRow(interfaceID, computerID))
+	if err == sql.ErrNoRows {
+		return dbo.NetworkInterface{}, errors.NewNotFound("network interface not found")
+	} else if err != nil {
+		return dbo.NetworkInterface{}, dbError("failed to query network interface", err)
+	}
+
+	return iface, nil
+}
+
+// AddNetworkInterface inserts a new network interface and returns its generated ID. If the interface is primary,
+// it replaces the computer's current primary interface. It returns a not found error if the computer does not exist
+// and a conflict error if the MAC address or the name is already in use.
+func (r *Repository) AddNetworkInterface(iface dbo.NetworkInterface) (int, error) {
+	tx, err := r.begin()
+	if err != nil {
+		return 0, dbError("failed to begin transaction", err)
+	}
+
+	defer tx.Rollback() // no-op after a successful commit
+
+	if iface.IsPrimary {
+		if err := unsetPrimaryInterface(tx.Tx, iface.ComputerID); err != nil {
+			return 0, err
+		}
+	}
+
+	var interfaceID int
+	err = tx.QueryRow(`
+		INSERT INTO network_interfaces (computer_id, name, mac_address, ip_address, type, is_primary)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id;`,
+		iface.ComputerID, iface.Name, iface.MACAddress, iface.IPAddress, iface.Type, iface.IsPrimary,
+	).Scan(&interfaceID)
+	if isConstraintViolation(err, foreignKeyViolation) {
+		return 0, errors.NewNotFound("computer not found")
+	} else if isConstraintViolation(err, uniqueViolation) {
+		return 0, errors.NewConflict("MAC address or name is already used by another network interface")
+	} else if err != nil {
+		return 0, dbError("failed to insert network interface", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, dbError("failed to commit transaction", err)
+	}
+
+	return interfaceID, nil
+}
+
+// UpdateNetworkInterface updates a network interface of a computer. If the interface becomes primary, it replaces
+// the computer's current primary interface. It returns a not found error if the computer has no such interface and
+// a conflict error if the MAC address or the name is already in use or if the primary interface would be demoted.
+func (r *Repository) UpdateNetworkInterface(computerID, interfaceID int, data dbo.NetworkInterface) error {
+	tx, err := r.begin()
+	if err != nil {
+		return dbError("failed to begin transaction", err)
+	}
+
+	defer tx.Rollback() // no-op after a successful commit
+
+	var isPrimary bool
+	err = tx.QueryRow(
+		`SELECT is_primary FROM network_interfaces WHERE id = $1 AND computer_id = $2 FOR UPDATE;`,
+		interfaceID, computerID,
+	).Scan(&isPrimary)
+	if err == sql.ErrNoRows {
+		return errors.NewNotFound("network interface not found")
+	} else if err != nil {
+		return dbError("failed to query network interface", err)
+	}
+
+	if isPrimary && !data.IsPrimary {
+		return errors.NewConflict("the primary network interface cannot be demoted, make another interface primary instead")
+	}
+
+	if !isPrimary && data.IsPrimary {
+		if err := unsetPrimaryInterface(tx.Tx, computerID); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE network_interfaces
+		SET name = $1, mac_address = $2, ip_address = $3, type = $4, is_primary = $5
+		WHERE id = $6;`,
+		data.Name, data.MACAddress, data.IPAddress, data.Type, data.IsPrimary, interfaceID,
+	)
+	if isConstraintViolation(err, uniqueViolation) {
+		return errors.NewConflict("MAC address or name is already used by another network interface")
+	} else if err != nil {
+		return dbError("failed to execute update statement", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbError("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// DeleteNetworkInterface removes a network interface of a computer. It returns a not found error if the computer
+// has no such interface and a conflict error if the interface is the primary one.
+func (r *Repository) DeleteNetworkInterface(computerID, interfaceID int) error {
+	tx, err := r.begin()
+	if err != nil {
+		return dbError("failed to begin transaction", err)
+	}
+
+	defer tx.Rollback() // no-op after a successful commit
+
+	var isPrimary bool
+	err = tx.QueryRow(
+		`SELECT is_primary FROM network_interfaces WHERE id = $1 AND computer_id = $2 FOR UPDATE;`,
+		interfaceID, computerID,
+	).Scan(&isPrimary)
+	if err == sql.ErrNoRows {
+		return errors.NewNotFound("network interface not found")
+	} else if err != nil {
+		return dbError("failed to query network interface", err)
+	}
+
+	if isPrimary {
+		return errors.NewConflict("the primary network interface cannot be deleted, make another interface primary first")
+	}
+
+	if _, err := tx.Exec(`DELETE FROM network_interfaces WHERE id = $1;`, interfaceID); err != nil {
+		return dbError("failed to execute delete statement", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbError("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// unsetPrimaryInterface demotes the current primary interface of a computer so that another one can become primary.
+func unsetPrimaryInterface(tx *sql.Tx, computerID int) error {
+	if _, err := tx.Exec(`UPDATE network_interfaces SET is_primary = false WHERE computer_id = $1 AND is_primary;`, computerID); err != nil {
+		return dbError("failed to unset primary network interface", err)
+	}
+
+	return nil
+}
+
+// scanNetworkInterface scans a row selected with networkInterfaceColumns into a network interface DBO.
+func scanNetworkInterface(row rowScanner) (dbo.NetworkInterface, error) {
+	var iface dbo.NetworkInterface
+
+	err := row.Scan(
+		&iface.ID,
+		&iface.ComputerID,
+		&iface.Name,
+		&iface.MACAddress,
+		&iface.IPAddress,
+		&iface.Type,
+		&iface.IsPrimary,
+	)
+
+	return iface, err
+}
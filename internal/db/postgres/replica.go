@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+	"uhuaha/computers-management/internal/consistency"
+	"uhuaha/computers-management/internal/service"
+
+	"github.com/bdlm/log"
+)
+
+// replicaCooldown is the time a replica is bypassed after it failed to answer a query.
+const replicaCooldown = 30 * time.Second
+
+// RepositoryOption configures optional features of a repository.
+type RepositoryOption func(r *Repository)
+
+// WithReplica routes the list and search queries to a read-only replica of the database. Requests marked with
+// consistency.WithReadYourWrites and transactions keep reading from the primary.
+func WithReplica(replicaConn *sql.DB) RepositoryOption {
+	return func(r *Repository) {
+		r.replica = &replica{
+			stmts: newStatementCache(replicaConn),
+			ping:  replicaConn.PingContext,
+		}
+	}
+}
+
+// replica is a read-only database that is bypassed for replicaCooldown after it failed to answer a query.
+type replica struct {
+	stmts *statementCache
+	ping  func(ctx context.Context) error
+
+	mu             sync.Mutex
+	unhealthyUntil time.Time
+}
+
+func (r *replica) healthy() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return time.Now().After(r.unhealthyUntil)
+}
+
+func (r *replica) markUnhealthy() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.unhealthyUntil = time.Now().Add(replicaCooldown)
+}
+
+// queryRead executes a read-only query on the replica if one is configured and may be used for ctx. If the replica
+// fails, the query is executed on the primary and the replica is bypassed for a while if it is unreachable.
+func (r *Repository) queryRead(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if r.replica != nil && r.tx == nil && !consistency.ReadYourWrites(ctx) && r.replica.healthy() {
+		rows, err := r.queryReplica(ctx, query, args...)
+		if err == nil {
+			return rows, nil
+		}
+
+		log.Warnf("failed to query replica, falling back to primary: %v", err)
+
+		if err := r.replica.ping(ctx); err != nil {
+			r.replica.markUnhealthy()
+		}
+	}
+
+	stmt, err := r.prepare(query)
+	if err != nil {
+		return nil, dbError("failed to prepare select statement", err)
+	}
+
+	return stmt.QueryContext(ctx, args...)
+}
+
+func (r *Repository) queryReplica(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	stmt, err := r.replica.stmts.prepare(query)
+	if err != nil {
+		return nil, dbError("failed to prepare select statement", err)
+	}
+
+	return stmt.QueryContext(ctx, args...)
+}
+
+// snapshotOptions start a transaction whose queries all see the database as of its first query.
+var snapshotOptions = &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+
+// WithSnapshot runs fn with a repository whose reads all see the same snapshot of the database, so that the
+// results of several queries are consistent with each other. The snapshot is taken on the replica if one is
+// configured and may be used for ctx, and on the primary otherwise or if the replica is unreachable. Within WithTx,
+// fn runs within the surrounding transaction.
+func (r *Repository) WithSnapshot(ctx context.Context, fn func(repo service.ComputerRepository) error) error {
+	if r.tx != nil {
+		return fn(r)
+	}
+
+	if r.replica != nil && !consistency.ReadYourWrites(ctx) && r.replica.healthy() {
+		tx, err := r.replica.stmts.db.BeginTx(ctx, snapshotOptions)
+		if err == nil {
+			return runSnapshot(tx, r.replica.stmts, fn)
+		}
+
+		log.Warnf("failed to begin snapshot on replica, falling back to primary: %v", err)
+
+		if err := r.replica.ping(ctx); err != nil {
+			r.replica.markUnhealthy()
+		}
+	}
+
+	tx, err := r.db.BeginTx(ctx, snapshotOptions)
+	if err != nil {
+		return dbError("failed to begin transaction", err)
+	}
+
+	return runSnapshot(tx, r.stmts, fn)
+}
+
+// runSnapshot runs fn with a repository using the read-only transaction and the statements of its database.
+func runSnapshot(tx *sql.Tx, stmts *statementCache, fn func(repo service.ComputerRepository) error) error {
+	defer tx.Rollback() // a read-only transaction has nothing to commit
+
+	return fn(&Repository{db: stmts.db, dbConn: tx, tx: tx, stmts: stmts})
+}
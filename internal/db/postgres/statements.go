@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// statementCache prepares every query once and reuses the prepared statement for all later calls. Queries with
+// optional conditions are cached once per combination of conditions since their values are passed as parameters.
+type statementCache struct {
+	db *sql.DB
+
+	mu    sync.RWMutex
+	stmts map[string]*sql.Stmt
+}
+
+func newStatementCache(db *sql.DB) *statementCache {
+	return &statementCache{
+		db:    db,
+		stmts: make(map[string]*sql.Stmt),
+	}
+}
+
+// prepare returns the prepared statement of the query and prepares it on first use.
+func (c *statementCache) prepare(query string) (*sql.Stmt, error) {
+	c.mu.RLock()
+	stmt, ok := c.stmts[query]
+	c.mu.RUnlock()
+
+	if ok {
+		return stmt, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if stmt, ok := c.stmts[query]; ok {
+		return stmt, nil
+	}
+
+	stmt, err := c.db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.stmts[query] = stmt
+
+	return stmt, nil
+}
+
+// close closes all prepared statements.
+func (c *statementCache) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for query, stmt := range c.stmts {
+		errs = append(errs, stmt.Close())
+		delete(c.stmts, query)
+	}
+
+	return errors.Join(errs...)
+}
+
+// prepare returns the cached prepared statement of the query. Within WithTx the statement is bound to the
+// transaction and closed together with it. The returned statement must not be closed by the caller.
+func (r *Repository) prepare(query string) (*sql.Stmt, error) {
+	stmt, err := r.stmts.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.tx != nil {
+		return r.tx.Stmt(stmt), nil
+	}
+
+	return stmt, nil
+}
+
+// Close releases the prepared statements of the repository. The database connections are not closed.
+func (r *Repository) Close() error {
+	if r.replica != nil {
+		return errors.Join(r.stmts.close(), r.replica.stmts.close())
+	}
+
+	return r.stmts.close()
+}
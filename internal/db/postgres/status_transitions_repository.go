@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"database/sql"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/errors"
+)
+
+// statusAssigned is the status of computers that belong to an employee.
+const statusAssigned = "assigned"
+
+// AddStatusTransition changes the status of a computer from transition.FromStatus to transition.ToStatus and
+// records the transition. A computer leaving the status assigned loses its employee and its active assignment ends.
+// It returns a conflict error if the computer's status is no longer transition.FromStatus, e.g. because it has been
+// changed concurrently. The stored transition including its ID and creation time is returned.
+func (r *Repository) AddStatusTransition(transition dbo.StatusTransition) (dbo.StatusTransition, error) {
+	tx, err := r.begin()
+	if err != nil {
+		return dbo.StatusTransition{}, dbError("failed to begin transaction", err)
+	}
+
+	defer tx.Rollback() // no-op after a successful commit
+
+	result, err := tx.Exec(
+		`UPDATE computers SET status = $1 WHERE id = $2 AND status = $3;`,
+		transition.ToStatus, transition.ComputerID, transition.FromStatus,
+	)
+	if err != nil {
+		return dbo.StatusTransition{}, dbError("failed to update status", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return dbo.StatusTransition{}, dbError("failed to get affected rows", err)
+	} else if n == 0 {
+		return dbo.StatusTransition{}, errors.NewConflict("status of the computer has been changed concurrently")
+	}
+
+	if transition.FromStatus == statusAssigned && transition.ToStatus != statusAssigned {
+		if _, err := tx.Exec(`UPDATE computers SET employee_abbreviation = NULL WHERE id = $1;`, transition.ComputerID); err != nil {
+			return dbo.StatusTransition{}, dbError("failed to remove employee", err)
+		}
+
+		if err := reassignComputer(tx.Tx, transition.ComputerID, sql.NullString{}); err != nil {
+			return dbo.StatusTransition{}, err
+		}
+	}
+
+	transition, err = insertStatusTransition(tx.Tx, transition)
+	if err != nil {
+		return dbo.StatusTransition{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbo.StatusTransition{}, dbError("failed to commit transaction", err)
+	}
+
+	return transition, nil
+}
+
+// insertStatusTransition records a status transition within the given transaction and returns it together with
+// its generated ID and creation time.
+func insertStatusTransition(tx *sql.Tx, transition dbo.StatusTransition) (dbo.StatusTransition, error) {
+	err := tx.QueryRow(`
+		INSERT INTO computer_status_transitions (computer_id, from_status, to_status, reason, actor)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at;`,
+		transition.ComputerID, transition.FromStatus, transition.ToStatus, transition.Reason, transition.Actor,
+	).Scan(&transition.ID, &transition.CreatedAt)
+	if err != nil {
+		return dbo.StatusTransition{}, dbError("failed to insert status transition", err)
+	}
+
+	return transition, nil
+}
+
+// GetStatusTransitions retrieves the status history of a computer ordered from oldest to newest.
+func (r *Repository) GetStatusTransitions(computerID int) ([]dbo.StatusTransition, error) {
+	stmt, err := r.prepare(`
+		SELECT id, computer_id, from_status, to_status, reason, actor, created_at
+		FROM computer_status_transitions
+		WHERE computer_id = $1
+		ORDER BY created_at, id;`)
+	if err != nil {
+		return nil, dbError("failed to prepare select statement", err)
+	}
+
+	rows, err := stmt.Query(computerID)
+	if err != nil {
+		return nil, dbError("failed to query status transitions", err)
+	}
+	defer rows.Close()
+
+	var transitions []dbo.StatusTransition
+
+	for rows.Next() {
+		var t dbo.StatusTransition
+
+		if err := rows.Scan(&t.ID, &t.ComputerID, &t.FromStatus, &t.ToStatus, &t.Reason, &t.Actor, &t.CreatedAt); err != nil {
+			return nil, dbError("failed to scan row", err)
+		}
+
+		transitions = append(transitions, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to iterate rows", err)
+	}
+
+	return transitions, nil
+}
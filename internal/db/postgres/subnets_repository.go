@@ -0,0 +1,368 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/errors"
+)
+
+// subnetColumns lists the selected columns of the subnets table. Addresses are returned in their canonical text
+// representation.
+const subnetColumns = `id, name, cidr::TEXT, host(gateway), vlan`
+
+// AddSubnet inserts a new subnet together with its reserved ranges and returns its generated ID.
+// It returns a conflict error if the subnet overlaps with an existing one.
+func (r *Repository) AddSubnet(subnet dbo.Subnet) (int, error) {
+	tx, err := r.begin()
+	if err != nil {
+		return 0, dbError("failed to begin transaction", err)
+	}
+
+	defer tx.Rollback() // no-op after a successful commit
+
+	var subnetID int
+	err = tx.QueryRow(`
+		INSERT INTO subnets (name, cidr, gateway, vlan)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id;`,
+		subnet.Name, subnet.CIDR, subnet.Gateway, subnet.VLAN,
+	).Scan(&subnetID)
+	if isConstraintViolation(err, exclusionViolation) {
+		return 0, errors.NewConflict("subnet overlaps with an existing subnet")
+	} else if err != nil {
+		return 0, dbError("failed to insert subnet", err)
+	}
+
+	if err := insertReservedRanges(tx.Tx, subnetID, subnet.ReservedRanges); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, dbError("failed to commit transaction", err)
+	}
+
+	return subnetID, nil
+}
+
+// GetSubnet retrieves a subnet including its reserved ranges by its ID.
+// It returns the subnet or an error if the record is not found or the query fails.
+func (r *Repository) GetSubnet(subnetID int) (dbo.Subnet, error) {
+	stmt, err := r.prepare(`SELECT ` + subnetColumns + ` FROM subnets WHERE id = $1;`)
+	if err != nil {
+		return dbo.Subnet{}, dbError("failed to prepare select statement", err)
+	}
+
+	var subnet dbo.Subnet
+
+	err = stmt.QueryRow(subnetID).Scan(
+		&subnet.ID,
+		&subnet.Name,
+		&subnet.CIDR,
+		&subnet.Gateway,
+		&subnet.VLAN,
+	)
+	if err == sql.ErrNoRows {
+		return dbo.Subnet{}, errors.NewNotFound("subnet not found")
+	} else if err != nil {
+		return dbo.Subnet{}, dbError("failed to query subnet", err)
+	}
+
+	subnet.ReservedRanges, err = r.getReservedRanges(subnetID)
+	if err != nil {
+		return dbo.Subnet{}, err
+	}
+
+	return subnet, nil
+}
+
+// GetAllSubnets retrieves all subnets including their reserved ranges ordered by their network address.
+func (r *Repository) GetAllSubnets() ([]dbo.Subnet, error) {
+	stmt, err := r.prepare(`SELECT ` + subnetColumns + ` FROM subnets ORDER BY cidr;`)
+	if err != nil {
+		return nil, dbError("failed to prepare select statement", err)
+	}
+
+	rows, err := stmt.Query()
+	if err != nil {
+		return nil, dbError("failed to query subnets", err)
+	}
+	defer rows.Close()
+
+	var subnets []dbo.Subnet
+
+	for rows.Next() {
+		var subnet dbo.Subnet
+
+		if err := rows.Scan(&subnet.ID, &subnet.Name, &subnet.CIDR, &subnet.Gateway, &subnet.VLAN); err != nil {
+			return nil, dbError("failed to scan row", err)
+		}
+
+		subnets = append(subnets, subnet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to iterate rows", err)
+	}
+
+	for i := range subnets {
+		subnets[i].ReservedRanges, err = r.getReservedRanges(subnets[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return subnets, nil
+}
+
+// UpdateSubnet updates an existing subnet and replaces its reserved ranges.
+// It returns a not found error if the subnet does not exist and a conflict error if it overlaps with another subnet.
+func (r *Repository) UpdateSubnet(subnetID int, data dbo.Subnet) error {
+	tx, err := r.begin()
+	if err != nil {
+		return dbError("failed to begin transaction", err)
+	}
+
+	defer tx.Rollback() // no-op after a successful commit
+
+	result, err := tx.Exec(`
+		UPDATE subnets
+		SET name = $1, cidr = $2, gateway = $3, vlan = $4
+		WHERE id = $5;`,
+		data.Name, data.CIDR, data.Gateway, data.VLAN, subnetID,
+	)
+	if isConstraintViolation(err, exclusionViolation) {
+		return errors.NewConflict("subnet overlaps with an existing subnet")
+	} else if err != nil {
+		return dbError("failed to execute update statement", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError("failed to get affected rows", err)
+	} else if n == 0 {
+		return errors.NewNotFound("subnet not found")
+	}
+
+	if _, err := tx.Exec(`DELETE FROM subnet_reserved_ranges WHERE subnet_id = $1;`, subnetID); err != nil {
+		return dbError("failed to delete reserved ranges", err)
+	}
+
+	if err := insertReservedRanges(tx.Tx, subnetID, data.ReservedRanges); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbError("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// DeleteSubnet removes a subnet together with its reserved ranges and IP allocations.
+// It returns an error if the deletion fails.
+func (r *Repository) DeleteSubnet(subnetID int) error {
+	stmt, err := r.prepare(`DELETE FROM subnets WHERE id = $1;`)
+	if err != nil {
+		return dbError("failed to prepare delete statement", err)
+	}
+
+	if _, err := stmt.Exec(subnetID); err != nil {
+		return dbError("failed to execute delete statement", err)
+	}
+
+	return nil
+}
+
+// GetUsedIPAddresses retrieves the distinct IP addresses of all network interfaces within the given subnet.
+func (r *Repository) GetUsedIPAddresses(subnetID int) ([]string, error) {
+	return r.queryIPAddresses(`
+		SELECT DISTINCT host(network_interfaces.ip_address)
+		FROM network_interfaces JOIN subnets ON network_interfaces.ip_address <<= subnets.cidr
+		WHERE subnets.id = $1;`, subnetID)
+}
+
+// GetAllocatedIPAddresses retrieves all IP addresses that have been allocated within the given subnet.
+func (r *Repository) GetAllocatedIPAddresses(subnetID int) ([]string, error) {
+	return r.queryIPAddresses(`SELECT host(ip_address) FROM ip_allocations WHERE subnet_id = $1;`, subnetID)
+}
+
+// AddIPAllocation reserves the given IP address within the given subnet. The allocation is released as soon as a
+// network interface uses the address, by DeleteIPAllocation or by DeleteIPAllocationsBefore. It returns a conflict
+// error if the address has already been allocated or is used by a network interface.
+func (r *Repository) AddIPAllocation(subnetID int, ipAddress string) error {
+	tx, err := r.begin()
+	if err != nil {
+		return dbError("failed to begin transaction", err)
+	}
+
+	defer tx.Rollback() // no-op after a successful commit
+
+	// Computers added with an automatically allocated address hold the same lock, see LockSubnet.
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1, $2);`, subnetLockClass, subnetID); err != nil {
+		return dbError("failed to lock subnet", err)
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO ip_allocations (subnet_id, ip_address)
+		SELECT $1::INTEGER, $2::INET
+		WHERE NOT EXISTS (SELECT 1 FROM network_interfaces WHERE ip_address = $2::INET);`,
+		subnetID, ipAddress,
+	)
+	if isConstraintViolation(err, uniqueViolation) {
+		return errors.NewConflict(fmt.Sprintf("IP address %s has already been allocated", ipAddress))
+	} else if err != nil {
+		return dbError("failed to insert IP allocation", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError("failed to get affected rows", err)
+	} else if n == 0 {
+		return errors.NewConflict(fmt.Sprintf("IP address %s is already used", ipAddress))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbError("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// DeleteIPAllocation releases the allocation of the given IP address within the given subnet. It returns a not found
+// error if the address is not allocated within the subnet.
+func (r *Repository) DeleteIPAllocation(subnetID int, ipAddress string) error {
+	stmt, err := r.prepare(`DELETE FROM ip_allocations WHERE subnet_id = $1 AND ip_address = $2::INET;`)
+	if err != nil {
+		return dbError("failed to prepare delete statement", err)
+	}
+
+	result, err := stmt.Exec(subnetID, ipAddress)
+	if err != nil {
+		return dbError("failed to execute delete statement", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError("failed to get affected rows", err)
+	} else if n == 0 {
+		return errors.NewNotFound(fmt.Sprintf("IP address %s is not allocated", ipAddress))
+	}
+
+	return nil
+}
+
+// DeleteIPAllocationsBefore releases all allocations made before the given time and returns their number.
+func (r *Repository) DeleteIPAllocationsBefore(before time.Time) (int, error) {
+	stmt, err := r.prepare(`DELETE FROM ip_allocations WHERE allocated_at < $1;`)
+	if err != nil {
+		return 0, dbError("failed to prepare delete statement", err)
+	}
+
+	result, err := stmt.Exec(before)
+	if err != nil {
+		return 0, dbError("failed to execute delete statement", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, dbError("failed to get affected rows", err)
+	}
+
+	return int(n), nil
+}
+
+// LockSubnet takes a transaction-level advisory lock on the subnet, so that concurrent transactions allocating
+// addresses of the subnet are serialized. The lock is released when the surrounding transaction of WithTx ends;
+// outside of it, the lock is released immediately.
+func (r *Repository) LockSubnet(subnetID int) error {
+	stmt, err := r.prepare(`SELECT pg_advisory_xact_lock($1, $2);`)
+	if err != nil {
+		return dbError("failed to prepare lock statement", err)
+	}
+
+	if _, err := stmt.Exec(subnetLockClass, subnetID); err != nil {
+		return dbError("failed to lock subnet", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) getReservedRanges(subnetID int) ([]dbo.IPRange, error) {
+	stmt, err := r.prepare(`
+		SELECT host(start_address), host(end_address), description
+		FROM subnet_reserved_ranges
+		WHERE subnet_id = $1
+		ORDER BY start_address;`)
+	if err != nil {
+		return nil, dbError("failed to prepare select statement", err)
+	}
+
+	rows, err := stmt.Query(subnetID)
+	if err != nil {
+		return nil, dbError("failed to query reserved ranges", err)
+	}
+	defer rows.Close()
+
+	var ranges []dbo.IPRange
+
+	for rows.Next() {
+		var ipRange dbo.IPRange
+
+		if err := rows.Scan(&ipRange.Start, &ipRange.End, &ipRange.Description); err != nil {
+			return nil, dbError("failed to scan row", err)
+		}
+
+		ranges = append(ranges, ipRange)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to iterate rows", err)
+	}
+
+	return ranges, nil
+}
+
+func (r *Repository) queryIPAddresses(query string, args ...any) ([]string, error) {
+	stmt, err := r.prepare(query)
+	if err != nil {
+		return nil, dbError("failed to prepare select statement", err)
+	}
+
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return nil, dbError("failed to query IP addresses", err)
+	}
+	defer rows.Close()
+
+	var ipAddresses []string
+
+	for rows.Next() {
+		var ipAddress string
+
+		if err := rows.Scan(&ipAddress); err != nil {
+			return nil, dbError("failed to scan row", err)
+		}
+
+		ipAddresses = append(ipAddresses, ipAddress)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to iterate rows", err)
+	}
+
+	return ipAddresses, nil
+}
+
+func insertReservedRanges(tx *sql.Tx, subnetID int, ranges []dbo.IPRange) error {
+	for _, ipRange := range ranges {
+		_, err := tx.Exec(`
+			INSERT INTO subnet_reserved_ranges (subnet_id, start_address, end_address, description)
+			VALUES ($1, $2, $3, $4);`,
+			subnetID, ipRange.Start, ipRange.End, ipRange.Description,
+		)
+		if err != nil {
+			return dbError("failed to insert reserved range", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"uhuaha/computers-management/internal/service"
+)
+
+var _ service.ComputerRepository = (*Repository)(nil)
+
+// The advisory locks of the repository are taken with two keys, the first of which tells what is locked. This
+// separates them from each other and from the single-key lock of the migrations.
+const (
+	ipAddressLockClass = 1
+	subnetLockClass    = 2
+	eventLogLockClass  = 3
+)
+
+// dbtx is implemented by both *sql.DB and *sql.Tx, so that the repository methods can run inside and outside
+// of a transaction.
+type dbtx interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// WithTx runs fn with a repository that executes all queries within one transaction. The transaction is committed
+// if fn returns nil and rolled back otherwise. Calling WithTx on the repository passed to fn runs the nested fn
+// within the same transaction.
+func (r *Repository) WithTx(ctx context.Context, fn func(repo service.ComputerRepository) error) error {
+	if r.tx != nil {
+		return fn(r)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return dbError("failed to begin transaction", err)
+	}
+
+	defer tx.Rollback() // no-op after a successful commit
+
+	if err := fn(&Repository{db: r.db, dbConn: tx, tx: tx, stmts: r.stmts, replica: r.replica}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbError("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// repositoryTx is a transaction used by a single repository method. If the repository runs within WithTx, the
+// method joins the surrounding transaction and committing or rolling it back is left to WithTx.
+type repositoryTx struct {
+	*sql.Tx
+	owned bool
+}
+
+func (t *repositoryTx) Commit() error {
+	if !t.owned {
+		return nil
+	}
+
+	return t.Tx.Commit()
+}
+
+func (t *repositoryTx) Rollback() error {
+	if !t.owned {
+		return nil
+	}
+
+	return t.Tx.Rollback()
+}
+
+// begin starts the transaction of a repository method or joins the one of WithTx.
+func (r *Repository) begin() (*repositoryTx, error) {
+	if r.tx != nil {
+		return &repositoryTx{Tx: r.tx}, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	return &repositoryTx{Tx: tx, owned: true}, nil
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"time"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+)
+
+// ClaimExpiringWarranties retrieves all computers that are not retired and whose warranty ends between from and to
+// (inclusive) and marks them as notified for their current warranty end. Computers that have already been claimed
+// for the same warranty end are skipped, so every computer is returned at most once per warranty period even if
+// several checks run concurrently.
+func (r *Repository) ClaimExpiringWarranties(from, to time.Time) ([]dbo.Computer, error) {
+	stmt, err := r.prepare(`
+		WITH claimed AS (
+			INSERT INTO warranty_notifications (computer_id, warranty_end)
+			SELECT id, warranty_end
+			FROM computers
+			WHERE warranty_end BETWEEN $1 AND $2 AND status <> 'retired'
+			ON CONFLICT DO NOTHING
+			RETURNING computer_id
+		)
+		SELECT ` + computerColumns + `
+		FROM computers
+		WHERE id IN (SELECT computer_id FROM claimed)
+		ORDER BY warranty_end, id;`)
+	if err != nil {
+		return nil, dbError("failed to prepare claim statement", err)
+	}
+
+	rows, err := stmt.Query(from, to)
+	if err != nil {
+		return nil, dbError("failed to claim expiring warranties", err)
+	}
+	defer rows.Close()
+
+	return scanComputers(rows)
+}
+
+// ReleaseWarrantyClaim removes the claim of a computer's warranty end, so that the next call of
+// ClaimExpiringWarranties returns the computer again.
+func (r *Repository) ReleaseWarrantyClaim(computerID int, warrantyEnd time.Time) error {
+	stmt, err := r.prepare(`DELETE FROM warranty_notifications WHERE computer_id = $1 AND warranty_end = $2;`)
+	if err != nil {
+		return dbError("failed to prepare delete statement", err)
+	}
+
+	if _, err := stmt.Exec(computerID, warrantyEnd); err != nil {
+		return dbError("failed to release warranty claim", err)
+	}
+
+	return nil
+}
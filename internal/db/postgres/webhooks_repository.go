@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/errors"
+
+	"github.com/lib/pq"
+)
+
+// webhookSubscriptionColumns lists the selected columns of the webhook_subscriptions table in the order expected
+// by scanWebhookSubscription.
+const webhookSubscriptionColumns = `id, url, event_types, secret, created_at`
+
+// AddWebhookSubscription inserts a new webhook subscription and returns its generated ID.
+func (r *Repository) AddWebhookSubscription(subscription dbo.WebhookSubscription) (int, error) {
+	stmt, err := r.prepare(`
+		INSERT INTO webhook_subscriptions (url, event_types, secret)
+		VALUES ($1, $2, $3)
+		RETURNING id;`)
+	if err != nil {
+		return 0, dbError("failed to prepare insert statement", err)
+	}
+
+	var subscriptionID int
+
+	err = stmt.QueryRow(subscription.URL, pq.Array(subscription.EventTypes), subscription.Secret).Scan(&subscriptionID)
+	if err != nil {
+		return 0, dbError("failed to insert webhook subscription", err)
+	}
+
+	return subscriptionID, nil
+}
+
+// GetWebhookSubscription retrieves a webhook subscription by its ID. It returns a not found error if it does not exist.
+func (r *Repository) GetWebhookSubscription(subscriptionID int) (dbo.WebhookSubscription, error) {
+	stmt, err := r.prepare(`SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1;`)
+	if err != nil {
+		return dbo.WebhookSubscription{}, dbError("failed to prepare select statement", err)
+	}
+
+	subscription, err := scanWebhookSubscription(stmt.QueryRow(subscriptionID))
+	if err == sql.ErrNoRows {
+		return dbo.WebhookSubscription{}, errors.NewNotFound("webhook subscription not found")
+	} else if err != nil {
+		return dbo.WebhookSubscription{}, dbError("failed to query webhook subscription", err)
+	}
+
+	return subscription, nil
+}
+
+// GetAllWebhookSubscriptions retrieves all webhook subscriptions ordered by their IDs.
+func (r *Repository) GetAllWebhookSubscriptions(ctx context.Context) ([]dbo.WebhookSubscription, error) {
+	stmt, err := r.prepare(`SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions ORDER BY id;`)
+	if err != nil {
+		return nil, dbError("failed to prepare select statement", err)
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, dbError("failed to query webhook subscriptions", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []dbo.WebhookSubscription
+
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, dbError("failed to scan row", err)
+		}
+
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to iterate rows", err)
+	}
+
+	return subscriptions, nil
+}
+
+// UpdateWebhookSubscription updates the URL, event types and secret of a webhook subscription. It returns a not
+// found error if the subscription does not exist.
+func (r *Repository) UpdateWebhookSubscription(subscriptionID int, data dbo.WebhookSubscription) error {
+	stmt, err := r.prepare(`UPDATE webhook_subscriptions SET url = $1, event_types = $2, secret = $3 WHERE id = $4;`)
+	if err != nil {
+		return dbError("failed to prepare update statement", err)
+	}
+
+	result, err := stmt.Exec(data.URL, pq.Array(data.EventTypes), data.Secret, subscriptionID)
+	if err != nil {
+		return dbError("failed to execute update statement", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError("failed to get affected rows", err)
+	} else if n == 0 {
+		return errors.NewNotFound("webhook subscription not found")
+	}
+
+	return nil
+}
+
+// DeleteWebhookSubscription removes a webhook subscription together with its delivery log. It returns a not found
+// error if the subscription does not exist.
+func (r *Repository) DeleteWebhookSubscription(subscriptionID int) error {
+	stmt, err := r.prepare(`DELETE FROM webhook_subscriptions WHERE id = $1;`)
+	if err != nil {
+		return dbError("failed to prepare delete statement", err)
+	}
+
+	result, err := stmt.Exec(subscriptionID)
+	if err != nil {
+		return dbError("failed to execute delete statement", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return dbError("failed to get affected rows", err)
+	} else if n == 0 {
+		return errors.NewNotFound("webhook subscription not found")
+	}
+
+	return nil
+}
+
+// AddWebhookDelivery records an attempt to deliver an event and returns it together with its generated ID and time.
+func (r *Repository) AddWebhookDelivery(delivery dbo.WebhookDelivery) (dbo.WebhookDelivery, error) {
+	stmt, err := r.prepare(`
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, attempt, status_code, error, succeeded, duration_ms)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, delivered_at;`)
+	if err != nil {
+		return dbo.WebhookDelivery{}, dbError("failed to prepare insert statement", err)
+	}
+
+	err = stmt.QueryRow(
+		delivery.SubscriptionID, delivery.EventID, delivery.EventType, delivery.Attempt,
+		delivery.StatusCode, delivery.Error, delivery.Succeeded, delivery.DurationMS,
+	).Scan(&delivery.ID, &delivery.DeliveredAt)
+	if err != nil {
+		return dbo.WebhookDelivery{}, dbError("failed to insert webhook delivery", err)
+	}
+
+	return delivery, nil
+}
+
+// GetWebhookDeliveries retrieves at most limit delivery attempts of a webhook subscription ordered from newest
+// to oldest.
+func (r *Repository) GetWebhookDeliveries(subscriptionID, limit int) ([]dbo.WebhookDelivery, error) {
+	stmt, err := r.prepare(`
+		SELECT id, subscription_id, event_id, event_type, attempt, status_code, error, succeeded, duration_ms, delivered_at
+		FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY id DESC
+		LIMIT $2;`)
+	if err != nil {
+		return nil, dbError("failed to prepare select statement", err)
+	}
+
+	rows, err := stmt.Query(subscriptionID, limit)
+	if err != nil {
+		return nil, dbError("failed to query webhook deliveries", err)
+	}
+	defer rows.Close()
+
+	var deliveries []dbo.WebhookDelivery
+
+	for rows.Next() {
+		var d dbo.WebhookDelivery
+
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Attempt, &d.StatusCode, &d.Error,
+			&d.Succeeded, &d.DurationMS, &d.DeliveredAt); err != nil {
+			return nil, dbError("failed to scan row", err)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to iterate rows", err)
+	}
+
+	return deliveries, nil
+}
+
+func scanWebhookSubscription(row rowScanner) (dbo.WebhookSubscription, error) {
+	var subscription dbo.WebhookSubscription
+
+	err := row.Scan(&subscription.ID, &subscription.URL, pq.Array(&subscription.EventTypes), &subscription.Secret, &subscription.CreatedAt)
+
+	return subscription, err
+}
@@ -11,3 +11,116 @@ func (e *NotFoundError) Error() string {
 func NewNotFound(msg string) error {
 	return &NotFoundError{Msg: msg}
 }
+
+type ConflictError struct {
+	Msg string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Msg
+}
+
+func NewConflict(msg string) error {
+	return &ConflictError{Msg: msg}
+}
+
+// InvalidTransitionError reports a status transition that is not allowed from the current status.
+// Allowed lists the statuses that can be reached instead.
+type InvalidTransitionError struct {
+	Msg     string
+	Allowed []string
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return e.Msg
+}
+
+func NewInvalidTransition(msg string, allowed []string) error {
+	return &InvalidTransitionError{Msg: msg, Allowed: allowed}
+}
+
+// ValidationError reports invalid input of a client, e.g. a malformed request body or an invalid parameter.
+type ValidationError struct {
+	Msg string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Msg
+}
+
+func NewValidation(msg string) error {
+	return &ValidationError{Msg: msg}
+}
+
+// PreconditionFailedError reports that a resource is not in the state an operation requires.
+type PreconditionFailedError struct {
+	Msg string
+}
+
+func (e *PreconditionFailedError) Error() string {
+	return e.Msg
+}
+
+func NewPreconditionFailed(msg string) error {
+	return &PreconditionFailedError{Msg: msg}
+}
+
+// ForbiddenError reports an operation that the service is not permitted to perform.
+type ForbiddenError struct {
+	Msg string
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Msg
+}
+
+func NewForbidden(msg string) error {
+	return &ForbiddenError{Msg: msg}
+}
+
+// UnavailableError reports that a dependency such as the database is temporarily unavailable, so that the
+// operation may succeed if it is retried later. Err is the optional cause, which is not meant to be shown to
+// clients.
+type UnavailableError struct {
+	Msg string
+	Err error
+}
+
+func (e *UnavailableError) Error() string {
+	if e.Err == nil {
+		return e.Msg
+	}
+
+	return e.Msg + ": " + e.Err.Error()
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+func NewUnavailable(msg string, err error) error {
+	return &UnavailableError{Msg: msg, Err: err}
+}
+
+// TimeoutError reports that an operation has not completed in time. Err is the optional cause, which is not
+// meant to be shown to clients.
+type TimeoutError struct {
+	Msg string
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Err == nil {
+		return e.Msg
+	}
+
+	return e.Msg + ": " + e.Err.Error()
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+func NewTimeout(msg string, err error) error {
+	return &TimeoutError{Msg: msg, Err: err}
+}
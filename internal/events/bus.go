@@ -0,0 +1,87 @@
+// Package events distributes the events of committed changes to the subscribers within the process.
+package events
+
+import (
+	"sync"
+	"uhuaha/computers-management/internal/model"
+)
+
+// Bus delivers published computer events to all current subscribers. Publishing never blocks: a subscriber whose
+// buffer is full is dropped and its channel is closed, so that a slow client cannot hold up the service. Dropped
+// subscribers resume from the persisted event log.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	bufferSize  int
+}
+
+// Subscription receives the events published after it has been created.
+type Subscription struct {
+	bus    *Bus
+	events chan model.ComputerEvent
+}
+
+// NewBus returns a bus whose subscribers buffer up to bufferSize events.
+func NewBus(bufferSize int) *Bus {
+	return &Bus{
+		subscribers: make(map[*Subscription]struct{}),
+		bufferSize:  bufferSize,
+	}
+}
+
+// Subscribe registers a new subscriber. It has to be closed once the events are no longer received.
+func (b *Bus) Subscribe() *Subscription {
+	sub := &Subscription{
+		bus:    b,
+		events: make(chan model.ComputerEvent, b.bufferSize),
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+// Publish delivers the events in their order to all subscribers.
+func (b *Bus) Publish(events ...model.ComputerEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		for _, event := range events {
+			select {
+			case sub.events <- event:
+			default:
+				b.remove(sub)
+			}
+
+			if _, ok := b.subscribers[sub]; !ok {
+				break
+			}
+		}
+	}
+}
+
+// Events returns the channel the events are delivered on. It is closed if the subscriber has been dropped or closed.
+func (s *Subscription) Events() <-chan model.ComputerEvent {
+	return s.events
+}
+
+// Close unregisters the subscriber. Closing it more than once has no effect.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	s.bus.remove(s)
+}
+
+// remove unregisters the subscriber and closes its channel. The caller must hold the lock.
+func (b *Bus) remove(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+
+	delete(b.subscribers, sub)
+	close(sub.events)
+}
@@ -0,0 +1,75 @@
+package events
+
+import (
+	"testing"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func receive(sub *Subscription) []int64 {
+	var ids []int64
+
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return ids
+			}
+
+			ids = append(ids, event.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestBusDeliversEventsToAllSubscribers(t *testing.T) {
+	bus := NewBus(10)
+
+	first := bus.Subscribe()
+	defer first.Close()
+
+	second := bus.Subscribe()
+	defer second.Close()
+
+	bus.Publish(model.ComputerEvent{ID: 1}, model.ComputerEvent{ID: 2})
+
+	assert.Equal(t, []int64{1, 2}, receive(first))
+	assert.Equal(t, []int64{1, 2}, receive(second))
+}
+
+func TestBusDropsSubscriberThatFallsBehind(t *testing.T) {
+	bus := NewBus(2)
+
+	slow := bus.Subscribe()
+	defer slow.Close()
+
+	fast := bus.Subscribe()
+	defer fast.Close()
+
+	bus.Publish(model.ComputerEvent{ID: 1}, model.ComputerEvent{ID: 2})
+	assert.Equal(t, []int64{1, 2}, receive(fast))
+
+	bus.Publish(model.ComputerEvent{ID: 3})
+
+	// The slow subscriber receives the buffered events before its channel is closed.
+	assert.Equal(t, []int64{1, 2}, receive(slow))
+	_, open := <-slow.Events()
+	assert.False(t, open)
+
+	assert.Equal(t, []int64{3}, receive(fast))
+}
+
+func TestSubscriptionCloseIsIdempotent(t *testing.T) {
+	bus := NewBus(1)
+
+	sub := bus.Subscribe()
+	sub.Close()
+	sub.Close()
+
+	bus.Publish(model.ComputerEvent{ID: 1})
+
+	_, open := <-sub.Events()
+	assert.False(t, open)
+}
@@ -7,9 +7,12 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 	"uhuaha/computers-management/internal/mocks"
 	"uhuaha/computers-management/internal/model"
 
+	errs "uhuaha/computers-management/internal/errors"
+
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -83,7 +86,26 @@ func TestAddComputerHandler(t *testing.T) {
 					Return(0, fmt.Errorf("something went wrong in the service layer"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"Failed to add computer"}`,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+		{
+			name: "service layer rejects duplicate IP address",
+			requestBody: `{
+                    "name": "TestPC",
+                    "ip_address": "192.168.0.1",
+                    "mac_address": "AA:BB:CC:DD:EE:FF"
+                }`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					AddComputer(model.Computer{
+						Name:       "TestPC",
+						IPAddress:  "192.168.0.1",
+						MACAddress: "AA:BB:CC:DD:EE:FF",
+					}).
+					Return(0, fmt.Errorf("failed to add a computer: %w", errs.NewConflict("IP address 192.168.0.1 is already used by computer with ID=1")))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"type":"/problems/conflict","title":"Conflict","status":409,"detail":"IP address 192.168.0.1 is already used by computer with ID=1","code":"conflict"}`,
 		},
 		{
 			name: "invalid request: employee abbreviation is not 3 characters long",
@@ -98,7 +120,159 @@ func TestAddComputerHandler(t *testing.T) {
 				// No service call expected because of prior validation error
 			},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"Invalid employee abbreviation"}`,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid employee abbreviation","code":"validation_failed"}`,
+		},
+		{
+			name: "invalid request: employee abbreviation has 3 bytes but 2 characters",
+			requestBody: `{
+                    "name": "TestPC",
+                    "ip_address": "192.168.0.1",
+                    "mac_address": "AA:BB:CC:DD:EE:FF",
+                    "employee_abbreviation": "ÄB"
+                }`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				// No service call expected because of prior validation error
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid employee abbreviation","code":"validation_failed"}`,
+		},
+		{
+			name: "invalid request: IP address is malformed",
+			requestBody: `{
+                    "name": "TestPC",
+                    "ip_address": "192.168.0.256",
+                    "mac_address": "AA:BB:CC:DD:EE:FF"
+                }`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				// No service call expected because of prior validation error
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid IP address","code":"validation_failed"}`,
+		},
+		{
+			name: "invalid request: MAC address is malformed",
+			requestBody: `{
+                    "name": "TestPC",
+                    "ip_address": "192.168.0.1",
+                    "mac_address": "AA:BB:CC:DD:EE:GG"
+                }`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				// No service call expected because of prior validation error
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid MAC address","code":"validation_failed"}`,
+		},
+		{
+			name: "valid JSON with automatic IP address allocation",
+			requestBody: `{
+                    "name": "TestPC",
+                    "ip_address": "auto",
+                    "mac_address": "AA:BB:CC:DD:EE:FF",
+                    "subnet_id": 7
+                }`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					AddComputer(model.Computer{
+						Name:       "TestPC",
+						IPAddress:  model.AutoIPAddress,
+						MACAddress: "AA:BB:CC:DD:EE:FF",
+						SubnetID:   intToPointer(7),
+					}).
+					Return(3, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"id":3}`,
+		},
+		{
+			name: "invalid request: automatic IP address allocation without subnet",
+			requestBody: `{
+                    "name": "TestPC",
+                    "ip_address": "auto",
+                    "mac_address": "AA:BB:CC:DD:EE:FF"
+                }`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				// No service call expected because of prior validation error
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Missing subnet ID for automatic IP address allocation","code":"validation_failed"}`,
+		},
+		{
+			name: "valid JSON with lifecycle attributes",
+			requestBody: `{
+                    "name": "LabPC",
+                    "ip_address": "10.0.0.3",
+                    "mac_address": "11:22:33:44:55:77",
+                    "serial_number": "SN-123",
+                    "purchase_date": "2024-01-15",
+                    "warranty_end": "2027-01-15",
+                    "vendor": "Lenovo",
+                    "model": "ThinkCentre M70q",
+                    "operating_system": "Ubuntu 24.04",
+                    "location": "Berlin"
+                }`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				purchaseDate := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+				warrantyEnd := time.Date(2027, 1, 15, 0, 0, 0, 0, time.UTC)
+				m.EXPECT().
+					AddComputer(model.Computer{
+						Name:            "LabPC",
+						IPAddress:       "10.0.0.3",
+						MACAddress:      "11:22:33:44:55:77",
+						SerialNumber:    toPointer("SN-123"),
+						PurchaseDate:    &purchaseDate,
+						WarrantyEnd:     &warrantyEnd,
+						Vendor:          toPointer("Lenovo"),
+						Model:           toPointer("ThinkCentre M70q"),
+						OperatingSystem: toPointer("Ubuntu 24.04"),
+						Location:        toPointer("Berlin"),
+					}).
+					Return(3, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"id":3}`,
+		},
+		{
+			name: "invalid request: purchase date is malformed",
+			requestBody: `{
+                    "name": "LabPC",
+                    "ip_address": "10.0.0.3",
+                    "mac_address": "11:22:33:44:55:77",
+                    "purchase_date": "15.01.2024"
+                }`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				// No service call expected because the date is invalid
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid request body","code":"validation_failed"}`,
+		},
+		{
+			name: "invalid request: warranty ends before purchase",
+			requestBody: `{
+                    "name": "LabPC",
+                    "ip_address": "10.0.0.3",
+                    "mac_address": "11:22:33:44:55:77",
+                    "purchase_date": "2024-01-15",
+                    "warranty_end": "2023-01-15"
+                }`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				// No service call expected because the dates are inconsistent
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid warranty end: it must not be before the purchase date","code":"validation_failed"}`,
+		},
+		{
+			name: "invalid request: vendor is blank",
+			requestBody: `{
+                    "name": "LabPC",
+                    "ip_address": "10.0.0.3",
+                    "mac_address": "11:22:33:44:55:77",
+                    "vendor": "  "
+                }`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				// No service call expected because the vendor is invalid
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid vendor: it must be a non-empty string of at most 255 characters","code":"validation_failed"}`,
 		},
 		{
 			name: "invalid JSON request",
@@ -111,7 +285,7 @@ func TestAddComputerHandler(t *testing.T) {
 				// No service call expected because JSON is invalid
 			},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"Invalid request body"}`,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid request body","code":"validation_failed"}`,
 		},
 	}
 
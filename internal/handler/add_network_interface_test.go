@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddNetworkInterfaceHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockComputerMgmtService)
+
+	tests := []struct {
+		name                 string
+		urlParam             string
+		requestBody          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "success: interface is added",
+			urlParam:    "1",
+			requestBody: `{"name":"wlan0","mac_address":"AA:BB:CC:DD:EE:01","ip_address":"192.168.0.10","type":"wifi"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					AddNetworkInterface(1, model.NetworkInterface{
+						Name:       "wlan0",
+						MACAddress: "AA:BB:CC:DD:EE:01",
+						IPAddress:  toPointer("192.168.0.10"),
+						Type:       model.InterfaceTypeWiFi,
+					}).
+					Return(5, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"id":5}`,
+		},
+		{
+			name:        "success: type defaults to other and IP address is optional",
+			urlParam:    "1",
+			requestBody: `{"name":"vnet0","mac_address":"AA:BB:CC:DD:EE:02"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					AddNetworkInterface(1, model.NetworkInterface{
+						Name:       "vnet0",
+						MACAddress: "AA:BB:CC:DD:EE:02",
+						Type:       model.InterfaceTypeOther,
+					}).
+					Return(6, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"id":6}`,
+		},
+		{
+			name:                 "invalid computer ID",
+			urlParam:             "abc",
+			requestBody:          `{"name":"eth1","mac_address":"AA:BB:CC:DD:EE:01"}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter 'computerID'","code":"validation_failed"}`,
+		},
+		{
+			name:                 "invalid MAC address",
+			urlParam:             "1",
+			requestBody:          `{"name":"eth1","mac_address":"not-a-mac"}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid MAC address","code":"validation_failed"}`,
+		},
+		{
+			name:                 "invalid type",
+			urlParam:             "1",
+			requestBody:          `{"name":"eth1","mac_address":"AA:BB:CC:DD:EE:01","type":"token-ring"}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid interface type: it must be one of ethernet, wifi, virtual or other","code":"validation_failed"}`,
+		},
+		{
+			name:                 "primary interface without IP address",
+			urlParam:             "1",
+			requestBody:          `{"name":"eth1","mac_address":"AA:BB:CC:DD:EE:01","primary":true}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Missing IP address: the primary interface must have an IP address","code":"validation_failed"}`,
+		},
+		{
+			name:        "computer not found",
+			urlParam:    "2",
+			requestBody: `{"name":"eth1","mac_address":"AA:BB:CC:DD:EE:01"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					AddNetworkInterface(2, gomock.Any()).
+					Return(0, errs.NewNotFound("computer not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"computer not found","code":"not_found"}`,
+		},
+		{
+			name:        "MAC address already in use",
+			urlParam:    "1",
+			requestBody: `{"name":"eth1","mac_address":"AA:BB:CC:DD:EE:01"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					AddNetworkInterface(1, gomock.Any()).
+					Return(0, errs.NewConflict("MAC address or name is already used by another network interface"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"type":"/problems/conflict","title":"Conflict","status":409,"detail":"MAC address or name is already used by another network interface","code":"conflict"}`,
+		},
+		{
+			name:        "service error",
+			urlParam:    "1",
+			requestBody: `{"name":"eth1","mac_address":"AA:BB:CC:DD:EE:01"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					AddNetworkInterface(1, gomock.Any()).
+					Return(0, fmt.Errorf("database unavailable"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/computers/"+tt.urlParam+"/interfaces", strings.NewReader(tt.requestBody))
+			req = mux.SetURLVars(req, map[string]string{"computerID": tt.urlParam})
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockComputerMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := New(mockService)
+			handler.AddNetworkInterface(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
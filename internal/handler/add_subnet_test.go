@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddSubnetHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockSubnetMgmtService)
+
+	tests := []struct {
+		name                 string
+		requestBody          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "valid JSON with all fields",
+			requestBody: `{
+				"name": "Office",
+				"cidr": "10.0.0.0/24",
+				"gateway": "10.0.0.1",
+				"vlan": 10,
+				"reserved_ranges": [{"start": "10.0.0.2", "end": "10.0.0.9", "description": "Printers"}]
+			}`,
+			mockBehavior: func(m *mocks.MockSubnetMgmtService) {
+				m.EXPECT().
+					AddSubnet(model.Subnet{
+						Name:    "Office",
+						CIDR:    "10.0.0.0/24",
+						Gateway: toPointer("10.0.0.1"),
+						VLAN:    intToPointer(10),
+						ReservedRanges: []model.IPRange{
+							{Start: "10.0.0.2", End: "10.0.0.9", Description: toPointer("Printers")},
+						},
+					}).
+					Return(1, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"id":1}`,
+		},
+		{
+			name:        "subnet overlaps with an existing subnet",
+			requestBody: `{"name": "Office", "cidr": "10.0.0.0/24"}`,
+			mockBehavior: func(m *mocks.MockSubnetMgmtService) {
+				m.EXPECT().
+					AddSubnet(model.Subnet{Name: "Office", CIDR: "10.0.0.0/24", ReservedRanges: []model.IPRange{}}).
+					Return(0, fmt.Errorf("failed to add a subnet: %w", errs.NewConflict("subnet overlaps with an existing subnet")))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"type":"/problems/conflict","title":"Conflict","status":409,"detail":"subnet overlaps with an existing subnet","code":"conflict"}`,
+		},
+		{
+			name:        "service layer returns error",
+			requestBody: `{"name": "Office", "cidr": "10.0.0.0/24"}`,
+			mockBehavior: func(m *mocks.MockSubnetMgmtService) {
+				m.EXPECT().
+					AddSubnet(model.Subnet{Name: "Office", CIDR: "10.0.0.0/24", ReservedRanges: []model.IPRange{}}).
+					Return(0, fmt.Errorf("db failure"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+		{
+			name:                 "invalid request: CIDR has host bits set",
+			requestBody:          `{"name": "Office", "cidr": "10.0.0.5/24"}`,
+			mockBehavior:         func(m *mocks.MockSubnetMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid CIDR: it must be an IPv4 network address such as 10.0.0.0/24","code":"validation_failed"}`,
+		},
+		{
+			name:                 "invalid request: gateway outside of the subnet",
+			requestBody:          `{"name": "Office", "cidr": "10.0.0.0/24", "gateway": "10.0.1.1"}`,
+			mockBehavior:         func(m *mocks.MockSubnetMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid gateway: it must be an IP address within the subnet","code":"validation_failed"}`,
+		},
+		{
+			name:                 "invalid request: VLAN out of range",
+			requestBody:          `{"name": "Office", "cidr": "10.0.0.0/24", "vlan": 4095}`,
+			mockBehavior:         func(m *mocks.MockSubnetMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid VLAN: it must be between 1 and 4094","code":"validation_failed"}`,
+		},
+		{
+			name:                 "invalid request: reserved range end before start",
+			requestBody:          `{"name": "Office", "cidr": "10.0.0.0/24", "reserved_ranges": [{"start": "10.0.0.9", "end": "10.0.0.2"}]}`,
+			mockBehavior:         func(m *mocks.MockSubnetMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid reserved range: start and end must be IP addresses within the subnet with start <= end","code":"validation_failed"}`,
+		},
+		{
+			name:                 "invalid JSON request",
+			requestBody:          `{"name": "Office", `,
+			mockBehavior:         func(m *mocks.MockSubnetMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid request body","code":"validation_failed"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSubnetMgmtService := mocks.NewMockSubnetMgmtService(ctrl)
+			tt.mockBehavior(mockSubnetMgmtService)
+
+			req := httptest.NewRequest(http.MethodPost, "/subnets", strings.NewReader(tt.requestBody))
+			rec := httptest.NewRecorder()
+
+			handler := NewSubnetHandler(mockSubnetMgmtService)
+
+			// Act
+			handler.AddSubnet(rec, req)
+
+			// Assert
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
+
+func intToPointer(i int) *int {
+	return &i
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddWebhookSubscriptionHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockWebhookMgmtService)
+
+	tests := []struct {
+		name                 string
+		requestBody          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "valid JSON with a secret",
+			requestBody: `{"url": "https://cmdb.example.com/hooks", "event_types": ["computer.created", "computer.deleted"], "secret": "0123456789abcdef"}`,
+			mockBehavior: func(m *mocks.MockWebhookMgmtService) {
+				m.EXPECT().
+					AddWebhookSubscription(model.WebhookSubscription{
+						URL:        "https://cmdb.example.com/hooks",
+						EventTypes: []model.ComputerEventType{model.ComputerCreated, model.ComputerDeleted},
+						Secret:     "0123456789abcdef",
+					}).
+					Return(model.WebhookSubscription{ID: 1, Secret: "0123456789abcdef"}, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"id":1,"secret":"0123456789abcdef"}`,
+		},
+		{
+			name:        "valid JSON without a secret returns the generated one",
+			requestBody: `{"url": "http://tickets.internal/webhook", "event_types": ["computer.assigned"]}`,
+			mockBehavior: func(m *mocks.MockWebhookMgmtService) {
+				m.EXPECT().
+					AddWebhookSubscription(model.WebhookSubscription{
+						URL:        "http://tickets.internal/webhook",
+						EventTypes: []model.ComputerEventType{model.ComputerAssigned},
+					}).
+					Return(model.WebhookSubscription{ID: 2, Secret: "generated"}, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"id":2,"secret":"generated"}`,
+		},
+		{
+			name:                 "relative URL",
+			requestBody:          `{"url": "/hooks", "event_types": ["computer.created"]}`,
+			mockBehavior:         func(m *mocks.MockWebhookMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL: it must be an absolute http or https URL","code":"validation_failed"}`,
+		},
+		{
+			name:                 "missing event types",
+			requestBody:          `{"url": "https://cmdb.example.com/hooks"}`,
+			mockBehavior:         func(m *mocks.MockWebhookMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Missing event types","code":"validation_failed"}`,
+		},
+		{
+			name:               "unknown event type",
+			requestBody:        `{"url": "https://cmdb.example.com/hooks", "event_types": ["computer.stolen"]}`,
+			mockBehavior:       func(m *mocks.MockWebhookMgmtService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,
+				"detail":"Invalid event type 'computer.stolen': it must be one of computer.created, computer.updated, computer.deleted, computer.assigned","code":"validation_failed"}`,
+		},
+		{
+			name:                 "short secret",
+			requestBody:          `{"url": "https://cmdb.example.com/hooks", "event_types": ["computer.created"], "secret": "short"}`,
+			mockBehavior:         func(m *mocks.MockWebhookMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid secret: it must be at least 16 characters long","code":"validation_failed"}`,
+		},
+		{
+			name:                 "invalid JSON request",
+			requestBody:          `{"url": `,
+			mockBehavior:         func(m *mocks.MockWebhookMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid request body","code":"validation_failed"}`,
+		},
+		{
+			name:        "service returns error",
+			requestBody: `{"url": "https://cmdb.example.com/hooks", "event_types": ["computer.created"]}`,
+			mockBehavior: func(m *mocks.MockWebhookMgmtService) {
+				m.EXPECT().AddWebhookSubscription(gomock.Any()).Return(model.WebhookSubscription{}, fmt.Errorf("db failure"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockWebhookMgmtService := mocks.NewMockWebhookMgmtService(ctrl)
+			tt.mockBehavior(mockWebhookMgmtService)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(tt.requestBody))
+			rec := httptest.NewRecorder()
+
+			handler := NewWebhookHandler(mockWebhookMgmtService)
+
+			// Act
+			handler.AddWebhookSubscription(rec, req)
+
+			// Assert
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			assert.JSONEq(t, tt.expectedResponseBody, string(body))
+		})
+	}
+}
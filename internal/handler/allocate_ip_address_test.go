@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uhuaha/computers-management/internal/mocks"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocateIPAddressHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockSubnetMgmtService)
+
+	tests := []struct {
+		name                 string
+		urlParam             string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "success: returns the allocated IP address",
+			urlParam: "1",
+			mockBehavior: func(m *mocks.MockSubnetMgmtService) {
+				m.EXPECT().AllocateIPAddress(1).Return("10.0.0.10", nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"ip_address":"10.0.0.10"}`,
+		},
+		{
+			name:     "subnet not found",
+			urlParam: "2",
+			mockBehavior: func(m *mocks.MockSubnetMgmtService) {
+				m.EXPECT().AllocateIPAddress(2).Return("", &errs.NotFoundError{Msg: "subnet not found"})
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"subnet not found","code":"not_found"}`,
+		},
+		{
+			name:     "subnet exhausted",
+			urlParam: "3",
+			mockBehavior: func(m *mocks.MockSubnetMgmtService) {
+				m.EXPECT().AllocateIPAddress(3).Return("", errs.NewConflict("subnet with ID=3 has no free IP address"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"type":"/problems/conflict","title":"Conflict","status":409,"detail":"subnet with ID=3 has no free IP address","code":"conflict"}`,
+		},
+		{
+			name:     "service returns error",
+			urlParam: "4",
+			mockBehavior: func(m *mocks.MockSubnetMgmtService) {
+				m.EXPECT().AllocateIPAddress(4).Return("", fmt.Errorf("db failure"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+		{
+			name:                 "invalid subnetID in URL",
+			urlParam:             "abc",
+			mockBehavior:         func(m *mocks.MockSubnetMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter 'subnetID'","code":"validation_failed"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/subnets/"+tt.urlParam+"/allocate", nil)
+			req = mux.SetURLVars(req, map[string]string{"subnetID": tt.urlParam})
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockSubnetMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := NewSubnetHandler(mockService)
+			handler.AllocateIPAddress(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/bdlm/log"
+	"github.com/gorilla/mux"
+)
+
+// CheckOutComputer hands out an in stock computer to an employee and starts a new assignment.
+func (c *ComputerMgmtHandler) CheckOutComputer(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	paramComputerID := vars["computerID"]
+	computerID, err := strconv.Atoi(paramComputerID)
+	if err != nil {
+		log.Error("failed to parse URL parameter 'computerID': " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'computerID'"))
+		return
+	}
+
+	var data CheckOutRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Error("failed to decode the request body: " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid request body"))
+		return
+	}
+
+	if utf8.RuneCountInString(data.EmployeeAbbreviation) != 3 {
+		log.Error("failed to parse employee abbreviation: it must be a 3-characters string")
+		handleError(w, r, errs.NewValidation("Invalid employee abbreviation"))
+		return
+	}
+
+	if msg := validateActor(data.Actor); msg != "" {
+		log.Error("failed to validate check-out: " + msg)
+		handleError(w, r, errs.NewValidation(msg))
+		return
+	}
+
+	assignment, err := c.computerMgmtService.CheckOutComputer(computerID, data.EmployeeAbbreviation, data.Actor)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to check out computer: %w", err))
+		return
+	}
+
+	response := convertAssignmentModelToDTO(assignment)
+
+	writeResponse(w, r, http.StatusCreated, jsonFormat, response)
+}
+
+// CheckInComputer takes back an assigned computer and ends its active assignment.
+func (c *ComputerMgmtHandler) CheckInComputer(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	paramComputerID := vars["computerID"]
+	computerID, err := strconv.Atoi(paramComputerID)
+	if err != nil {
+		log.Error("failed to parse URL parameter 'computerID': " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'computerID'"))
+		return
+	}
+
+	var data CheckInRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Error("failed to decode the request body: " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid request body"))
+		return
+	}
+
+	if msg := validateActor(data.Actor); msg != "" {
+		log.Error("failed to validate check-in: " + msg)
+		handleError(w, r, errs.NewValidation(msg))
+		return
+	}
+
+	assignment, err := c.computerMgmtService.CheckInComputer(computerID, data.Actor)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to check in computer: %w", err))
+		return
+	}
+
+	response := convertAssignmentModelToDTO(assignment)
+
+	writeResponse(w, r, http.StatusOK, jsonFormat, response)
+}
+
+// GetAssignmentsByEmployee retrieves the current and past computer assignments of an employee.
+func (c *ComputerMgmtHandler) GetAssignmentsByEmployee(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateFormat(w, r, readFormats)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	employee := vars["employee"]
+	if utf8.RuneCountInString(employee) != 3 {
+		log.Error("failed to parse URL parameter 'employee': it must be a 3-characters string")
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'employee'"))
+		return
+	}
+
+	assignments, err := c.computerMgmtService.GetAssignmentsByEmployee(employee)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to get assignments by employee: %w", err))
+		return
+	}
+
+	response := convertAssignmentModelsToDTOs(assignments)
+
+	writeResponse(w, r, http.StatusOK, format, response)
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/bdlm/log"
+)
+
+// BatchUpdateComputers applies the same changes to all selected computers in one transaction.
+// With the query parameter dry_run=true the affected computers are returned without changing them.
+func (c *ComputerMgmtHandler) BatchUpdateComputers(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := parseDryRun(r.URL.Query())
+	if err != nil {
+		log.Error("failed to parse query parameters: " + err.Error())
+		handleError(w, r, errs.NewValidation(err.Error()))
+		return
+	}
+
+	var data BatchUpdateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Error("failed to decode the request body: " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid request body"))
+		return
+	}
+
+	selector, err := parseBatchSelector(data.BatchSelector)
+	if err != nil {
+		log.Error("failed to parse batch selection: " + err.Error())
+		handleError(w, r, errs.NewValidation(err.Error()))
+		return
+	}
+
+	if msg := validateComputerChanges(data.Changes); msg != "" {
+		log.Error("failed to validate batch changes: " + msg)
+		handleError(w, r, errs.NewValidation(msg))
+		return
+	}
+
+	computers, err := c.computerMgmtService.BatchUpdateComputers(selector, convertComputerChangesDTOToModel(data.Changes), dryRun)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to update computers: %w", err))
+		return
+	}
+
+	response := convertBatchResultToDTO(computers, dryRun)
+
+	writeResponse(w, r, http.StatusOK, jsonFormat, response)
+}
+
+// BatchDeleteComputers deletes all selected computers in one transaction.
+// With the query parameter dry_run=true the affected computers are returned without deleting them.
+func (c *ComputerMgmtHandler) BatchDeleteComputers(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := parseDryRun(r.URL.Query())
+	if err != nil {
+		log.Error("failed to parse query parameters: " + err.Error())
+		handleError(w, r, errs.NewValidation(err.Error()))
+		return
+	}
+
+	var data BatchDeleteRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Error("failed to decode the request body: " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid request body"))
+		return
+	}
+
+	selector, err := parseBatchSelector(data.BatchSelector)
+	if err != nil {
+		log.Error("failed to parse batch selection: " + err.Error())
+		handleError(w, r, errs.NewValidation(err.Error()))
+		return
+	}
+
+	computers, err := c.computerMgmtService.BatchDeleteComputers(selector, dryRun)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to delete computers: %w", err))
+		return
+	}
+
+	response := convertBatchResultToDTO(computers, dryRun)
+
+	writeResponse(w, r, http.StatusOK, jsonFormat, response)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchDeleteComputersHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockComputerMgmtService)
+
+	tests := []struct {
+		name                 string
+		query                string
+		requestBody          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "success: computers selected by IDs are deleted",
+			requestBody: `{"ids":[1]}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					BatchDeleteComputers(model.ComputerSelector{IDs: []int{1}}, false).
+					Return([]model.Computer{
+						{ID: 1, Name: "PC1", IPAddress: "192.168.0.1", MACAddress: "AA:BB:CC:DD:EE:01", Status: model.StatusRetired},
+					}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"dry_run":false,"computers":[
+				{"id":1,"name":"PC1","ip_address":"192.168.0.1","mac_address":"AA:BB:CC:DD:EE:01","status":"retired"}
+			]}`,
+		},
+		{
+			name:        "success: dry run with a filter",
+			query:       "?dry_run=true",
+			requestBody: `{"filter":{"vendor":"Dell","warranty_ends_before":"2020-12-31"}}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					BatchDeleteComputers(gomock.Any(), true).
+					Return([]model.Computer{}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"dry_run":true,"computers":[]}`,
+		},
+		{
+			name:                 "missing selection",
+			requestBody:          `{}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid selection: either 'ids' or 'filter' must be given","code":"validation_failed"}`,
+		},
+		{
+			name:                 "empty filter",
+			requestBody:          `{"filter":{"vendor":""}}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid filter: it must contain at least one non-empty attribute","code":"validation_failed"}`,
+		},
+		{
+			name:                 "invalid ID",
+			requestBody:          `{"ids":[1,-2]}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid selection: IDs must be positive","code":"validation_failed"}`,
+		},
+		{
+			name:        "unknown IDs",
+			requestBody: `{"ids":[9]}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					BatchDeleteComputers(gomock.Any(), false).
+					Return(nil, errs.NewNotFound("computers not found: 9"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"computers not found: 9","code":"not_found"}`,
+		},
+		{
+			name:        "filter matches too many computers",
+			requestBody: `{"filter":{"location":"Berlin"}}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					BatchDeleteComputers(gomock.Any(), false).
+					Return(nil, errs.NewValidation("the filter matches more than 1000 computers"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"the filter matches more than 1000 computers","code":"validation_failed"}`,
+		},
+		{
+			name:        "service error",
+			requestBody: `{"ids":[1]}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					BatchDeleteComputers(gomock.Any(), false).
+					Return(nil, fmt.Errorf("database unavailable"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/computers:batchDelete"+tt.query, strings.NewReader(tt.requestBody))
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockComputerMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := New(mockService)
+			handler.BatchDeleteComputers(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchUpdateComputersHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockComputerMgmtService)
+
+	tests := []struct {
+		name                 string
+		query                string
+		requestBody          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "success: computers selected by IDs are updated",
+			requestBody: `{"ids":[1,2],"changes":{"description":"Spare"}}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					BatchUpdateComputers(
+						model.ComputerSelector{IDs: []int{1, 2}},
+						model.ComputerChanges{Description: toPointer("Spare")},
+						false,
+					).
+					Return([]model.Computer{
+						{ID: 1, Name: "PC1", IPAddress: "192.168.0.1", MACAddress: "AA:BB:CC:DD:EE:01", Description: toPointer("Spare"), Status: model.StatusInStock},
+						{ID: 2, Name: "PC2", IPAddress: "192.168.0.2", MACAddress: "AA:BB:CC:DD:EE:02", Description: toPointer("Spare"), Status: model.StatusInStock},
+					}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"dry_run":false,"computers":[
+				{"id":1,"name":"PC1","ip_address":"192.168.0.1","mac_address":"AA:BB:CC:DD:EE:01","description":"Spare","status":"in_stock"},
+				{"id":2,"name":"PC2","ip_address":"192.168.0.2","mac_address":"AA:BB:CC:DD:EE:02","description":"Spare","status":"in_stock"}
+			]}`,
+		},
+		{
+			name:        "success: dry run with a filter",
+			query:       "?dry_run=true",
+			requestBody: `{"filter":{"location":"Berlin"},"changes":{"location":"Hamburg"}}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					BatchUpdateComputers(
+						model.ComputerSelector{Filter: &model.ComputerFilter{Location: toPointer("Berlin")}},
+						model.ComputerChanges{Location: toPointer("Hamburg")},
+						true,
+					).
+					Return([]model.Computer{}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"dry_run":true,"computers":[]}`,
+		},
+		{
+			name:                 "invalid dry run parameter",
+			query:                "?dry_run=maybe",
+			requestBody:          `{"ids":[1],"changes":{"location":"Hamburg"}}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid query parameter 'dry_run': it must be true or false","code":"validation_failed"}`,
+		},
+		{
+			name:                 "both IDs and filter",
+			requestBody:          `{"ids":[1],"filter":{"location":"Berlin"},"changes":{"location":"Hamburg"}}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid selection: either 'ids' or 'filter' must be given","code":"validation_failed"}`,
+		},
+		{
+			name:                 "unsupported filter attribute",
+			requestBody:          `{"filter":{"name":"PC1"},"changes":{"location":"Hamburg"}}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid filter attribute 'name': it is not supported","code":"validation_failed"}`,
+		},
+		{
+			name:                 "invalid date in filter",
+			requestBody:          `{"filter":{"warranty_ends_before":"soon"},"changes":{"location":"Hamburg"}}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid filter attribute 'warranty_ends_before': it must be a date formatted as YYYY-MM-DD","code":"validation_failed"}`,
+		},
+		{
+			name:                 "missing changes",
+			requestBody:          `{"ids":[1]}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Missing changes","code":"validation_failed"}`,
+		},
+		{
+			name:        "success: computers are reassigned to another employee",
+			requestBody: `{"ids":[1],"changes":{"employee_abbreviation":"NEW"}}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					BatchUpdateComputers(
+						model.ComputerSelector{IDs: []int{1}},
+						model.ComputerChanges{EmployeeAbbreviation: toPointer("NEW")},
+						false,
+					).
+					Return([]model.Computer{
+						{ID: 1, Name: "PC1", IPAddress: "192.168.0.1", MACAddress: "AA:BB:CC:DD:EE:01", EmployeeAbbreviation: toPointer("NEW"), Status: model.StatusAssigned},
+					}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"dry_run":false,"computers":[
+				{"id":1,"name":"PC1","ip_address":"192.168.0.1","mac_address":"AA:BB:CC:DD:EE:01","employee_abbreviation":"NEW","status":"assigned"}
+			]}`,
+		},
+		{
+			name:                 "invalid employee abbreviation",
+			requestBody:          `{"ids":[1],"changes":{"employee_abbreviation":"EMPLOYEE"}}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid employee abbreviation","code":"validation_failed"}`,
+		},
+		{
+			name:        "unknown IDs",
+			requestBody: `{"ids":[1,9],"changes":{"location":"Hamburg"}}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					BatchUpdateComputers(gomock.Any(), gomock.Any(), false).
+					Return(nil, errs.NewNotFound("computers not found: 9"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"computers not found: 9","code":"not_found"}`,
+		},
+		{
+			name:        "computers changed concurrently",
+			requestBody: `{"ids":[1,2],"changes":{"location":"Hamburg"}}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					BatchUpdateComputers(gomock.Any(), gomock.Any(), false).
+					Return(nil, errs.NewConflict("the selected computers have been changed concurrently"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"type":"/problems/conflict","title":"Conflict","status":409,"detail":"the selected computers have been changed concurrently","code":"conflict"}`,
+		},
+		{
+			name:        "computers cannot be assigned",
+			requestBody: `{"ids":[1,2],"changes":{"employee_abbreviation":"NEW"}}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					BatchUpdateComputers(gomock.Any(), gomock.Any(), false).
+					Return(nil, errs.NewConflict("computers cannot be assigned to an employee: 2 (in_repair)"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"type":"/problems/conflict","title":"Conflict","status":409,"detail":"computers cannot be assigned to an employee: 2 (in_repair)","code":"conflict"}`,
+		},
+		{
+			name:        "service error",
+			requestBody: `{"ids":[1],"changes":{"location":"Hamburg"}}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					BatchUpdateComputers(gomock.Any(), gomock.Any(), false).
+					Return(nil, fmt.Errorf("database unavailable"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/computers:batchUpdate"+tt.query, strings.NewReader(tt.requestBody))
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockComputerMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := New(mockService)
+			handler.BatchUpdateComputers(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckInComputerHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockComputerMgmtService)
+
+	startedAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	endedAt := time.Date(2025, 6, 30, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		urlParam             string
+		requestBody          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "success: computer is checked in",
+			urlParam:    "1",
+			requestBody: `{"actor":"ADM"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					CheckInComputer(1, "ADM").
+					Return(model.Assignment{ID: 4, ComputerID: 1, ComputerName: "PC1", EmployeeAbbreviation: "EMP", StartedAt: startedAt, EndedAt: &endedAt}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"id":4,"computer_id":1,"computer_name":"PC1","employee_abbreviation":"EMP",
+				"started_at":"2025-03-01T09:00:00Z","ended_at":"2025-06-30T17:00:00Z","active":false}`,
+		},
+		{
+			name:                 "invalid computer ID",
+			urlParam:             "abc",
+			requestBody:          `{"actor":"ADM"}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter 'computerID'","code":"validation_failed"}`,
+		},
+		{
+			name:                 "missing actor",
+			urlParam:             "1",
+			requestBody:          `{}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid actor: it must be a non-empty string of at most 255 characters","code":"validation_failed"}`,
+		},
+		{
+			name:        "computer is not checked out",
+			urlParam:    "2",
+			requestBody: `{"actor":"ADM"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					CheckInComputer(2, "ADM").
+					Return(model.Assignment{}, errs.NewConflict(`computer with ID=2 cannot be checked in while its status is "in_stock"`))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"type":"/problems/conflict","title":"Conflict","status":409,"detail":"computer with ID=2 cannot be checked in while its status is \"in_stock\"","code":"conflict"}`,
+		},
+		{
+			name:        "service error",
+			urlParam:    "1",
+			requestBody: `{"actor":"ADM"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					CheckInComputer(1, "ADM").
+					Return(model.Assignment{}, fmt.Errorf("database unavailable"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/computers/"+tt.urlParam+"/checkin", strings.NewReader(tt.requestBody))
+			req = mux.SetURLVars(req, map[string]string{"computerID": tt.urlParam})
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockComputerMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := New(mockService)
+			handler.CheckInComputer(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
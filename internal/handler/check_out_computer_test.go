@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckOutComputerHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockComputerMgmtService)
+
+	startedAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		urlParam             string
+		requestBody          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "success: computer is checked out",
+			urlParam:    "1",
+			requestBody: `{"employee_abbreviation":"EMP","actor":"ADM"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					CheckOutComputer(1, "EMP", "ADM").
+					Return(model.Assignment{ID: 4, ComputerID: 1, ComputerName: "PC1", EmployeeAbbreviation: "EMP", StartedAt: startedAt}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponseBody: `{"id":4,"computer_id":1,"computer_name":"PC1","employee_abbreviation":"EMP",
+				"started_at":"2025-03-01T09:00:00Z","active":true}`,
+		},
+		{
+			name:                 "invalid employee abbreviation",
+			urlParam:             "1",
+			requestBody:          `{"employee_abbreviation":"EMPLOYEE","actor":"ADM"}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid employee abbreviation","code":"validation_failed"}`,
+		},
+		{
+			name:                 "missing actor",
+			urlParam:             "1",
+			requestBody:          `{"employee_abbreviation":"EMP"}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid actor: it must be a non-empty string of at most 255 characters","code":"validation_failed"}`,
+		},
+		{
+			name:        "computer cannot be checked out",
+			urlParam:    "2",
+			requestBody: `{"employee_abbreviation":"EMP","actor":"ADM"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					CheckOutComputer(2, "EMP", "ADM").
+					Return(model.Assignment{}, errs.NewInvalidTransition(
+						`computer with ID=2 cannot be checked out while its status is "in_repair"`,
+						[]string{"in_stock", "lost", "retired"},
+					))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"type":"/problems/invalid_status_transition","title":"Invalid status transition","status":409,"detail":"computer with ID=2 cannot be checked out while its status is \"in_repair\"","code":"invalid_status_transition","allowed_next_states":["in_stock","lost","retired"]}`,
+		},
+		{
+			name:        "computer not found",
+			urlParam:    "3",
+			requestBody: `{"employee_abbreviation":"EMP","actor":"ADM"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					CheckOutComputer(3, "EMP", "ADM").
+					Return(model.Assignment{}, errs.NewNotFound("computer not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"computer not found","code":"not_found"}`,
+		},
+		{
+			name:        "service error",
+			urlParam:    "1",
+			requestBody: `{"employee_abbreviation":"EMP","actor":"ADM"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					CheckOutComputer(1, "EMP", "ADM").
+					Return(model.Assignment{}, fmt.Errorf("database unavailable"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/computers/"+tt.urlParam+"/checkout", strings.NewReader(tt.requestBody))
+			req = mux.SetURLVars(req, map[string]string{"computerID": tt.urlParam})
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockComputerMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := New(mockService)
+			handler.CheckOutComputer(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
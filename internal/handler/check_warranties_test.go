@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckWarrantiesHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockWarrantyMgmtService)
+
+	warrantyEnd := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "success: returns reported computers",
+			mockBehavior: func(m *mocks.MockWarrantyMgmtService) {
+				m.EXPECT().CheckWarranties().Return([]model.Computer{
+					{ID: 1, Name: "PC1", IPAddress: "192.168.0.1", MACAddress: "AA:BB:CC:DD:EE:FF", WarrantyEnd: &warrantyEnd},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"notified":1,"computers":[
+				{"id":1,"name":"PC1","ip_address":"192.168.0.1","mac_address":"AA:BB:CC:DD:EE:FF","warranty_end":"2025-03-10"}]}`,
+		},
+		{
+			name: "success: nothing to report",
+			mockBehavior: func(m *mocks.MockWarrantyMgmtService) {
+				m.EXPECT().CheckWarranties().Return(nil, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"notified":0,"computers":[]}`,
+		},
+		{
+			name: "service returns error",
+			mockBehavior: func(m *mocks.MockWarrantyMgmtService) {
+				m.EXPECT().CheckWarranties().Return(nil, fmt.Errorf("db failure"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/jobs/warranty-check", nil)
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockWarrantyMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := NewWarrantyHandler(mockService)
+			handler.CheckWarranties(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
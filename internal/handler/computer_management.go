@@ -3,11 +3,15 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 	"uhuaha/computers-management/internal/model"
+	"unicode/utf8"
 
 	errs "uhuaha/computers-management/internal/errors"
 
@@ -17,11 +21,27 @@ import (
 
 type ComputerMgmtService interface {
 	AddComputer(computer model.Computer) (int, error)
-	GetComputer(computerID int) (model.Computer, error)
-	GetAllComputers() ([]model.Computer, error)
+	GetComputersVersion(ctx context.Context) (model.ComputersVersion, error)
+	GetComputerVersion(ctx context.Context, computerID int) (model.ComputersVersion, error)
+	GetVersionedComputer(ctx context.Context, computerID int) (model.Computer, model.ComputersVersion, error)
+	GetVersionedComputers(ctx context.Context, filter model.ComputerFilter) ([]model.Computer, model.ComputersVersion, error)
 	UpdateComputer(computerID int, data model.Computer) error
-	GetComputersByEmployee(employee string) ([]model.Computer, error)
+	GetVersionedComputersByEmployee(ctx context.Context, employee string) ([]model.Computer, model.ComputersVersion, error)
 	DeleteComputer(computerID int) error
+	GetIPConflicts() ([]model.IPConflict, error)
+	SearchComputers(ctx context.Context, query string) ([]model.ComputerSearchResult, error)
+	TransitionComputerStatus(computerID int, transition model.StatusTransition) (model.StatusTransition, error)
+	GetStatusTransitions(computerID int) ([]model.StatusTransition, error)
+	GetNetworkInterfaces(computerID int) ([]model.NetworkInterface, error)
+	GetNetworkInterface(computerID, interfaceID int) (model.NetworkInterface, error)
+	AddNetworkInterface(computerID int, iface model.NetworkInterface) (int, error)
+	UpdateNetworkInterface(computerID, interfaceID int, data model.NetworkInterface) error
+	DeleteNetworkInterface(computerID, interfaceID int) error
+	CheckOutComputer(computerID int, employee, actor string) (model.Assignment, error)
+	CheckInComputer(computerID int, actor string) (model.Assignment, error)
+	GetAssignmentsByEmployee(employee string) ([]model.Assignment, error)
+	BatchUpdateComputers(selector model.ComputerSelector, changes model.ComputerChanges, dryRun bool) ([]model.Computer, error)
+	BatchDeleteComputers(selector model.ComputerSelector, dryRun bool) ([]model.Computer, error)
 }
 
 type ComputerMgmtHandler struct {
@@ -40,113 +60,125 @@ func (c *ComputerMgmtHandler) AddComputer(w http.ResponseWriter, r *http.Request
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
 		log.Error("failed to decode the request body: " + err.Error())
-		handleError(w, "Invalid request body", http.StatusBadRequest)
+		handleError(w, r, errs.NewValidation("Invalid request body"))
 		return
 	}
 
-	if data.EmployeeAbbreviation != nil && len(*data.EmployeeAbbreviation) != 3 {
+	if data.EmployeeAbbreviation != nil && utf8.RuneCountInString(*data.EmployeeAbbreviation) != 3 {
 		log.Error("failed to parse employee abbreviation: it must be a 3-characters string")
-		handleError(w, "Invalid employee abbreviation", http.StatusBadRequest)
+		handleError(w, r, errs.NewValidation("Invalid employee abbreviation"))
 		return
 	}
 
-	computer := model.Computer{
-		Name:                 data.Name,
-		IPAddress:            data.IPAddress,
-		MACAddress:           data.MACAddress,
-		EmployeeAbbreviation: data.EmployeeAbbreviation,
-		Description:          data.Description,
+	if data.IPAddress == model.AutoIPAddress {
+		if data.SubnetID == nil {
+			log.Error("failed to allocate IP address: subnet ID is missing")
+			handleError(w, r, errs.NewValidation("Missing subnet ID for automatic IP address allocation"))
+			return
+		}
+	} else if !isValidIPAddress(data.IPAddress) {
+		log.Error("failed to parse IP address: " + data.IPAddress)
+		handleError(w, r, errs.NewValidation("Invalid IP address"))
+		return
 	}
 
-	computerID, err := c.computerMgmtService.AddComputer(computer)
-	if err != nil {
-		log.Error("failed to add computer: " + err.Error())
-		handleError(w, "Failed to add computer", http.StatusInternalServerError)
+	if !isValidMACAddress(data.MACAddress) {
+		log.Error("failed to parse MAC address: " + data.MACAddress)
+		handleError(w, r, errs.NewValidation("Invalid MAC address"))
 		return
 	}
 
-	response := AddComputerResponse{
-		ID: computerID,
+	if msg := validateLifecycleAttributes(data.LifecycleAttributes, time.Now()); msg != "" {
+		log.Error("failed to validate lifecycle attributes: " + msg)
+		handleError(w, r, errs.NewValidation(msg))
+		return
 	}
 
-	res, err := json.Marshal(response)
+	computer := convertAddComputerRequestToModel(data)
+
+	computerID, err := c.computerMgmtService.AddComputer(computer)
 	if err != nil {
-		log.Error("failed to encode response: " + err.Error())
-		w.WriteHeader(http.StatusCreated)
+		handleError(w, r, fmt.Errorf("failed to add computer: %w", err))
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	if _, err := w.Write(res); err != nil {
-		log.Error("failed to write response body: " + err.Error())
+	response := AddComputerResponse{
+		ID: computerID,
 	}
+
+	writeResponse(w, r, http.StatusCreated, jsonFormat, response)
 }
 
 // GetComputer gets a computer's data by its ID.
 func (c *ComputerMgmtHandler) GetComputerByID(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateFormat(w, r, computerFormats)
+	if !ok {
+		return
+	}
+
 	vars := mux.Vars(r)
 
 	paramComputerID := vars["computerID"]
 	computerID, err := strconv.Atoi(paramComputerID)
 	if err != nil {
 		log.Error("failed to parse URL parameter computerID: " + err.Error())
-		handleError(w, "Invalid URL parameter", http.StatusBadRequest)
+		handleError(w, r, errs.NewValidation("Invalid URL parameter"))
 		return
 	}
 
-	computer, err := c.computerMgmtService.GetComputer(computerID)
-	if err != nil {
-		var nf *errs.NotFoundError
-		if errors.As(err, &nf) {
-			handleError(w, nf.Error(), http.StatusNotFound)
-			return
-		}
+	// A computer that does not exist is never current, so that the request is answered with 404 Not Found.
+	getVersion := func() (model.ComputersVersion, error) {
+		return c.computerMgmtService.GetComputerVersion(r.Context(), computerID)
+	}
 
-		log.Error("failed to get computer by ID: " + err.Error())
-		handleError(w, "Failed to get computer by ID", http.StatusInternalServerError)
+	if checkNotModified(w, r, format, getVersion) {
 		return
 	}
 
-	response := convertComputerModelToDTO(computer)
-
-	res, err := json.Marshal(response)
+	computer, version, err := c.computerMgmtService.GetVersionedComputer(r.Context(), computerID)
 	if err != nil {
-		log.Error("failed to encode response: " + err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		handleError(w, r, fmt.Errorf("failed to get computer by ID: %w", err))
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write(res); err != nil {
-		log.Error("failed to write response body: " + err.Error())
-	}
+	setCacheHeaders(w, format, version)
+
+	response := convertComputerModelToDTO(computer)
+
+	writeResponse(w, r, http.StatusOK, format, response)
 }
 
-// GetAllComputers retrieves all computers' data from the storage.
+// GetAllComputers retrieves all computers' data from the storage. The list can be filtered by the query parameters
+// serial_number, vendor, model, operating_system, location, purchased_after, purchased_before,
+// warranty_ends_after and warranty_ends_before.
 func (c *ComputerMgmtHandler) GetAllComputers(w http.ResponseWriter, r *http.Request) {
-	computers, err := c.computerMgmtService.GetAllComputers()
+	format, ok := negotiateFormat(w, r, computerFormats)
+	if !ok {
+		return
+	}
+
+	filter, err := parseComputerFilter(r.URL.Query())
 	if err != nil {
-		log.Error("failed to get all computers: " + err.Error())
-		handleError(w, "Failed to get all computers", http.StatusInternalServerError)
+		log.Error("failed to parse query parameters: " + err.Error())
+		handleError(w, r, errs.NewValidation(err.Error()))
 		return
 	}
 
-	response := convertComputerModelsToDTOs(computers)
+	if checkNotModified(w, r, format, c.getComputersVersion(r.Context())) {
+		return
+	}
 
-	res, err := json.Marshal(response)
+	computers, version, err := c.computerMgmtService.GetVersionedComputers(r.Context(), filter)
 	if err != nil {
-		log.Error("failed to encode response: " + err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		handleError(w, r, fmt.Errorf("failed to get all computers: %w", err))
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write(res); err != nil {
-		log.Error("failed to write response body: " + err.Error())
-	}
+	setCacheHeaders(w, format, version)
+
+	response := convertComputerModelsToDTOs(computers)
+
+	writeResponse(w, r, http.StatusOK, format, response)
 }
 
 // UpdateComputer updates a computer's data.
@@ -157,7 +189,7 @@ func (c *ComputerMgmtHandler) UpdateComputer(w http.ResponseWriter, r *http.Requ
 	computerID, err := strconv.Atoi(paramComputerID)
 	if err != nil {
 		log.Error("failed to parse URL parameter computerID: " + err.Error())
-		handleError(w, "Invalid URL parameter", http.StatusBadRequest)
+		handleError(w, r, errs.NewValidation("Invalid URL parameter"))
 		return
 	}
 
@@ -165,27 +197,38 @@ func (c *ComputerMgmtHandler) UpdateComputer(w http.ResponseWriter, r *http.Requ
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
 		log.Error("failed to decode the request body: " + err.Error())
-		handleError(w, "Invalid request body", http.StatusBadRequest)
+		handleError(w, r, errs.NewValidation("Invalid request body"))
 		return
 	}
 
-	if data.EmployeeAbbreviation != nil && len(*data.EmployeeAbbreviation) != 3 {
+	if data.EmployeeAbbreviation != nil && utf8.RuneCountInString(*data.EmployeeAbbreviation) != 3 {
 		log.Error("failed to parse employee abbreviation: it must be a 3-characters string")
-		handleError(w, "Invalid employee abbreviation", http.StatusBadRequest)
+		handleError(w, r, errs.NewValidation("Invalid employee abbreviation"))
+		return
+	}
+
+	if !isValidIPAddress(data.IPAddress) {
+		log.Error("failed to parse IP address: " + data.IPAddress)
+		handleError(w, r, errs.NewValidation("Invalid IP address"))
 		return
 	}
 
-	computer := model.Computer{
-		Name:                 data.Name,
-		IPAddress:            data.IPAddress,
-		MACAddress:           data.MACAddress,
-		EmployeeAbbreviation: data.EmployeeAbbreviation,
-		Description:          data.Description,
+	if !isValidMACAddress(data.MACAddress) {
+		log.Error("failed to parse MAC address: " + data.MACAddress)
+		handleError(w, r, errs.NewValidation("Invalid MAC address"))
+		return
+	}
+
+	if msg := validateLifecycleAttributes(data.LifecycleAttributes, time.Now()); msg != "" {
+		log.Error("failed to validate lifecycle attributes: " + msg)
+		handleError(w, r, errs.NewValidation(msg))
+		return
 	}
 
+	computer := convertUpdateComputerRequestToModel(data)
+
 	if err := c.computerMgmtService.UpdateComputer(computerID, computer); err != nil {
-		log.Error("failed to update computer: " + err.Error())
-		handleError(w, "Failed to update computer", http.StatusInternalServerError)
+		handleError(w, r, fmt.Errorf("failed to update computer: %w", err))
 		return
 	}
 
@@ -194,36 +237,35 @@ func (c *ComputerMgmtHandler) UpdateComputer(w http.ResponseWriter, r *http.Requ
 
 // GetComputersByEmployee retrieves all computers from storage that are assigned to a given employee.
 func (c *ComputerMgmtHandler) GetComputersByEmployee(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateFormat(w, r, computerFormats)
+	if !ok {
+		return
+	}
+
 	vars := mux.Vars(r)
 
 	employee := vars["employee"]
-	if len(employee) != 3 {
+	if utf8.RuneCountInString(employee) != 3 {
 		log.Error("failed to parse URL parameter 'employee': it must be a 3-characters string")
-		handleError(w, "Invalid URL parameter 'employee'", http.StatusBadRequest)
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'employee'"))
 		return
 	}
 
-	computers, err := c.computerMgmtService.GetComputersByEmployee(employee)
-	if err != nil {
-		log.Error("failed to get computers by employee: " + err.Error())
-		handleError(w, "Failed to get computers by employee", http.StatusInternalServerError)
+	if checkNotModified(w, r, format, c.getComputersVersion(r.Context())) {
 		return
 	}
 
-	response := convertComputerModelsToDTOs(computers)
-
-	res, err := json.Marshal(response)
+	computers, version, err := c.computerMgmtService.GetVersionedComputersByEmployee(r.Context(), employee)
 	if err != nil {
-		log.Error("failed to encode response: " + err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		handleError(w, r, fmt.Errorf("failed to get computers by employee: %w", err))
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write(res); err != nil {
-		log.Error("failed to write response body: " + err.Error())
-	}
+	setCacheHeaders(w, format, version)
+
+	response := convertComputerModelsToDTOs(computers)
+
+	writeResponse(w, r, http.StatusOK, format, response)
 }
 
 // DeleteComputer deletes a computer by its ID.
@@ -234,15 +276,129 @@ func (c *ComputerMgmtHandler) DeleteComputer(w http.ResponseWriter, r *http.Requ
 	computerID, err := strconv.Atoi(paramComputerID)
 	if err != nil {
 		log.Error("failed to parse URL parameter 'computerID': " + err.Error())
-		handleError(w, "Invalid URL parameter 'computerID'", http.StatusBadRequest)
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'computerID'"))
 		return
 	}
 
 	if err := c.computerMgmtService.DeleteComputer(computerID); err != nil {
-		log.Error("failed to delete computer: " + err.Error())
-		handleError(w, "Failed to delete computer", http.StatusInternalServerError)
+		handleError(w, r, fmt.Errorf("failed to delete computer: %w", err))
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// GetIPConflicts retrieves all IP addresses that are used by more than one computer together with the computers involved.
+func (c *ComputerMgmtHandler) GetIPConflicts(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateFormat(w, r, readFormats)
+	if !ok {
+		return
+	}
+
+	conflicts, err := c.computerMgmtService.GetIPConflicts()
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to get IP conflicts: %w", err))
+		return
+	}
+
+	response := convertIPConflictsToDTO(conflicts)
+
+	writeResponse(w, r, http.StatusOK, format, response)
+}
+
+// SearchComputers retrieves the computers matching the query parameter 'q' ordered by relevance.
+func (c *ComputerMgmtHandler) SearchComputers(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateFormat(w, r, readFormats)
+	if !ok {
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		log.Error("failed to parse query parameter 'q': it must not be empty")
+		handleError(w, r, errs.NewValidation("Missing query parameter 'q'"))
+		return
+	}
+
+	results, err := c.computerMgmtService.SearchComputers(r.Context(), query)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to search computers: %w", err))
+		return
+	}
+
+	response := convertSearchResultsToDTO(results)
+
+	writeResponse(w, r, http.StatusOK, format, response)
+}
+
+// TransitionComputerStatus changes a computer's status and records the reason and actor of the change.
+// A transition that is not allowed from the current status is rejected together with the allowed next states.
+func (c *ComputerMgmtHandler) TransitionComputerStatus(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	paramComputerID := vars["computerID"]
+	computerID, err := strconv.Atoi(paramComputerID)
+	if err != nil {
+		log.Error("failed to parse URL parameter 'computerID': " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'computerID'"))
+		return
+	}
+
+	var data AddStatusTransitionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Error("failed to decode the request body: " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid request body"))
+		return
+	}
+
+	if msg := validateStatusTransition(data); msg != "" {
+		log.Error("failed to validate status transition: " + msg)
+		handleError(w, r, errs.NewValidation(msg))
+		return
+	}
+
+	transition := model.StatusTransition{
+		To:     model.ComputerStatus(data.Status),
+		Reason: data.Reason,
+		Actor:  data.Actor,
+	}
+
+	transition, err = c.computerMgmtService.TransitionComputerStatus(computerID, transition)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to change computer status: %w", err))
+		return
+	}
+
+	response := convertStatusTransitionToDTO(transition)
+
+	writeResponse(w, r, http.StatusCreated, jsonFormat, response)
+}
+
+// GetStatusTransitions retrieves the status history of a computer.
+func (c *ComputerMgmtHandler) GetStatusTransitions(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateFormat(w, r, readFormats)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	paramComputerID := vars["computerID"]
+	computerID, err := strconv.Atoi(paramComputerID)
+	if err != nil {
+		log.Error("failed to parse URL parameter 'computerID': " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'computerID'"))
+		return
+	}
+
+	transitions, err := c.computerMgmtService.GetStatusTransitions(computerID)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to get status transitions: %w", err))
+		return
+	}
+
+	response := convertStatusTransitionsToDTO(transitions)
+
+	writeResponse(w, r, http.StatusOK, format, response)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/bdlm/log"
+)
+
+// checkNotModified answers a conditional request with 304 Not Modified and the caching headers of a computer
+// representation if the client's copy matches the current version of all computers returned by getVersion. It is
+// called before the computers are loaded, so that a 304 does not read them. It reports whether the response has been
+// written. If the version cannot be determined, e.g. since the requested computer does not exist, the request is
+// answered unconditionally.
+func checkNotModified(w http.ResponseWriter, r *http.Request, format responseFormat, getVersion func() (model.ComputersVersion, error)) bool {
+	version, err := getVersion()
+	if err != nil {
+		var notFound *errs.NotFoundError
+		if !errors.As(err, &notFound) {
+			log.Warn("failed to get computers version: " + err.Error())
+		}
+
+		return false
+	}
+
+	if !notModified(r, entityTag(format, version), lastModified(version)) {
+		return false
+	}
+
+	setCacheHeaders(w, format, version)
+	w.WriteHeader(http.StatusNotModified)
+
+	return true
+}
+
+// getComputersVersion returns a function retrieving the current version of all computers for checkNotModified.
+func (c *ComputerMgmtHandler) getComputersVersion(ctx context.Context) func() (model.ComputersVersion, error) {
+	return func() (model.ComputersVersion, error) {
+		return c.computerMgmtService.GetComputersVersion(ctx)
+	}
+}
+
+// setCacheHeaders sets the ETag, Last-Modified and Cache-Control headers of a computer representation based on
+// the version of all computers it has been read with and the format of the representation.
+func setCacheHeaders(w http.ResponseWriter, format responseFormat, version model.ComputersVersion) {
+	w.Header().Set("ETag", entityTag(format, version))
+	w.Header().Set("Last-Modified", lastModified(version).Format(http.TimeFormat))
+	// Clients may store the representation but have to revalidate it before every use.
+	w.Header().Set("Cache-Control", "private, no-cache")
+}
+
+// entityTag returns the weak ETag of a computer representation. The representations in different formats are
+// distinguished since they share the same URL.
+func entityTag(format responseFormat, version model.ComputersVersion) string {
+	return fmt.Sprintf(`W/"%d-%s"`, version.Version, format.name)
+}
+
+// lastModified returns the last modification time of a computer representation in the precision of HTTP dates.
+func lastModified(version model.ComputersVersion) time.Time {
+	return version.ChangedAt.UTC().Truncate(time.Second)
+}
+
+// notModified evaluates If-None-Match or, if it is absent, If-Modified-Since as described in RFC 9110.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			// ETags are compared weakly, i.e. regardless of their W/ prefix.
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.After(ifModifiedSince)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionalGetHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockComputerMgmtService)
+
+	changedAt := time.Date(2025, 3, 1, 12, 30, 15, 500, time.UTC)
+	version := model.ComputersVersion{Version: 7, ChangedAt: changedAt}
+
+	tests := []struct {
+		name                 string
+		headers              map[string]string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedETag         string
+		expectedLastModified string
+	}{
+		{
+			name: "unconditional request returns 200 with caching headers",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputersVersion(gomock.Any()).Return(version, nil)
+				m.EXPECT().GetVersionedComputers(gomock.Any(), model.ComputerFilter{}).Return([]model.Computer{}, version, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedETag:         `W/"7-json"`,
+			expectedLastModified: "Sat, 01 Mar 2025 12:30:15 GMT",
+		},
+		{
+			name:    "matching If-None-Match returns 304 without loading the computers",
+			headers: map[string]string{"If-None-Match": `W/"6-json", W/"7-json"`},
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputersVersion(gomock.Any()).Return(version, nil)
+			},
+			expectedStatusCode:   http.StatusNotModified,
+			expectedETag:         `W/"7-json"`,
+			expectedLastModified: "Sat, 01 Mar 2025 12:30:15 GMT",
+		},
+		{
+			name:    "outdated If-None-Match returns 200 and ignores If-Modified-Since",
+			headers: map[string]string{"If-None-Match": `W/"6-json"`, "If-Modified-Since": "Sat, 01 Mar 2025 12:30:15 GMT"},
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputersVersion(gomock.Any()).Return(version, nil)
+				m.EXPECT().GetVersionedComputers(gomock.Any(), model.ComputerFilter{}).Return([]model.Computer{}, version, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedETag:         `W/"7-json"`,
+			expectedLastModified: "Sat, 01 Mar 2025 12:30:15 GMT",
+		},
+		{
+			name:    "If-None-Match of another format returns 200",
+			headers: map[string]string{"Accept": "text/csv", "If-None-Match": `W/"7-json"`},
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputersVersion(gomock.Any()).Return(version, nil)
+				m.EXPECT().GetVersionedComputers(gomock.Any(), model.ComputerFilter{}).Return([]model.Computer{}, version, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedETag:         `W/"7-csv"`,
+			expectedLastModified: "Sat, 01 Mar 2025 12:30:15 GMT",
+		},
+		{
+			name:    "current If-Modified-Since returns 304",
+			headers: map[string]string{"If-Modified-Since": "Sat, 01 Mar 2025 12:30:15 GMT"},
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputersVersion(gomock.Any()).Return(version, nil)
+			},
+			expectedStatusCode:   http.StatusNotModified,
+			expectedETag:         `W/"7-json"`,
+			expectedLastModified: "Sat, 01 Mar 2025 12:30:15 GMT",
+		},
+		{
+			name:    "outdated If-Modified-Since returns 200",
+			headers: map[string]string{"If-Modified-Since": "Sat, 01 Mar 2025 12:30:14 GMT"},
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputersVersion(gomock.Any()).Return(version, nil)
+				m.EXPECT().GetVersionedComputers(gomock.Any(), model.ComputerFilter{}).Return([]model.Computer{}, version, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedETag:         `W/"7-json"`,
+			expectedLastModified: "Sat, 01 Mar 2025 12:30:15 GMT",
+		},
+		{
+			name:    "computers changed after the version check return 200 with the version they were read with",
+			headers: map[string]string{"If-None-Match": `W/"5-json"`},
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputersVersion(gomock.Any()).Return(model.ComputersVersion{Version: 6, ChangedAt: changedAt.Add(-time.Minute)}, nil)
+				m.EXPECT().GetVersionedComputers(gomock.Any(), model.ComputerFilter{}).Return([]model.Computer{}, version, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedETag:         `W/"7-json"`,
+			expectedLastModified: "Sat, 01 Mar 2025 12:30:15 GMT",
+		},
+		{
+			name:    "failing version check returns 200 without caching headers",
+			headers: map[string]string{"If-None-Match": `W/"7-json"`},
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputersVersion(gomock.Any()).Return(model.ComputersVersion{}, fmt.Errorf("db failure"))
+				m.EXPECT().GetVersionedComputers(gomock.Any(), model.ComputerFilter{}).Return([]model.Computer{}, version, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedETag:         `W/"7-json"`,
+			expectedLastModified: "Sat, 01 Mar 2025 12:30:15 GMT",
+		},
+		{
+			name:    "failing read returns 500 without caching headers",
+			headers: map[string]string{"If-None-Match": `W/"6-json"`},
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputersVersion(gomock.Any()).Return(version, nil)
+				m.EXPECT().GetVersionedComputers(gomock.Any(), model.ComputerFilter{}).Return(nil, model.ComputersVersion{}, fmt.Errorf("db failure"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockComputerMgmtService := mocks.NewMockComputerMgmtService(ctrl)
+			tt.mockBehavior(mockComputerMgmtService)
+
+			handler := New(mockComputerMgmtService)
+
+			req := httptest.NewRequest(http.MethodGet, "/computers", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.GetAllComputers(rec, req)
+
+			// Assert
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+			assert.Equal(t, tt.expectedETag, res.Header.Get("ETag"))
+			assert.Equal(t, tt.expectedLastModified, res.Header.Get("Last-Modified"))
+
+			if tt.expectedStatusCode == http.StatusNotModified {
+				body, _ := io.ReadAll(res.Body)
+				assert.Empty(t, body)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"time"
 	"uhuaha/computers-management/internal/model"
 )
 
@@ -12,6 +13,9 @@ func convertComputerModelToDTO(computer model.Computer) GetComputerByIDResponse
 		MACAddress:           computer.MACAddress,
 		EmployeeAbbreviation: computer.EmployeeAbbreviation,
 		Description:          computer.Description,
+		SubnetID:             computer.SubnetID,
+		Status:               string(computer.Status),
+		LifecycleAttributes:  convertLifecycleAttributesToDTO(computer),
 	}
 }
 
@@ -19,17 +23,349 @@ func convertComputerModelsToDTOs(computers []model.Computer) GetComputersRespons
 	computerDTOs := make([]GetComputerByIDResponse, len(computers))
 
 	for i, computer := range computers {
-		computerDTOs[i] = GetComputerByIDResponse{
-			ID:                   computer.ID,
-			Name:                 computer.Name,
-			IPAddress:            computer.IPAddress,
-			MACAddress:           computer.MACAddress,
-			EmployeeAbbreviation: computer.EmployeeAbbreviation,
-			Description:          computer.Description,
-		}
+		computerDTOs[i] = convertComputerModelToDTO(computer)
 	}
 
 	return GetComputersResponse{
 		Computers: computerDTOs,
 	}
 }
+
+func convertAddComputerRequestToModel(data AddComputerRequest) model.Computer {
+	computer := model.Computer{
+		Name:                 data.Name,
+		IPAddress:            data.IPAddress,
+		MACAddress:           data.MACAddress,
+		EmployeeAbbreviation: data.EmployeeAbbreviation,
+		Description:          data.Description,
+		SubnetID:             data.SubnetID,
+	}
+	setLifecycleAttributes(&computer, data.LifecycleAttributes)
+
+	return computer
+}
+
+func convertUpdateComputerRequestToModel(data UpdateComputerRequest) model.Computer {
+	computer := model.Computer{
+		Name:                 data.Name,
+		IPAddress:            data.IPAddress,
+		MACAddress:           data.MACAddress,
+		EmployeeAbbreviation: data.EmployeeAbbreviation,
+		Description:          data.Description,
+	}
+	setLifecycleAttributes(&computer, data.LifecycleAttributes)
+
+	return computer
+}
+
+func setLifecycleAttributes(computer *model.Computer, attrs LifecycleAttributes) {
+	computer.SerialNumber = attrs.SerialNumber
+	computer.PurchaseDate = dateToTime(attrs.PurchaseDate)
+	computer.WarrantyEnd = dateToTime(attrs.WarrantyEnd)
+	computer.Vendor = attrs.Vendor
+	computer.Model = attrs.Model
+	computer.OperatingSystem = attrs.OperatingSystem
+	computer.Location = attrs.Location
+}
+
+func convertLifecycleAttributesToDTO(computer model.Computer) LifecycleAttributes {
+	return LifecycleAttributes{
+		SerialNumber:    computer.SerialNumber,
+		PurchaseDate:    timeToDate(computer.PurchaseDate),
+		WarrantyEnd:     timeToDate(computer.WarrantyEnd),
+		Vendor:          computer.Vendor,
+		Model:           computer.Model,
+		OperatingSystem: computer.OperatingSystem,
+		Location:        computer.Location,
+	}
+}
+
+func dateToTime(d *Date) *time.Time {
+	if d == nil {
+		return nil
+	}
+
+	return &d.Time
+}
+
+func timeToDate(t *time.Time) *Date {
+	if t == nil {
+		return nil
+	}
+
+	return &Date{Time: *t}
+}
+
+func convertStatusTransitionToDTO(transition model.StatusTransition) StatusTransitionResponse {
+	return StatusTransitionResponse{
+		ID:         transition.ID,
+		ComputerID: transition.ComputerID,
+		FromStatus: string(transition.From),
+		ToStatus:   string(transition.To),
+		Reason:     transition.Reason,
+		Actor:      transition.Actor,
+		CreatedAt:  transition.CreatedAt,
+	}
+}
+
+func convertStatusTransitionsToDTO(transitions []model.StatusTransition) GetStatusTransitionsResponse {
+	transitionDTOs := make([]StatusTransitionResponse, len(transitions))
+
+	for i, transition := range transitions {
+		transitionDTOs[i] = convertStatusTransitionToDTO(transition)
+	}
+
+	return GetStatusTransitionsResponse{
+		Transitions: transitionDTOs,
+	}
+}
+
+func convertComputerChangesDTOToModel(changes ComputerChangesDTO) model.ComputerChanges {
+	return model.ComputerChanges{
+		EmployeeAbbreviation: changes.EmployeeAbbreviation,
+		Description:          changes.Description,
+		Vendor:               changes.Vendor,
+		Model:                changes.Model,
+		OperatingSystem:      changes.OperatingSystem,
+		Location:             changes.Location,
+	}
+}
+
+func convertBatchResultToDTO(computers []model.Computer, dryRun bool) BatchResponse {
+	return BatchResponse{
+		DryRun:    dryRun,
+		Computers: convertComputerModelsToDTOs(computers).Computers,
+	}
+}
+
+func convertAssignmentModelToDTO(assignment model.Assignment) AssignmentResponse {
+	return AssignmentResponse{
+		ID:                   assignment.ID,
+		ComputerID:           assignment.ComputerID,
+		ComputerName:         assignment.ComputerName,
+		EmployeeAbbreviation: assignment.EmployeeAbbreviation,
+		StartedAt:            assignment.StartedAt,
+		EndedAt:              assignment.EndedAt,
+		Active:               assignment.IsActive(),
+	}
+}
+
+func convertAssignmentModelsToDTOs(assignments []model.Assignment) GetAssignmentsResponse {
+	assignmentDTOs := make([]AssignmentResponse, len(assignments))
+
+	for i, assignment := range assignments {
+		assignmentDTOs[i] = convertAssignmentModelToDTO(assignment)
+	}
+
+	return GetAssignmentsResponse{
+		Assignments: assignmentDTOs,
+	}
+}
+
+func convertComputerEventModelToDTO(event model.ComputerEvent) ComputerEventResponse {
+	return ComputerEventResponse{
+		ID:                   event.ID,
+		Type:                 string(event.Type),
+		ComputerID:           event.ComputerID,
+		EmployeeAbbreviation: event.EmployeeAbbreviation,
+		OccurredAt:           event.OccurredAt,
+	}
+}
+
+func convertWebhookSubscriptionRequestToModel(data WebhookSubscriptionRequest) model.WebhookSubscription {
+	eventTypes := make([]model.ComputerEventType, len(data.EventTypes))
+	for i, t := range data.EventTypes {
+		eventTypes[i] = model.ComputerEventType(t)
+	}
+
+	return model.WebhookSubscription{
+		URL:        data.URL,
+		EventTypes: eventTypes,
+		Secret:     data.Secret,
+	}
+}
+
+func convertWebhookSubscriptionModelToDTO(subscription model.WebhookSubscription) WebhookSubscriptionResponse {
+	eventTypes := make([]string, len(subscription.EventTypes))
+	for i, t := range subscription.EventTypes {
+		eventTypes[i] = string(t)
+	}
+
+	return WebhookSubscriptionResponse{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: eventTypes,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+func convertWebhookSubscriptionModelsToDTOs(subscriptions []model.WebhookSubscription) GetWebhookSubscriptionsResponse {
+	subscriptionDTOs := make([]WebhookSubscriptionResponse, len(subscriptions))
+
+	for i, subscription := range subscriptions {
+		subscriptionDTOs[i] = convertWebhookSubscriptionModelToDTO(subscription)
+	}
+
+	return GetWebhookSubscriptionsResponse{
+		Subscriptions: subscriptionDTOs,
+	}
+}
+
+func convertWebhookDeliveryModelToDTO(delivery model.WebhookDelivery) WebhookDeliveryResponse {
+	return WebhookDeliveryResponse{
+		ID:          delivery.ID,
+		EventID:     delivery.EventID,
+		EventType:   string(delivery.EventType),
+		Attempt:     delivery.Attempt,
+		StatusCode:  delivery.StatusCode,
+		Error:       delivery.Error,
+		Succeeded:   delivery.Succeeded,
+		DurationMS:  delivery.Duration.Milliseconds(),
+		DeliveredAt: delivery.DeliveredAt,
+	}
+}
+
+func convertWebhookDeliveryModelsToDTOs(deliveries []model.WebhookDelivery) GetWebhookDeliveriesResponse {
+	deliveryDTOs := make([]WebhookDeliveryResponse, len(deliveries))
+
+	for i, delivery := range deliveries {
+		deliveryDTOs[i] = convertWebhookDeliveryModelToDTO(delivery)
+	}
+
+	return GetWebhookDeliveriesResponse{
+		Deliveries: deliveryDTOs,
+	}
+}
+
+func convertNotificationPreviewModelToDTO(preview model.NotificationPreview) NotificationPreviewResponse {
+	return NotificationPreviewResponse{
+		Event:   preview.Event,
+		Locale:  preview.Locale,
+		Subject: preview.Subject,
+		Body:    preview.Body,
+	}
+}
+
+func convertNetworkInterfaceRequestToModel(data NetworkInterfaceRequest) model.NetworkInterface {
+	interfaceType := model.NetworkInterfaceType(data.Type)
+	if interfaceType == "" {
+		interfaceType = model.InterfaceTypeOther
+	}
+
+	return model.NetworkInterface{
+		Name:       data.Name,
+		MACAddress: data.MACAddress,
+		IPAddress:  data.IPAddress,
+		Type:       interfaceType,
+		IsPrimary:  data.Primary,
+	}
+}
+
+func convertNetworkInterfaceModelToDTO(iface model.NetworkInterface) NetworkInterfaceResponse {
+	return NetworkInterfaceResponse{
+		ID:         iface.ID,
+		ComputerID: iface.ComputerID,
+		Name:       iface.Name,
+		MACAddress: iface.MACAddress,
+		IPAddress:  iface.IPAddress,
+		Type:       string(iface.Type),
+		Primary:    iface.IsPrimary,
+	}
+}
+
+func convertNetworkInterfaceModelsToDTOs(interfaces []model.NetworkInterface) GetNetworkInterfacesResponse {
+	interfaceDTOs := make([]NetworkInterfaceResponse, len(interfaces))
+
+	for i, iface := range interfaces {
+		interfaceDTOs[i] = convertNetworkInterfaceModelToDTO(iface)
+	}
+
+	return GetNetworkInterfacesResponse{
+		Interfaces: interfaceDTOs,
+	}
+}
+
+func convertSearchResultsToDTO(results []model.ComputerSearchResult) SearchComputersResponse {
+	resultDTOs := make([]ComputerSearchResultResponse, len(results))
+
+	for i, result := range results {
+		resultDTOs[i] = ComputerSearchResultResponse{
+			Computer:   convertComputerModelToDTO(result.Computer),
+			Rank:       result.Rank,
+			Highlights: result.Highlights,
+		}
+	}
+
+	return SearchComputersResponse{
+		Results: resultDTOs,
+	}
+}
+
+func convertIPConflictsToDTO(conflicts []model.IPConflict) GetIPConflictsResponse {
+	conflictDTOs := make([]IPConflictResponse, len(conflicts))
+
+	for i, conflict := range conflicts {
+		conflictDTOs[i] = IPConflictResponse{
+			IPAddress: conflict.IPAddress,
+			Computers: convertComputerModelsToDTOs(conflict.Computers).Computers,
+		}
+	}
+
+	return GetIPConflictsResponse{
+		Conflicts: conflictDTOs,
+	}
+}
+
+func convertSubnetModelToDTO(subnet model.Subnet) GetSubnetByIDResponse {
+	ranges := make([]IPRangeDTO, len(subnet.ReservedRanges))
+	for i, r := range subnet.ReservedRanges {
+		ranges[i] = IPRangeDTO(r)
+	}
+
+	return GetSubnetByIDResponse{
+		ID:             subnet.ID,
+		Name:           subnet.Name,
+		CIDR:           subnet.CIDR,
+		Gateway:        subnet.Gateway,
+		VLAN:           subnet.VLAN,
+		ReservedRanges: ranges,
+	}
+}
+
+func convertSubnetModelsToDTOs(subnets []model.Subnet) GetSubnetsResponse {
+	subnetDTOs := make([]GetSubnetByIDResponse, len(subnets))
+
+	for i, subnet := range subnets {
+		subnetDTOs[i] = convertSubnetModelToDTO(subnet)
+	}
+
+	return GetSubnetsResponse{
+		Subnets: subnetDTOs,
+	}
+}
+
+func convertIPRangeDTOsToModels(ranges []IPRangeDTO) []model.IPRange {
+	models := make([]model.IPRange, len(ranges))
+	for i, r := range ranges {
+		models[i] = model.IPRange(r)
+	}
+
+	return models
+}
+
+func convertSubnetUtilizationToDTO(utilization model.SubnetUtilization) GetSubnetUtilizationResponse {
+	var percent float64
+	if utilization.Total > 0 {
+		percent = float64(utilization.Total-utilization.Free) * 100 / float64(utilization.Total)
+	}
+
+	return GetSubnetUtilizationResponse{
+		SubnetID:           utilization.SubnetID,
+		Total:              utilization.Total,
+		Used:               utilization.Used,
+		Allocated:          utilization.Allocated,
+		Reserved:           utilization.Reserved,
+		Free:               utilization.Free,
+		UtilizationPercent: percent,
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertComputerModelToDTORoundTrip(t *testing.T) {
+	purchaseDate := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	warrantyEnd := time.Date(2027, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		computer model.Computer
+	}{
+		{
+			name: "required fields only",
+			computer: model.Computer{
+				Name:       "TestPC",
+				IPAddress:  "192.168.0.1",
+				MACAddress: "AA:BB:CC:DD:EE:FF",
+			},
+		},
+		{
+			name: "all fields",
+			computer: model.Computer{
+				Name:                 "LabPC",
+				IPAddress:            "10.0.0.3",
+				MACAddress:           "11:22:33:44:55:77",
+				EmployeeAbbreviation: toPointer("STR"),
+				Description:          toPointer("Lab machine"),
+				SubnetID:             intToPointer(4),
+				SerialNumber:         toPointer("SN-123"),
+				PurchaseDate:         &purchaseDate,
+				WarrantyEnd:          &warrantyEnd,
+				Vendor:               toPointer("Lenovo"),
+				Model:                toPointer("ThinkCentre M70q"),
+				OperatingSystem:      toPointer("Ubuntu 24.04"),
+				Location:             toPointer("Berlin"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(convertComputerModelToDTO(tt.computer))
+			require.NoError(t, err)
+
+			var request AddComputerRequest
+			require.NoError(t, json.Unmarshal(body, &request))
+
+			assert.Equal(t, tt.computer, convertAddComputerRequestToModel(request))
+		})
+	}
+}
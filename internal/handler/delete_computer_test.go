@@ -39,7 +39,7 @@ func TestDeleteComputerHandler(t *testing.T) {
 				// no call expected
 			},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"Invalid URL parameter 'computerID'"}`,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter 'computerID'","code":"validation_failed"}`,
 		},
 		{
 			name:     "service returns error",
@@ -48,7 +48,7 @@ func TestDeleteComputerHandler(t *testing.T) {
 				m.EXPECT().DeleteComputer(2).Return(fmt.Errorf("db error"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"Failed to delete computer"}`,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
 		},
 	}
 
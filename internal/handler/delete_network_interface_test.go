@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uhuaha/computers-management/internal/mocks"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteNetworkInterfaceHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockComputerMgmtService)
+
+	tests := []struct {
+		name                 string
+		computerID           string
+		interfaceID          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "success: interface is deleted",
+			computerID:  "1",
+			interfaceID: "2",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().DeleteNetworkInterface(1, 2).Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:                 "invalid computer ID",
+			computerID:           "abc",
+			interfaceID:          "2",
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter 'computerID'","code":"validation_failed"}`,
+		},
+		{
+			name:        "primary interface cannot be deleted",
+			computerID:  "1",
+			interfaceID: "1",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					DeleteNetworkInterface(1, 1).
+					Return(errs.NewConflict("the primary network interface cannot be deleted, make another interface primary first"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"type":"/problems/conflict","title":"Conflict","status":409,"detail":"the primary network interface cannot be deleted, make another interface primary first","code":"conflict"}`,
+		},
+		{
+			name:        "interface not found",
+			computerID:  "1",
+			interfaceID: "9",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					DeleteNetworkInterface(1, 9).
+					Return(errs.NewNotFound("network interface not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"network interface not found","code":"not_found"}`,
+		},
+		{
+			name:        "service error",
+			computerID:  "1",
+			interfaceID: "2",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					DeleteNetworkInterface(1, 2).
+					Return(fmt.Errorf("database unavailable"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/computers/"+tt.computerID+"/interfaces/"+tt.interfaceID, nil)
+			req = mux.SetURLVars(req, map[string]string{"computerID": tt.computerID, "interfaceID": tt.interfaceID})
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockComputerMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := New(mockService)
+			handler.DeleteNetworkInterface(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
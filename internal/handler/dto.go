@@ -1,11 +1,57 @@
 package handler
 
+import (
+	"encoding/json"
+	"time"
+)
+
+// dateLayout is the format of calendar dates in requests, responses and query parameters.
+const dateLayout = time.DateOnly
+
+// Date is a calendar date that is encoded as "YYYY-MM-DD" in JSON.
+type Date struct {
+	time.Time
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Format(dateLayout))
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return err
+	}
+
+	d.Time = t
+	return nil
+}
+
+// LifecycleAttributes holds the hardware and lifecycle attributes of a computer.
+type LifecycleAttributes struct {
+	SerialNumber    *string `json:"serial_number,omitempty"`
+	PurchaseDate    *Date   `json:"purchase_date,omitempty"`
+	WarrantyEnd     *Date   `json:"warranty_end,omitempty"`
+	Vendor          *string `json:"vendor,omitempty"`
+	Model           *string `json:"model,omitempty"`
+	OperatingSystem *string `json:"operating_system,omitempty"`
+	Location        *string `json:"location,omitempty"`
+}
+
 type AddComputerRequest struct {
 	Name                 string  `json:"name"`
 	IPAddress            string  `json:"ip_address"`
 	MACAddress           string  `json:"mac_address"`
 	EmployeeAbbreviation *string `json:"employee_abbreviation"`
 	Description          *string `json:"description"`
+	// SubnetID is required if IPAddress is "auto" and selects the subnet to allocate the address from.
+	SubnetID *int `json:"subnet_id"`
+	LifecycleAttributes
 }
 
 type AddComputerResponse struct {
@@ -19,6 +65,9 @@ type GetComputerByIDResponse struct {
 	MACAddress           string  `json:"mac_address"`
 	EmployeeAbbreviation *string `json:"employee_abbreviation,omitempty"`
 	Description          *string `json:"description,omitempty"`
+	SubnetID             *int    `json:"subnet_id,omitempty"`
+	Status               string  `json:"status,omitempty"`
+	LifecycleAttributes
 }
 
 type GetComputersResponse struct {
@@ -31,4 +80,261 @@ type UpdateComputerRequest struct {
 	MACAddress           string  `json:"mac_address"`
 	EmployeeAbbreviation *string `json:"employee_abbreviation"`
 	Description          *string `json:"description"`
+	LifecycleAttributes
+}
+
+type AddStatusTransitionRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+	Actor  string `json:"actor"`
+}
+
+type StatusTransitionResponse struct {
+	ID         int       `json:"id"`
+	ComputerID int       `json:"computer_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason"`
+	Actor      string    `json:"actor"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type GetStatusTransitionsResponse struct {
+	Transitions []StatusTransitionResponse `json:"transitions"`
+}
+
+// Problem is the body of every error response as described in RFC 7807. Code identifies the kind of problem
+// for clients and does not change. AllowedNextStates is only set if a status transition is not allowed.
+type Problem struct {
+	Type              string   `json:"type"`
+	Title             string   `json:"title"`
+	Status            int      `json:"status"`
+	Detail            string   `json:"detail,omitempty"`
+	Instance          string   `json:"instance,omitempty"`
+	Code              string   `json:"code"`
+	AllowedNextStates []string `json:"allowed_next_states,omitempty"`
+}
+
+// BatchSelector selects the computers of a batch request either by their IDs or by a filter with the same
+// attributes as the query parameters of GET /computers.
+type BatchSelector struct {
+	IDs    []int             `json:"ids,omitempty"`
+	Filter map[string]string `json:"filter,omitempty"`
+}
+
+// ComputerChangesDTO holds the attributes set by a batch update. Omitted attributes are left unchanged.
+type ComputerChangesDTO struct {
+	EmployeeAbbreviation *string `json:"employee_abbreviation,omitempty"`
+	Description          *string `json:"description,omitempty"`
+	Vendor               *string `json:"vendor,omitempty"`
+	Model                *string `json:"model,omitempty"`
+	OperatingSystem      *string `json:"operating_system,omitempty"`
+	Location             *string `json:"location,omitempty"`
+}
+
+type BatchUpdateRequest struct {
+	BatchSelector
+	Changes ComputerChangesDTO `json:"changes"`
+}
+
+type BatchDeleteRequest struct {
+	BatchSelector
+}
+
+// BatchResponse lists the computers affected by a batch request. For a dry run nothing has been changed.
+type BatchResponse struct {
+	DryRun    bool                      `json:"dry_run"`
+	Computers []GetComputerByIDResponse `json:"computers"`
+}
+
+type CheckOutRequest struct {
+	EmployeeAbbreviation string `json:"employee_abbreviation"`
+	Actor                string `json:"actor"`
+}
+
+type CheckInRequest struct {
+	Actor string `json:"actor"`
+}
+
+// AssignmentResponse describes an assignment of a computer to an employee. EndedAt is omitted while it is active
+// and ComputerID once the computer has been deleted.
+type AssignmentResponse struct {
+	ID                   int        `json:"id"`
+	ComputerID           int        `json:"computer_id,omitempty"`
+	ComputerName         string     `json:"computer_name"`
+	EmployeeAbbreviation string     `json:"employee_abbreviation"`
+	StartedAt            time.Time  `json:"started_at"`
+	EndedAt              *time.Time `json:"ended_at,omitempty"`
+	Active               bool       `json:"active"`
+}
+
+type GetAssignmentsResponse struct {
+	Assignments []AssignmentResponse `json:"assignments"`
+}
+
+// NetworkInterfaceRequest is the body of requests adding or updating a network interface.
+// Type defaults to "other".
+type NetworkInterfaceRequest struct {
+	Name       string  `json:"name"`
+	MACAddress string  `json:"mac_address"`
+	IPAddress  *string `json:"ip_address"`
+	Type       string  `json:"type"`
+	Primary    bool    `json:"primary"`
+}
+
+type AddNetworkInterfaceResponse struct {
+	ID int `json:"id"`
+}
+
+type NetworkInterfaceResponse struct {
+	ID         int     `json:"id"`
+	ComputerID int     `json:"computer_id"`
+	Name       string  `json:"name"`
+	MACAddress string  `json:"mac_address"`
+	IPAddress  *string `json:"ip_address,omitempty"`
+	Type       string  `json:"type"`
+	Primary    bool    `json:"primary"`
+}
+
+type GetNetworkInterfacesResponse struct {
+	Interfaces []NetworkInterfaceResponse `json:"interfaces"`
+}
+
+type ComputerSearchResultResponse struct {
+	Computer   GetComputerByIDResponse `json:"computer"`
+	Rank       float64                 `json:"rank"`
+	Highlights map[string]string       `json:"highlights"`
+}
+
+type SearchComputersResponse struct {
+	Results []ComputerSearchResultResponse `json:"results"`
+}
+
+type IPConflictResponse struct {
+	IPAddress string                    `json:"ip_address"`
+	Computers []GetComputerByIDResponse `json:"computers"`
+}
+
+type GetIPConflictsResponse struct {
+	Conflicts []IPConflictResponse `json:"conflicts"`
+}
+
+type IPRangeDTO struct {
+	Start       string  `json:"start"`
+	End         string  `json:"end"`
+	Description *string `json:"description,omitempty"`
+}
+
+type AddSubnetRequest struct {
+	Name           string       `json:"name"`
+	CIDR           string       `json:"cidr"`
+	Gateway        *string      `json:"gateway"`
+	VLAN           *int         `json:"vlan"`
+	ReservedRanges []IPRangeDTO `json:"reserved_ranges"`
+}
+
+type AddSubnetResponse struct {
+	ID int `json:"id"`
+}
+
+type GetSubnetByIDResponse struct {
+	ID             int          `json:"id"`
+	Name           string       `json:"name"`
+	CIDR           string       `json:"cidr"`
+	Gateway        *string      `json:"gateway,omitempty"`
+	VLAN           *int         `json:"vlan,omitempty"`
+	ReservedRanges []IPRangeDTO `json:"reserved_ranges"`
+}
+
+type GetSubnetsResponse struct {
+	Subnets []GetSubnetByIDResponse `json:"subnets"`
+}
+
+type UpdateSubnetRequest struct {
+	Name           string       `json:"name"`
+	CIDR           string       `json:"cidr"`
+	Gateway        *string      `json:"gateway"`
+	VLAN           *int         `json:"vlan"`
+	ReservedRanges []IPRangeDTO `json:"reserved_ranges"`
+}
+
+type GetSubnetUtilizationResponse struct {
+	SubnetID           int     `json:"subnet_id"`
+	Total              int     `json:"total"`
+	Used               int     `json:"used"`
+	Allocated          int     `json:"allocated"`
+	Reserved           int     `json:"reserved"`
+	Free               int     `json:"free"`
+	UtilizationPercent float64 `json:"utilization_percent"`
+}
+
+type AllocateIPAddressResponse struct {
+	IPAddress string `json:"ip_address"`
+}
+
+// ComputerEventResponse is the data of a streamed computer event. EmployeeAbbreviation is omitted if the computer
+// has no employee.
+type ComputerEventResponse struct {
+	ID                   int64     `json:"id"`
+	Type                 string    `json:"type"`
+	ComputerID           int       `json:"computer_id"`
+	EmployeeAbbreviation *string   `json:"employee_abbreviation,omitempty"`
+	OccurredAt           time.Time `json:"occurred_at"`
+}
+
+// WebhookSubscriptionRequest is the body of requests adding or updating a webhook subscription. An empty secret
+// is generated when adding a subscription and kept when updating it.
+type WebhookSubscriptionRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"`
+}
+
+// AddWebhookSubscriptionResponse returns the secret of a new subscription, which is not returned by any other request.
+type AddWebhookSubscriptionResponse struct {
+	ID     int    `json:"id"`
+	Secret string `json:"secret"`
+}
+
+type WebhookSubscriptionResponse struct {
+	ID         int       `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type GetWebhookSubscriptionsResponse struct {
+	Subscriptions []WebhookSubscriptionResponse `json:"subscriptions"`
+}
+
+// WebhookDeliveryResponse describes an attempt to deliver an event. StatusCode is omitted if no response has
+// been received and EventID for test events.
+type WebhookDeliveryResponse struct {
+	ID          int64     `json:"id"`
+	EventID     *int64    `json:"event_id,omitempty"`
+	EventType   string    `json:"event_type"`
+	Attempt     int       `json:"attempt"`
+	StatusCode  *int      `json:"status_code,omitempty"`
+	Error       *string   `json:"error,omitempty"`
+	Succeeded   bool      `json:"succeeded"`
+	DurationMS  int64     `json:"duration_ms"`
+	DeliveredAt time.Time `json:"delivered_at"`
+}
+
+type GetWebhookDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+}
+
+// NotificationPreviewResponse is a notification template rendered with sample data. Locale is the locale whose
+// template has been rendered, which is the default locale if the requested one lacks the template.
+type NotificationPreviewResponse struct {
+	Event   string `json:"event"`
+	Locale  string `json:"locale"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+type WarrantyCheckResponse struct {
+	Notified  int                       `json:"notified"`
+	Computers []GetComputerByIDResponse `json:"computers"`
 }
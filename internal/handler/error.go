@@ -2,20 +2,129 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"uhuaha/computers-management/internal/requestid"
+
+	errs "uhuaha/computers-management/internal/errors"
 
 	"github.com/bdlm/log"
 )
 
-// handleError writes a JSON-formatted error response with the given message and HTTP status code.
-// It sets the "Content-Type" header to "application/json" and encodes the error as:
-// {"error": "<errMsg>"}.
-func handleError(w http.ResponseWriter, errMsg string, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(map[string]string{
-		"error": errMsg,
-	}); err != nil {
-		log.Error("failed to encode error message: " + err.Error())
+const (
+	problemContentType = "application/problem+json"
+	// problemTypeBase is the base of the type URIs of problems, which are followed by their code.
+	problemTypeBase = "/problems/"
+)
+
+// problemKind describes a kind of problem that is reported with the same status, title and code.
+type problemKind struct {
+	status int
+	title  string
+	code   string
+}
+
+// problemMapping maps a typed error to the kind of problem it is reported as.
+type problemMapping struct {
+	kind problemKind
+	// match returns the typed error if err is or wraps one.
+	match func(err error) (error, bool)
+	// detail replaces the message of the typed error if it must not be disclosed to clients.
+	detail string
+}
+
+// problemMappings is the single mapping of the typed errors of internal/errors to HTTP responses. Errors that
+// match none of them are internal errors.
+var problemMappings = []problemMapping{
+	{kind: problemKind{http.StatusBadRequest, "Validation failed", "validation_failed"}, match: as[*errs.ValidationError]},
+	{kind: problemKind{http.StatusForbidden, "Forbidden", "forbidden"}, match: as[*errs.ForbiddenError]},
+	{kind: problemKind{http.StatusNotFound, "Resource not found", "not_found"}, match: as[*errs.NotFoundError]},
+	{kind: problemKind{http.StatusNotAcceptable, "Not acceptable", "not_acceptable"}, match: as[*notAcceptableError]},
+	{kind: problemKind{http.StatusConflict, "Invalid status transition", "invalid_status_transition"}, match: as[*errs.InvalidTransitionError]},
+	{kind: problemKind{http.StatusConflict, "Conflict", "conflict"}, match: as[*errs.ConflictError]},
+	{kind: problemKind{http.StatusPreconditionFailed, "Precondition failed", "precondition_failed"}, match: as[*errs.PreconditionFailedError]},
+	{
+		kind:   problemKind{http.StatusServiceUnavailable, "Service unavailable", "unavailable"},
+		match:  as[*errs.UnavailableError],
+		detail: "The service is temporarily unavailable, please retry later",
+	},
+	{
+		kind:   problemKind{http.StatusGatewayTimeout, "Timeout", "timeout"},
+		match:  as[*errs.TimeoutError],
+		detail: "The request has not been processed in time, please retry later",
+	},
+}
+
+// internalProblemMapping applies to all errors that match none of the problemMappings.
+var internalProblemMapping = problemMapping{
+	kind:   problemKind{http.StatusInternalServerError, "Internal server error", "internal_error"},
+	detail: "The request could not be processed due to an unexpected error",
+}
+
+// as returns the error of type T that err is or wraps.
+func as[T error](err error) (error, bool) {
+	var target T
+	if errors.As(err, &target) {
+		return target, true
+	}
+
+	return nil, false
+}
+
+// notAcceptableError reports that none of the media types accepted by a client can be produced.
+type notAcceptableError struct {
+	msg string
+}
+
+func (e *notAcceptableError) Error() string {
+	return e.msg
+}
+
+// newProblem maps err to the problem it is reported as. The detail of a typed error is its own message without
+// the context it has been wrapped in unless it may not be disclosed.
+func newProblem(err error) Problem {
+	mapping, typed := internalProblemMapping, err
+
+	for _, candidate := range problemMappings {
+		if target, ok := candidate.match(err); ok {
+			mapping, typed = candidate, target
+			break
+		}
+	}
+
+	kind, detail := mapping.kind, mapping.detail
+	if detail == "" {
+		detail = typed.Error()
+	}
+
+	problem := Problem{
+		Type:   problemTypeBase + kind.code,
+		Title:  kind.title,
+		Status: kind.status,
+		Detail: detail,
+		Code:   kind.code,
+	}
+
+	if invalidTransition, ok := typed.(*errs.InvalidTransitionError); ok {
+		problem.AllowedNextStates = invalidTransition.Allowed
+	}
+
+	return problem
+}
+
+// handleError writes an RFC 7807 problem+json response describing err. Its instance is the ID of the request.
+// Internal errors are logged together with the request ID since their message is not disclosed to the client.
+func handleError(w http.ResponseWriter, r *http.Request, err error) {
+	problem := newProblem(err)
+	problem.Instance = requestid.FromContext(r.Context())
+
+	if problem.Status >= http.StatusInternalServerError {
+		log.Errorf("request %s failed: %v", problem.Instance, err)
+	}
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		log.Error("failed to encode problem: " + err.Error())
 	}
 }
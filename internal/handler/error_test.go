@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uhuaha/computers-management/internal/requestid"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleError(t *testing.T) {
+	tests := []struct {
+		name                 string
+		err                  error
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "validation error",
+			err:                  errs.NewValidation("Invalid MAC address"),
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid MAC address","instance":"req-1","code":"validation_failed"}`,
+		},
+		{
+			name:                 "wrapped not found error is reported without its context",
+			err:                  fmt.Errorf("failed to get computer: %w", errs.NewNotFound("computer not found")),
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"computer not found","instance":"req-1","code":"not_found"}`,
+		},
+		{
+			name:                 "conflict error",
+			err:                  errs.NewConflict("IP address is already used"),
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"type":"/problems/conflict","title":"Conflict","status":409,"detail":"IP address is already used","instance":"req-1","code":"conflict"}`,
+		},
+		{
+			name:               "invalid transition error includes the allowed next states",
+			err:                errs.NewInvalidTransition("computer cannot change its status", []string{"in_stock", "lost"}),
+			expectedStatusCode: http.StatusConflict,
+			expectedResponseBody: `{"type":"/problems/invalid_status_transition","title":"Invalid status transition","status":409,
+				"detail":"computer cannot change its status","instance":"req-1","code":"invalid_status_transition","allowed_next_states":["in_stock","lost"]}`,
+		},
+		{
+			name:                 "precondition failed error",
+			err:                  fmt.Errorf("failed to update subnet: %w", errs.NewPreconditionFailed("subnet is locked")),
+			expectedStatusCode:   http.StatusPreconditionFailed,
+			expectedResponseBody: `{"type":"/problems/precondition_failed","title":"Precondition failed","status":412,"detail":"subnet is locked","instance":"req-1","code":"precondition_failed"}`,
+		},
+		{
+			name:                 "forbidden error",
+			err:                  fmt.Errorf("failed to delete computer: %w", errs.NewForbidden("the database does not permit the operation")),
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: `{"type":"/problems/forbidden","title":"Forbidden","status":403,"detail":"the database does not permit the operation","instance":"req-1","code":"forbidden"}`,
+		},
+		{
+			name:               "unavailable error is not disclosed",
+			err:                fmt.Errorf("failed to get all computers: %w", errs.NewUnavailable("the database is unavailable", fmt.Errorf("dial tcp: connection refused"))),
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponseBody: `{"type":"/problems/unavailable","title":"Service unavailable","status":503,
+				"detail":"The service is temporarily unavailable, please retry later","instance":"req-1","code":"unavailable"}`,
+		},
+		{
+			name:               "timeout error is not disclosed",
+			err:                fmt.Errorf("failed to search computers: %w", errs.NewTimeout("the database has canceled the query", fmt.Errorf("pq: canceling statement due to statement timeout"))),
+			expectedStatusCode: http.StatusGatewayTimeout,
+			expectedResponseBody: `{"type":"/problems/timeout","title":"Timeout","status":504,
+				"detail":"The request has not been processed in time, please retry later","instance":"req-1","code":"timeout"}`,
+		},
+		{
+			name:               "unexpected error is not disclosed",
+			err:                fmt.Errorf("failed to add computer: %w", fmt.Errorf("pq: connection refused")),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,
+				"detail":"The request could not be processed due to an unexpected error","instance":"req-1","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/computers/1", nil)
+			req = req.WithContext(requestid.NewContext(req.Context(), "req-1"))
+
+			rec := httptest.NewRecorder()
+
+			// Act
+			handleError(rec, req, tt.err)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, rec.Code)
+			assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.expectedResponseBody, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/bdlm/log"
+)
+
+// eventLogPageSize is the number of recorded events loaded at once when a client resumes a stream.
+const eventLogPageSize = 100
+
+type EventMgmtService interface {
+	SubscribeToComputerEvents() (<-chan model.ComputerEvent, func())
+	GetComputerEventsSince(ctx context.Context, afterID int64, limit int) ([]model.ComputerEvent, error)
+}
+
+type EventMgmtHandler struct {
+	eventMgmtService EventMgmtService
+	// heartbeatInterval is the time after which a comment is sent to keep an idle stream open.
+	heartbeatInterval time.Duration
+}
+
+func NewEventHandler(service EventMgmtService, heartbeatInterval time.Duration) *EventMgmtHandler {
+	return &EventMgmtHandler{
+		eventMgmtService:  service,
+		heartbeatInterval: heartbeatInterval,
+	}
+}
+
+// StreamComputerEvents streams the computer events as server-sent events until the client disconnects. The events
+// can be filtered by the query parameters employee and computer_id. A client sending the Last-Event-ID header first
+// receives the events recorded after that event. A stream is ended if the client cannot keep up with the events;
+// it then resumes with the Last-Event-ID of the last event received.
+func (h *EventMgmtHandler) StreamComputerEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseEventFilter(r.URL.Query())
+	if err != nil {
+		log.Error("failed to parse query parameters: " + err.Error())
+		handleError(w, r, errs.NewValidation(err.Error()))
+		return
+	}
+
+	lastEventHeader := r.Header.Get("Last-Event-ID")
+
+	lastEventID, err := parseLastEventID(lastEventHeader)
+	if err != nil {
+		log.Error("failed to parse header 'Last-Event-ID': " + err.Error())
+		handleError(w, r, errs.NewValidation(err.Error()))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		handleError(w, r, errors.New("failed to stream computer events: the response writer cannot flush"))
+		return
+	}
+
+	// Subscribing before the recorded events are loaded ensures that no event is missed in between.
+	events, unsubscribe := h.eventMgmtService.SubscribeToComputerEvents()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Disables the response buffering of reverse proxies such as nginx.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// replayedID is the ID of the last recorded event sent. Since the IDs are assigned in the order in which the
+	// events are committed, published events up to it have been sent already.
+	var replayedID int64
+
+	if lastEventHeader != "" {
+		replayedID, err = h.replayComputerEvents(r.Context(), w, lastEventID, filter)
+		if err != nil {
+			log.Errorf("failed to replay computer events after ID=%d: %v", lastEventID, err)
+			return
+		}
+
+		flusher.Flush()
+	}
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				log.Info("ended stream of computer events of a client that has fallen behind")
+				return
+			}
+
+			if event.ID <= replayedID || !filter.Matches(event) {
+				continue
+			}
+
+			if err := writeComputerEvent(w, event); err != nil {
+				log.Error("failed to write computer event: " + err.Error())
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+
+		flusher.Flush()
+	}
+}
+
+// replayComputerEvents sends the recorded events after the event with the ID afterID that match the filter and
+// returns the ID of the last recorded event.
+func (h *EventMgmtHandler) replayComputerEvents(ctx context.Context, w http.ResponseWriter, afterID int64, filter model.ComputerEventFilter) (int64, error) {
+	for {
+		events, err := h.eventMgmtService.GetComputerEventsSince(ctx, afterID, eventLogPageSize)
+		if err != nil {
+			return afterID, err
+		}
+
+		for _, event := range events {
+			if filter.Matches(event) {
+				if err := writeComputerEvent(w, event); err != nil {
+					return afterID, err
+				}
+			}
+
+			afterID = event.ID
+		}
+
+		if len(events) < eventLogPageSize {
+			return afterID, nil
+		}
+	}
+}
+
+// writeComputerEvent writes the event as a server-sent event named after its type.
+func writeComputerEvent(w http.ResponseWriter, event model.ComputerEvent) error {
+	data, err := json.Marshal(convertComputerEventModelToDTO(event))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+
+	return err
+}
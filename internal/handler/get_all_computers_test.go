@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 	"uhuaha/computers-management/internal/mocks"
 	"uhuaha/computers-management/internal/model"
 
@@ -18,6 +19,7 @@ func TestGetAllComputersHandler(t *testing.T) {
 
 	tests := []struct {
 		name                 string
+		query                string
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
@@ -25,12 +27,13 @@ func TestGetAllComputersHandler(t *testing.T) {
 		{
 			name: "success: return 200 with computers list",
 			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputersVersion(gomock.Any()).Return(model.ComputersVersion{Version: 1}, nil)
 				m.EXPECT().
-					GetAllComputers().
+					GetVersionedComputers(gomock.Any(), model.ComputerFilter{}).
 					Return([]model.Computer{
 						{ID: 1, Name: "PC1", IPAddress: "192.168.0.1", MACAddress: "AA:BB:CC:DD:EE:FF"},
 						{ID: 2, Name: "PC2", IPAddress: "192.168.0.2", MACAddress: "11:22:33:44:55:66", EmployeeAbbreviation: toPointer("EMP"), Description: toPointer("Office PC")},
-					}, nil)
+					}, model.ComputersVersion{Version: 1}, nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponseBody: `{"computers":
@@ -42,19 +45,52 @@ func TestGetAllComputersHandler(t *testing.T) {
 		{
 			name: "success: return 200 with empty list",
 			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputersVersion(gomock.Any()).Return(model.ComputersVersion{Version: 1}, nil)
 				m.EXPECT().
-					GetAllComputers().
-					Return([]model.Computer{}, nil)
+					GetVersionedComputers(gomock.Any(), model.ComputerFilter{}).
+					Return([]model.Computer{}, model.ComputersVersion{Version: 1}, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
 			expectedResponseBody: `{"computers":[]}`,
 		},
+		{
+			name:  "success: filter by lifecycle attributes",
+			query: "?vendor=Dell&location=Berlin&warranty_ends_before=2025-12-31",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				warrantyEnd := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+				m.EXPECT().GetComputersVersion(gomock.Any()).Return(model.ComputersVersion{Version: 1}, nil)
+				m.EXPECT().
+					GetVersionedComputers(gomock.Any(), model.ComputerFilter{
+						Vendor:             toPointer("Dell"),
+						Location:           toPointer("Berlin"),
+						WarrantyEndsBefore: &warrantyEnd,
+					}).
+					Return([]model.Computer{
+						{ID: 3, Name: "PC3", IPAddress: "192.168.0.3", MACAddress: "AA:BB:CC:DD:EE:00", Vendor: toPointer("Dell"), Location: toPointer("Berlin"), WarrantyEnd: &warrantyEnd},
+					}, model.ComputersVersion{Version: 1}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"computers":
+			[
+				{"id":3,"name":"PC3","ip_address":"192.168.0.3","mac_address":"AA:BB:CC:DD:EE:00","vendor":"Dell","location":"Berlin","warranty_end":"2025-12-31"}]
+			}`,
+		},
+		{
+			name:  "return 400 due to invalid date filter",
+			query: "?purchased_after=yesterday",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				// no call expected
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid query parameter 'purchased_after': it must be a date formatted as YYYY-MM-DD","code":"validation_failed"}`,
+		},
 		{
 			name: "return 500 due to service error",
 			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputersVersion(gomock.Any()).Return(model.ComputersVersion{Version: 1}, nil)
 				m.EXPECT().
-					GetAllComputers().
-					Return(nil, fmt.Errorf("database unavailable"))
+					GetVersionedComputers(gomock.Any(), model.ComputerFilter{}).
+					Return(nil, model.ComputersVersion{}, fmt.Errorf("database unavailable"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 		},
@@ -71,7 +107,7 @@ func TestGetAllComputersHandler(t *testing.T) {
 
 			handler := New(mockComputerMgmtService)
 
-			req := httptest.NewRequest(http.MethodGet, "/computers", nil)
+			req := httptest.NewRequest(http.MethodGet, "/computers"+tt.query, nil)
 			rec := httptest.NewRecorder()
 
 			// Act
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAssignmentsByEmployeeHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockComputerMgmtService)
+
+	firstStart := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	firstEnd := time.Date(2025, 2, 28, 17, 0, 0, 0, time.UTC)
+	secondStart := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		urlParam             string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "success: return 200 with current and past assignments",
+			urlParam: "EMP",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					GetAssignmentsByEmployee("EMP").
+					Return([]model.Assignment{
+						{ID: 2, ComputerID: 5, ComputerName: "PC5", EmployeeAbbreviation: "EMP", StartedAt: secondStart},
+						{ID: 1, ComputerID: 3, ComputerName: "PC3", EmployeeAbbreviation: "EMP", StartedAt: firstStart, EndedAt: &firstEnd},
+					}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"assignments":[
+				{"id":2,"computer_id":5,"computer_name":"PC5","employee_abbreviation":"EMP","started_at":"2025-03-01T09:00:00Z","active":true},
+				{"id":1,"computer_id":3,"computer_name":"PC3","employee_abbreviation":"EMP","started_at":"2024-01-15T09:00:00Z",
+					"ended_at":"2025-02-28T17:00:00Z","active":false}
+			]}`,
+		},
+		{
+			name:     "success: return 200 with empty list",
+			urlParam: "NEW",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					GetAssignmentsByEmployee("NEW").
+					Return([]model.Assignment{}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"assignments":[]}`,
+		},
+		{
+			name:                 "invalid employee abbreviation",
+			urlParam:             "EMPLOYEE",
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter 'employee'","code":"validation_failed"}`,
+		},
+		{
+			name:     "service error",
+			urlParam: "EMP",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					GetAssignmentsByEmployee("EMP").
+					Return(nil, fmt.Errorf("database unavailable"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/employees/"+tt.urlParam+"/assignments", nil)
+			req = mux.SetURLVars(req, map[string]string{"employee": tt.urlParam})
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockComputerMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := New(mockService)
+			handler.GetAssignmentsByEmployee(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ func TestGetComputerByIDHandler(t *testing.T) {
 	tests := []struct {
 		name                 string
 		urlParam             string
+		headers              map[string]string
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
@@ -30,14 +31,15 @@ func TestGetComputerByIDHandler(t *testing.T) {
 			name:     "valid request returns 200",
 			urlParam: "1",
 			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputerVersion(gomock.Any(), 1).Return(model.ComputersVersion{Version: 1}, nil)
 				m.EXPECT().
-					GetComputer(1).
+					GetVersionedComputer(gomock.Any(), 1).
 					Return(model.Computer{
 						ID:         1,
 						Name:       "TestPC",
 						IPAddress:  "192.168.1.10",
 						MACAddress: "AA:BB:CC:DD:EE:FF",
-					}, nil)
+					}, model.ComputersVersion{Version: 1}, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
 			expectedResponseBody: `{"id":1, "name":"TestPC", "ip_address":"192.168.1.10", "mac_address":"AA:BB:CC:DD:EE:FF"}`,
@@ -49,29 +51,44 @@ func TestGetComputerByIDHandler(t *testing.T) {
 				// Service should not be called because parsing fails
 			},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"Invalid URL parameter"}`,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter","code":"validation_failed"}`,
 		},
 		{
 			name:     "computer not found returns 404",
 			urlParam: "42",
 			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputerVersion(gomock.Any(), 42).Return(model.ComputersVersion{}, &errs.NotFoundError{Msg: "computer not found"})
 				m.EXPECT().
-					GetComputer(42).
-					Return(model.Computer{}, &errs.NotFoundError{Msg: "computer not found"})
+					GetVersionedComputer(gomock.Any(), 42).
+					Return(model.Computer{}, model.ComputersVersion{}, &errs.NotFoundError{Msg: "computer not found"})
 			},
 			expectedStatusCode:   http.StatusNotFound,
-			expectedResponseBody: `{"error":"computer not found"}`,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"computer not found","code":"not_found"}`,
+		},
+		{
+			name:     "deleted computer with a current ETag of the collection returns 404",
+			urlParam: "42",
+			headers:  map[string]string{"If-None-Match": `W/"1-json"`},
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputerVersion(gomock.Any(), 42).Return(model.ComputersVersion{}, &errs.NotFoundError{Msg: "computer not found"})
+				m.EXPECT().
+					GetVersionedComputer(gomock.Any(), 42).
+					Return(model.Computer{}, model.ComputersVersion{}, &errs.NotFoundError{Msg: "computer not found"})
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"computer not found","code":"not_found"}`,
 		},
 		{
 			name:     "internal service error returns 500",
 			urlParam: "5",
 			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetComputerVersion(gomock.Any(), 5).Return(model.ComputersVersion{Version: 1}, nil)
 				m.EXPECT().
-					GetComputer(5).
-					Return(model.Computer{}, fmt.Errorf("db connection failed"))
+					GetVersionedComputer(gomock.Any(), 5).
+					Return(model.Computer{}, model.ComputersVersion{}, fmt.Errorf("db connection failed"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"Failed to get computer by ID"}`,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
 		},
 	}
 
@@ -87,6 +104,9 @@ func TestGetComputerByIDHandler(t *testing.T) {
 			handler := New(mockComputerMgmtService)
 
 			req := httptest.NewRequest(http.MethodGet, "/computers/"+tt.urlParam, nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
 			req = mux.SetURLVars(req, map[string]string{
 				"computerID": tt.urlParam,
 			})
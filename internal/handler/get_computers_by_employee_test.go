@@ -32,7 +32,8 @@ func TestGetComputersByEmployeeHandler(t *testing.T) {
 					{ID: 1, Name: "PC1", IPAddress: "192.168.0.1", MACAddress: "AA:BB:CC:DD:EE:FF"},
 					{ID: 2, Name: "PC2", IPAddress: "192.168.0.2", MACAddress: "11:22:33:44:55:66"},
 				}
-				m.EXPECT().GetComputersByEmployee("ABC").Return(computers, nil)
+				m.EXPECT().GetComputersVersion(gomock.Any()).Return(model.ComputersVersion{Version: 1}, nil)
+				m.EXPECT().GetVersionedComputersByEmployee(gomock.Any(), "ABC").Return(computers, model.ComputersVersion{Version: 1}, nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponseBody: `{"computers":[
@@ -47,16 +48,17 @@ func TestGetComputersByEmployeeHandler(t *testing.T) {
 				// no call expected
 			},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"Invalid URL parameter 'employee'"}`,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter 'employee'","code":"validation_failed"}`,
 		},
 		{
 			name:     "service returns error",
 			urlParam: "XYZ",
 			mockBehavior: func(m *mocks.MockComputerMgmtService) {
-				m.EXPECT().GetComputersByEmployee("XYZ").Return(nil, fmt.Errorf("db failure"))
+				m.EXPECT().GetComputersVersion(gomock.Any()).Return(model.ComputersVersion{Version: 1}, nil)
+				m.EXPECT().GetVersionedComputersByEmployee(gomock.Any(), "XYZ").Return(nil, model.ComputersVersion{}, fmt.Errorf("db failure"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"Failed to get computers by employee"}`,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
 		},
 	}
 
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNetworkInterfaceByIDHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockComputerMgmtService)
+
+	tests := []struct {
+		name                 string
+		computerID           string
+		interfaceID          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "success: return 200 with interface",
+			computerID:  "1",
+			interfaceID: "2",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					GetNetworkInterface(1, 2).
+					Return(model.NetworkInterface{ID: 2, ComputerID: 1, Name: "eth1", MACAddress: "aa:bb:cc:dd:ee:02", IPAddress: toPointer("10.0.0.2"), Type: model.InterfaceTypeEthernet}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"id":2,"computer_id":1,"name":"eth1","mac_address":"aa:bb:cc:dd:ee:02","ip_address":"10.0.0.2","type":"ethernet","primary":false}`,
+		},
+		{
+			name:                 "invalid interface ID",
+			computerID:           "1",
+			interfaceID:          "abc",
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter 'interfaceID'","code":"validation_failed"}`,
+		},
+		{
+			name:        "interface not found",
+			computerID:  "1",
+			interfaceID: "9",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					GetNetworkInterface(1, 9).
+					Return(model.NetworkInterface{}, errs.NewNotFound("network interface not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"network interface not found","code":"not_found"}`,
+		},
+		{
+			name:        "service error",
+			computerID:  "1",
+			interfaceID: "2",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					GetNetworkInterface(1, 2).
+					Return(model.NetworkInterface{}, fmt.Errorf("database unavailable"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/computers/"+tt.computerID+"/interfaces/"+tt.interfaceID, nil)
+			req = mux.SetURLVars(req, map[string]string{"computerID": tt.computerID, "interfaceID": tt.interfaceID})
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockComputerMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := New(mockService)
+			handler.GetNetworkInterfaceByID(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNetworkInterfacesHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockComputerMgmtService)
+
+	tests := []struct {
+		name                 string
+		urlParam             string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "success: return 200 with interfaces",
+			urlParam: "1",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					GetNetworkInterfaces(1).
+					Return([]model.NetworkInterface{
+						{ID: 1, ComputerID: 1, Name: "primary", MACAddress: "aa:bb:cc:dd:ee:ff", IPAddress: toPointer("192.168.0.1"), Type: model.InterfaceTypeOther, IsPrimary: true},
+						{ID: 2, ComputerID: 1, Name: "wlan0", MACAddress: "aa:bb:cc:dd:ee:01", Type: model.InterfaceTypeWiFi},
+					}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"interfaces":[
+				{"id":1,"computer_id":1,"name":"primary","mac_address":"aa:bb:cc:dd:ee:ff","ip_address":"192.168.0.1","type":"other","primary":true},
+				{"id":2,"computer_id":1,"name":"wlan0","mac_address":"aa:bb:cc:dd:ee:01","type":"wifi","primary":false}
+			]}`,
+		},
+		{
+			name:                 "invalid computer ID",
+			urlParam:             "abc",
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter 'computerID'","code":"validation_failed"}`,
+		},
+		{
+			name:     "computer not found",
+			urlParam: "2",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					GetNetworkInterfaces(2).
+					Return(nil, errs.NewNotFound("computer not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"computer not found","code":"not_found"}`,
+		},
+		{
+			name:     "service error",
+			urlParam: "1",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					GetNetworkInterfaces(1).
+					Return(nil, fmt.Errorf("database unavailable"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/computers/"+tt.urlParam+"/interfaces", nil)
+			req = mux.SetURLVars(req, map[string]string{"computerID": tt.urlParam})
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockComputerMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := New(mockService)
+			handler.GetNetworkInterfaces(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStatusTransitionsHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockComputerMgmtService)
+
+	tests := []struct {
+		name                 string
+		urlParam             string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "success: returns status history",
+			urlParam: "1",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetStatusTransitions(1).Return([]model.StatusTransition{
+					{
+						ID:         1,
+						ComputerID: 1,
+						From:       model.StatusInStock,
+						To:         model.StatusLost,
+						Reason:     "Not returned",
+						Actor:      "ADM",
+						CreatedAt:  time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+					},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"transitions":[{"id":1,"computer_id":1,"from_status":"in_stock","to_status":"lost",
+				"reason":"Not returned","actor":"ADM","created_at":"2025-03-01T12:00:00Z"}]}`,
+		},
+		{
+			name:     "success: empty history",
+			urlParam: "2",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetStatusTransitions(2).Return(nil, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"transitions":[]}`,
+		},
+		{
+			name:     "computer not found",
+			urlParam: "3",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetStatusTransitions(3).Return(nil, &errs.NotFoundError{Msg: "computer not found"})
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"computer not found","code":"not_found"}`,
+		},
+		{
+			name:     "service returns error",
+			urlParam: "4",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().GetStatusTransitions(4).Return(nil, fmt.Errorf("db failure"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/computers/"+tt.urlParam+"/transitions", nil)
+			req = mux.SetURLVars(req, map[string]string{"computerID": tt.urlParam})
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockComputerMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := New(mockService)
+			handler.GetStatusTransitions(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
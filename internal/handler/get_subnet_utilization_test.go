@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSubnetUtilizationHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockSubnetMgmtService)
+
+	tests := []struct {
+		name                 string
+		urlParam             string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "success: returns utilization",
+			urlParam: "1",
+			mockBehavior: func(m *mocks.MockSubnetMgmtService) {
+				m.EXPECT().GetSubnetUtilization(1).Return(model.SubnetUtilization{
+					SubnetID: 1, Total: 8, Used: 2, Allocated: 1, Reserved: 1, Free: 4,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"subnet_id":1,"total":8,"used":2,"allocated":1,"reserved":1,"free":4,
+				"utilization_percent":50}`,
+		},
+		{
+			name:     "subnet not found",
+			urlParam: "2",
+			mockBehavior: func(m *mocks.MockSubnetMgmtService) {
+				m.EXPECT().GetSubnetUtilization(2).Return(model.SubnetUtilization{}, &errs.NotFoundError{Msg: "subnet not found"})
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"subnet not found","code":"not_found"}`,
+		},
+		{
+			name:     "service returns error",
+			urlParam: "3",
+			mockBehavior: func(m *mocks.MockSubnetMgmtService) {
+				m.EXPECT().GetSubnetUtilization(3).Return(model.SubnetUtilization{}, fmt.Errorf("db failure"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/subnets/"+tt.urlParam+"/utilization", nil)
+			req = mux.SetURLVars(req, map[string]string{"subnetID": tt.urlParam})
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockSubnetMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := NewSubnetHandler(mockService)
+			handler.GetSubnetUtilization(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
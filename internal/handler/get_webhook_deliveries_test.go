@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWebhookDeliveriesHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockWebhookMgmtService)
+
+	deliveredAt := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	eventID := int64(12)
+
+	tests := []struct {
+		name                 string
+		urlParam             string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "success: returns the deliveries",
+			urlParam: "1",
+			mockBehavior: func(m *mocks.MockWebhookMgmtService) {
+				m.EXPECT().GetWebhookDeliveries(1).Return([]model.WebhookDelivery{
+					{
+						ID: 2, SubscriptionID: 1, EventID: &eventID, EventType: model.ComputerCreated, Attempt: 2,
+						StatusCode: intToPointer(200), Succeeded: true, Duration: 15 * time.Millisecond, DeliveredAt: deliveredAt,
+					},
+					{
+						ID: 1, SubscriptionID: 1, EventID: &eventID, EventType: model.ComputerCreated, Attempt: 1,
+						StatusCode: intToPointer(503), Error: toPointer("unexpected status code 503"), DeliveredAt: deliveredAt,
+					},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"deliveries":[
+				{"id":2,"event_id":12,"event_type":"computer.created","attempt":2,"status_code":200,"succeeded":true,"duration_ms":15,"delivered_at":"2025-03-10T08:00:00Z"},
+				{"id":1,"event_id":12,"event_type":"computer.created","attempt":1,"status_code":503,"error":"unexpected status code 503","succeeded":false,"duration_ms":0,"delivered_at":"2025-03-10T08:00:00Z"}]}`,
+		},
+		{
+			name:     "success: no deliveries",
+			urlParam: "1",
+			mockBehavior: func(m *mocks.MockWebhookMgmtService) {
+				m.EXPECT().GetWebhookDeliveries(1).Return(nil, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"deliveries":[]}`,
+		},
+		{
+			name:                 "invalid webhook ID",
+			urlParam:             "abc",
+			mockBehavior:         func(m *mocks.MockWebhookMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter 'webhookID'","code":"validation_failed"}`,
+		},
+		{
+			name:     "subscription not found",
+			urlParam: "2",
+			mockBehavior: func(m *mocks.MockWebhookMgmtService) {
+				m.EXPECT().GetWebhookDeliveries(2).Return(nil, errs.NewNotFound("webhook subscription not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"webhook subscription not found","code":"not_found"}`,
+		},
+		{
+			name:     "service returns error",
+			urlParam: "3",
+			mockBehavior: func(m *mocks.MockWebhookMgmtService) {
+				m.EXPECT().GetWebhookDeliveries(3).Return(nil, fmt.Errorf("db failure"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockWebhookMgmtService := mocks.NewMockWebhookMgmtService(ctrl)
+			tt.mockBehavior(mockWebhookMgmtService)
+
+			req := httptest.NewRequest(http.MethodGet, "/webhooks/"+tt.urlParam+"/deliveries", nil)
+			req = mux.SetURLVars(req, map[string]string{"webhookID": tt.urlParam})
+			rec := httptest.NewRecorder()
+
+			handler := NewWebhookHandler(mockWebhookMgmtService)
+
+			// Act
+			handler.GetWebhookDeliveries(rec, req)
+
+			// Assert
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			assert.JSONEq(t, tt.expectedResponseBody, string(body))
+		})
+	}
+}
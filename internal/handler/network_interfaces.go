@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/bdlm/log"
+	"github.com/gorilla/mux"
+)
+
+// AddNetworkInterface adds a network interface to a computer.
+func (c *ComputerMgmtHandler) AddNetworkInterface(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	paramComputerID := vars["computerID"]
+	computerID, err := strconv.Atoi(paramComputerID)
+	if err != nil {
+		log.Error("failed to parse URL parameter 'computerID': " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'computerID'"))
+		return
+	}
+
+	var data NetworkInterfaceRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Error("failed to decode the request body: " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid request body"))
+		return
+	}
+
+	if msg := validateNetworkInterface(data); msg != "" {
+		log.Error("failed to validate network interface: " + msg)
+		handleError(w, r, errs.NewValidation(msg))
+		return
+	}
+
+	interfaceID, err := c.computerMgmtService.AddNetworkInterface(computerID, convertNetworkInterfaceRequestToModel(data))
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to add network interface: %w", err))
+		return
+	}
+
+	response := AddNetworkInterfaceResponse{ID: interfaceID}
+
+	writeResponse(w, r, http.StatusCreated, jsonFormat, response)
+}
+
+// GetNetworkInterfaces retrieves all network interfaces of a computer.
+func (c *ComputerMgmtHandler) GetNetworkInterfaces(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateFormat(w, r, readFormats)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	paramComputerID := vars["computerID"]
+	computerID, err := strconv.Atoi(paramComputerID)
+	if err != nil {
+		log.Error("failed to parse URL parameter 'computerID': " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'computerID'"))
+		return
+	}
+
+	interfaces, err := c.computerMgmtService.GetNetworkInterfaces(computerID)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to get network interfaces: %w", err))
+		return
+	}
+
+	response := convertNetworkInterfaceModelsToDTOs(interfaces)
+
+	writeResponse(w, r, http.StatusOK, format, response)
+}
+
+// GetNetworkInterfaceByID gets a network interface of a computer by its ID.
+func (c *ComputerMgmtHandler) GetNetworkInterfaceByID(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateFormat(w, r, readFormats)
+	if !ok {
+		return
+	}
+
+	computerID, interfaceID, ok := parseNetworkInterfaceURL(w, r)
+	if !ok {
+		return
+	}
+
+	iface, err := c.computerMgmtService.GetNetworkInterface(computerID, interfaceID)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to get network interface by ID: %w", err))
+		return
+	}
+
+	response := convertNetworkInterfaceModelToDTO(iface)
+
+	writeResponse(w, r, http.StatusOK, format, response)
+}
+
+// UpdateNetworkInterface updates a network interface of a computer.
+func (c *ComputerMgmtHandler) UpdateNetworkInterface(w http.ResponseWriter, r *http.Request) {
+	computerID, interfaceID, ok := parseNetworkInterfaceURL(w, r)
+	if !ok {
+		return
+	}
+
+	var data NetworkInterfaceRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Error("failed to decode the request body: " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid request body"))
+		return
+	}
+
+	if msg := validateNetworkInterface(data); msg != "" {
+		log.Error("failed to validate network interface: " + msg)
+		handleError(w, r, errs.NewValidation(msg))
+		return
+	}
+
+	if err := c.computerMgmtService.UpdateNetworkInterface(computerID, interfaceID, convertNetworkInterfaceRequestToModel(data)); err != nil {
+		handleError(w, r, fmt.Errorf("failed to update network interface: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteNetworkInterface deletes a network interface of a computer. The primary interface cannot be deleted.
+func (c *ComputerMgmtHandler) DeleteNetworkInterface(w http.ResponseWriter, r *http.Request) {
+	computerID, interfaceID, ok := parseNetworkInterfaceURL(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.computerMgmtService.DeleteNetworkInterface(computerID, interfaceID); err != nil {
+		handleError(w, r, fmt.Errorf("failed to delete network interface: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseNetworkInterfaceURL parses the computer and interface IDs from the URL. It writes an error response
+// and returns false if one of them is invalid.
+func parseNetworkInterfaceURL(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	vars := mux.Vars(r)
+
+	computerID, err := strconv.Atoi(vars["computerID"])
+	if err != nil {
+		log.Error("failed to parse URL parameter 'computerID': " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'computerID'"))
+		return 0, 0, false
+	}
+
+	interfaceID, err := strconv.Atoi(vars["interfaceID"])
+	if err != nil {
+		log.Error("failed to parse URL parameter 'interfaceID': " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'interfaceID'"))
+		return 0, 0, false
+	}
+
+	return computerID, interfaceID, true
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/bdlm/log"
+	"github.com/gorilla/mux"
+)
+
+type NotificationMgmtService interface {
+	PreviewNotificationTemplate(event, locale string) (model.NotificationPreview, error)
+}
+
+type NotificationMgmtHandler struct {
+	notificationMgmtService NotificationMgmtService
+}
+
+func NewNotificationHandler(service NotificationMgmtService) *NotificationMgmtHandler {
+	return &NotificationMgmtHandler{
+		notificationMgmtService: service,
+	}
+}
+
+// PreviewNotificationTemplate renders the notification template of an event type with sample data. The optional
+// query parameter 'locale' selects the language; without it, the configured locale is used.
+func (h *NotificationMgmtHandler) PreviewNotificationTemplate(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateFormat(w, r, readFormats)
+	if !ok {
+		return
+	}
+
+	event := mux.Vars(r)["event"]
+
+	locale := r.URL.Query().Get("locale")
+	if r.URL.Query().Has("locale") && !isValidLocale(locale) {
+		log.Error("failed to validate query parameter 'locale': " + locale)
+		handleError(w, r, errs.NewValidation("Invalid query parameter 'locale'"))
+		return
+	}
+
+	preview, err := h.notificationMgmtService.PreviewNotificationTemplate(event, locale)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to preview notification template: %w", err))
+		return
+	}
+
+	response := convertNotificationPreviewModelToDTO(preview)
+
+	writeResponse(w, r, http.StatusOK, format, response)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviewNotificationTemplateHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockNotificationMgmtService)
+
+	tests := []struct {
+		name                 string
+		event                string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "success: configured locale",
+			event: "assignment_threshold",
+			mockBehavior: func(m *mocks.MockNotificationMgmtService) {
+				m.EXPECT().PreviewNotificationTemplate("assignment_threshold", "").Return(model.NotificationPreview{
+					Event:   "assignment_threshold",
+					Locale:  "en",
+					Subject: "Employee MMU has 3 computers assigned",
+					Body:    "There are 3 computers assigned to the employee MMU, which reaches the threshold of 3: PC1, PC2, PC3.",
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"event":"assignment_threshold","locale":"en","subject":"Employee MMU has 3 computers assigned",
+				"body":"There are 3 computers assigned to the employee MMU, which reaches the threshold of 3: PC1, PC2, PC3."}`,
+		},
+		{
+			name:  "success: requested locale",
+			event: "ip_conflict",
+			query: "?locale=de",
+			mockBehavior: func(m *mocks.MockNotificationMgmtService) {
+				m.EXPECT().PreviewNotificationTemplate("ip_conflict", "de").Return(model.NotificationPreview{
+					Event:   "ip_conflict",
+					Locale:  "de",
+					Subject: "IP-Adressenkonflikt",
+					Body:    "Die IP-Adresse 192.168.0.10 ist mehreren Computern zugewiesen.",
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"event":"ip_conflict","locale":"de","subject":"IP-Adressenkonflikt",
+				"body":"Die IP-Adresse 192.168.0.10 ist mehreren Computern zugewiesen."}`,
+		},
+		{
+			name:                 "invalid locale",
+			event:                "ip_conflict",
+			query:                "?locale=../en",
+			mockBehavior:         func(m *mocks.MockNotificationMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid query parameter 'locale'","code":"validation_failed"}`,
+		},
+		{
+			name:  "template not found",
+			event: "unknown",
+			mockBehavior: func(m *mocks.MockNotificationMgmtService) {
+				m.EXPECT().PreviewNotificationTemplate("unknown", "").Return(model.NotificationPreview{},
+					errs.NewNotFound(`notification template "unknown" not found`))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"notification template \"unknown\" not found","code":"not_found"}`,
+		},
+		{
+			name:  "service returns error",
+			event: "warranty_expiry",
+			mockBehavior: func(m *mocks.MockNotificationMgmtService) {
+				m.EXPECT().PreviewNotificationTemplate("warranty_expiry", "").Return(model.NotificationPreview{},
+					fmt.Errorf("template failure"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockNotificationMgmtService := mocks.NewMockNotificationMgmtService(ctrl)
+			tt.mockBehavior(mockNotificationMgmtService)
+
+			req := httptest.NewRequest(http.MethodGet, "/notifications/templates/"+tt.event+"/preview"+tt.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"event": tt.event})
+			rec := httptest.NewRecorder()
+
+			handler := NewNotificationHandler(mockNotificationMgmtService)
+
+			// Act
+			handler.PreviewNotificationTemplate(rec, req)
+
+			// Assert
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			assert.JSONEq(t, tt.expectedResponseBody, string(body))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uhuaha/computers-management/internal/mocks"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseIPAddressHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockSubnetMgmtService)
+
+	tests := []struct {
+		name                 string
+		subnetID             string
+		ipAddress            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "success: returns 204",
+			subnetID:  "1",
+			ipAddress: "10.0.0.10",
+			mockBehavior: func(m *mocks.MockSubnetMgmtService) {
+				m.EXPECT().ReleaseIPAddress(1, "10.0.0.10").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:      "address not allocated",
+			subnetID:  "2",
+			ipAddress: "10.0.0.11",
+			mockBehavior: func(m *mocks.MockSubnetMgmtService) {
+				m.EXPECT().ReleaseIPAddress(2, "10.0.0.11").Return(errs.NewNotFound("IP address 10.0.0.11 is not allocated"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"IP address 10.0.0.11 is not allocated","code":"not_found"}`,
+		},
+		{
+			name:      "service returns error",
+			subnetID:  "3",
+			ipAddress: "10.0.0.12",
+			mockBehavior: func(m *mocks.MockSubnetMgmtService) {
+				m.EXPECT().ReleaseIPAddress(3, "10.0.0.12").Return(fmt.Errorf("db failure"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+		{
+			name:                 "invalid subnetID in URL",
+			subnetID:             "abc",
+			ipAddress:            "10.0.0.10",
+			mockBehavior:         func(m *mocks.MockSubnetMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter 'subnetID'","code":"validation_failed"}`,
+		},
+		{
+			name:                 "invalid IP address in URL",
+			subnetID:             "1",
+			ipAddress:            "10.0.0.256",
+			mockBehavior:         func(m *mocks.MockSubnetMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter 'ipAddress'","code":"validation_failed"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/subnets/"+tt.subnetID+"/allocations/"+tt.ipAddress, nil)
+			req = mux.SetURLVars(req, map[string]string{"subnetID": tt.subnetID, "ipAddress": tt.ipAddress})
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockSubnetMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := NewSubnetHandler(mockService)
+			handler.ReleaseIPAddress(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bdlm/log"
+	"github.com/klauspost/compress/zstd"
+	"gopkg.in/yaml.v3"
+)
+
+// minCompressSize is the size below which a response body is not worth compressing.
+const minCompressSize = 1024
+
+// responseFormat encodes response bodies as one media type.
+type responseFormat struct {
+	// name distinguishes the ETags of the representations in different formats.
+	name string
+	// mediaTypes lists the media types matched in the Accept header. The first one is sent as Content-Type.
+	mediaTypes []string
+	// contentType is the value of the Content-Type header.
+	contentType string
+	encode      func(body any) ([]byte, error)
+}
+
+var (
+	jsonFormat = responseFormat{
+		name:        "json",
+		mediaTypes:  []string{"application/json"},
+		contentType: "application/json",
+		encode:      json.Marshal,
+	}
+	yamlFormat = responseFormat{
+		name:        "yaml",
+		mediaTypes:  []string{"application/yaml", "application/x-yaml", "text/yaml"},
+		contentType: "application/yaml",
+		encode:      encodeYAML,
+	}
+	csvFormat = responseFormat{
+		name:        "csv",
+		mediaTypes:  []string{"text/csv"},
+		contentType: "text/csv; charset=utf-8",
+		encode:      encodeCSV,
+	}
+)
+
+var (
+	// readFormats are offered by the endpoints retrieving resources.
+	readFormats = []responseFormat{jsonFormat, yamlFormat}
+	// computerFormats are offered by the endpoints retrieving computers, which can be represented as a table.
+	computerFormats = []responseFormat{jsonFormat, yamlFormat, csvFormat}
+)
+
+// zstdEncoder is shared by all responses since EncodeAll may be called concurrently.
+var zstdEncoder, _ = zstd.NewWriter(nil)
+
+// negotiateFormat selects the offered format that is most preferred by the Accept header of the request. Formats
+// with the same preference are selected in the offered order. If no format is acceptable, it answers with
+// 406 Not Acceptable and reports false.
+func negotiateFormat(w http.ResponseWriter, r *http.Request, offered []responseFormat) (responseFormat, bool) {
+	w.Header().Add("Vary", "Accept")
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return offered[0], true
+	}
+
+	var (
+		best        responseFormat
+		bestQuality float64
+	)
+
+	for _, format := range offered {
+		quality := 0.0
+		for _, mediaType := range format.mediaTypes {
+			quality = max(quality, acceptQuality(accept, mediaType))
+		}
+
+		if quality > bestQuality {
+			best, bestQuality = format, quality
+		}
+	}
+
+	if bestQuality == 0 {
+		var mediaTypes []string
+		for _, format := range offered {
+			mediaTypes = append(mediaTypes, format.mediaTypes[0])
+		}
+
+		handleError(w, r, &notAcceptableError{msg: "Supported media types: " + strings.Join(mediaTypes, ", ")})
+
+		return responseFormat{}, false
+	}
+
+	return best, true
+}
+
+// acceptQuality returns the quality value of the most specific media range of the Accept header matching the
+// media type, or 0 if it is not acceptable.
+func acceptQuality(accept, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+
+	quality, specificity := 0.0, -1
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		rangeType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		var rangeSpecificity int
+		switch rangeType {
+		case mediaType:
+			rangeSpecificity = 2
+		case typ + "/*":
+			rangeSpecificity = 1
+		case "*/*":
+			rangeSpecificity = 0
+		default:
+			continue
+		}
+
+		if rangeSpecificity > specificity {
+			quality, specificity = parseQuality(params), rangeSpecificity
+		}
+	}
+
+	return quality
+}
+
+func parseQuality(params map[string]string) float64 {
+	q, ok := params["q"]
+	if !ok {
+		return 1
+	}
+
+	quality, err := strconv.ParseFloat(q, 64)
+	if err != nil || quality < 0 || quality > 1 {
+		return 0
+	}
+
+	return quality
+}
+
+// writeResponse encodes body in the given format and writes it with the given status code. The body is compressed
+// with zstd or gzip if the Accept-Encoding header of the request allows it and if it is large enough.
+func writeResponse(w http.ResponseWriter, r *http.Request, statusCode int, format responseFormat, body any) {
+	res, err := format.encode(body)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to encode response: %w", err))
+		return
+	}
+
+	w.Header().Add("Vary", "Accept-Encoding")
+
+	if len(res) >= minCompressSize {
+		switch negotiateEncoding(r.Header.Get("Accept-Encoding")) {
+		case "zstd":
+			res = zstdEncoder.EncodeAll(res, nil)
+			w.Header().Set("Content-Encoding", "zstd")
+		case "gzip":
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			if _, err := gz.Write(res); err == nil && gz.Close() == nil {
+				res = buf.Bytes()
+				w.Header().Set("Content-Encoding", "gzip")
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", format.contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(res)))
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(res); err != nil {
+		log.Error("failed to write response body: " + err.Error())
+	}
+}
+
+// negotiateEncoding returns the most preferred of the supported content codings zstd and gzip or an empty string
+// if the response should not be compressed. zstd is preferred if both are equally acceptable.
+func negotiateEncoding(acceptEncoding string) string {
+	qualities := map[string]float64{}
+
+	for _, coding := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(coding), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			quality = parseQuality(map[string]string{"q": q})
+		}
+
+		qualities[strings.ToLower(strings.TrimSpace(name))] = quality
+	}
+
+	best, bestQuality := "", 0.0
+	for _, coding := range []string{"zstd", "gzip"} {
+		quality, ok := qualities[coding]
+		if !ok {
+			quality = qualities["*"]
+		}
+
+		if quality > bestQuality {
+			best, bestQuality = coding, quality
+		}
+	}
+
+	return best
+}
+
+// encodeYAML encodes body as YAML using the field names and the field order of its JSON encoding.
+func encodeYAML(body any) ([]byte, error) {
+	res, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is valid YAML in flow style, so it is decoded into a node tree that keeps the field order.
+	var node yaml.Node
+	if err := yaml.Unmarshal(res, &node); err != nil {
+		return nil, err
+	}
+
+	resetStyle(&node)
+
+	return yaml.Marshal(&node)
+}
+
+// resetStyle lets the YAML encoder choose the block style instead of the flow style of JSON.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// csvTable is implemented by response bodies that can be represented as a table.
+type csvTable interface {
+	// csvRecords returns the header followed by one record per row.
+	csvRecords() [][]string
+}
+
+func encodeCSV(body any) ([]byte, error) {
+	table, ok := body.(csvTable)
+	if !ok {
+		return nil, fmt.Errorf("%T cannot be encoded as CSV", body)
+	}
+
+	var buf bytes.Buffer
+
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(table.csvRecords()); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// computerCSVHeader lists the columns of the CSV representation of computers.
+var computerCSVHeader = []string{
+	"id", "name", "ip_address", "mac_address", "employee_abbreviation", "description", "subnet_id", "status",
+	"serial_number", "purchase_date", "warranty_end", "vendor", "model", "operating_system", "location",
+}
+
+func (c GetComputerByIDResponse) csvRecord() []string {
+	stringOf := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+
+		return *s
+	}
+
+	dateOf := func(d *Date) string {
+		if d == nil {
+			return ""
+		}
+
+		return d.Format(dateLayout)
+	}
+
+	var subnetID string
+	if c.SubnetID != nil {
+		subnetID = strconv.Itoa(*c.SubnetID)
+	}
+
+	return []string{
+		strconv.Itoa(c.ID), c.Name, c.IPAddress, c.MACAddress, stringOf(c.EmployeeAbbreviation),
+		stringOf(c.Description), subnetID, c.Status, stringOf(c.SerialNumber), dateOf(c.PurchaseDate),
+		dateOf(c.WarrantyEnd), stringOf(c.Vendor), stringOf(c.Model), stringOf(c.OperatingSystem), stringOf(c.Location),
+	}
+}
+
+func (c GetComputerByIDResponse) csvRecords() [][]string {
+	return [][]string{slices.Clone(computerCSVHeader), c.csvRecord()}
+}
+
+func (c GetComputersResponse) csvRecords() [][]string {
+	records := [][]string{slices.Clone(computerCSVHeader)}
+	for _, computer := range c.Computers {
+		records = append(records, computer.csvRecord())
+	}
+
+	return records
+}
@@ -0,0 +1,320 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		name                string
+		accept              string
+		offered             []responseFormat
+		expectedContentType string
+		expectedStatusCode  int
+	}{
+		{
+			name:                "missing Accept selects JSON",
+			offered:             computerFormats,
+			expectedContentType: "application/json",
+		},
+		{
+			name:                "wildcard selects JSON",
+			accept:              "*/*",
+			offered:             computerFormats,
+			expectedContentType: "application/json",
+		},
+		{
+			name:                "exact media type selects CSV",
+			accept:              "text/csv",
+			offered:             computerFormats,
+			expectedContentType: "text/csv; charset=utf-8",
+		},
+		{
+			name:                "alias selects YAML",
+			accept:              "text/yaml",
+			offered:             readFormats,
+			expectedContentType: "application/yaml",
+		},
+		{
+			name:                "highest quality value wins",
+			accept:              "application/json;q=0.5, application/yaml;q=0.9, */*;q=0.1",
+			offered:             computerFormats,
+			expectedContentType: "application/yaml",
+		},
+		{
+			name:                "more specific media range overrides wildcard",
+			accept:              "text/*, text/csv;q=0",
+			offered:             computerFormats,
+			expectedContentType: "application/yaml",
+		},
+		{
+			name:               "CSV is not offered",
+			accept:             "text/csv",
+			offered:            readFormats,
+			expectedStatusCode: http.StatusNotAcceptable,
+		},
+		{
+			name:               "unsupported media type",
+			accept:             "application/xml",
+			offered:            computerFormats,
+			expectedStatusCode: http.StatusNotAcceptable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/computers", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			rec := httptest.NewRecorder()
+
+			// Act
+			format, ok := negotiateFormat(rec, req, tt.offered)
+
+			// Assert
+			assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+
+			if tt.expectedStatusCode == http.StatusNotAcceptable {
+				assert.False(t, ok)
+				assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+				assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+				return
+			}
+
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedContentType, format.contentType)
+		})
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{acceptEncoding: "", expected: ""},
+		{acceptEncoding: "identity", expected: ""},
+		{acceptEncoding: "gzip", expected: "gzip"},
+		{acceptEncoding: "gzip, deflate, br, zstd", expected: "zstd"},
+		{acceptEncoding: "zstd;q=0.5, gzip", expected: "gzip"},
+		{acceptEncoding: "*", expected: "zstd"},
+		{acceptEncoding: "*, zstd;q=0", expected: "gzip"},
+		{acceptEncoding: "gzip;q=0", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			assert.Equal(t, tt.expected, negotiateEncoding(tt.acceptEncoding))
+		})
+	}
+}
+
+func TestWriteResponse(t *testing.T) {
+	large := GetComputersResponse{}
+	for i := range 50 {
+		large.Computers = append(large.Computers, GetComputerByIDResponse{ID: i, Name: "PC", IPAddress: "192.168.0.1", MACAddress: "AA:BB:CC:DD:EE:FF"})
+	}
+
+	expectedBody, err := jsonFormat.encode(large)
+	require.NoError(t, err)
+
+	decodeGzip := func(body []byte) ([]byte, error) {
+		reader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+
+		return io.ReadAll(reader)
+	}
+
+	decodeZstd := func(body []byte) ([]byte, error) {
+		decoder, err := zstd.NewReader(nil)
+		if err != nil {
+			return nil, err
+		}
+		defer decoder.Close()
+
+		return decoder.DecodeAll(body, nil)
+	}
+
+	tests := []struct {
+		name                    string
+		acceptEncoding          string
+		body                    any
+		expectedContentEncoding string
+		decode                  func(body []byte) ([]byte, error)
+	}{
+		{
+			name:   "uncompressed without Accept-Encoding",
+			body:   large,
+			decode: func(body []byte) ([]byte, error) { return body, nil },
+		},
+		{
+			name:                    "gzip",
+			acceptEncoding:          "gzip",
+			body:                    large,
+			expectedContentEncoding: "gzip",
+			decode:                  decodeGzip,
+		},
+		{
+			name:                    "zstd",
+			acceptEncoding:          "gzip, zstd",
+			body:                    large,
+			expectedContentEncoding: "zstd",
+			decode:                  decodeZstd,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/computers", nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+
+			rec := httptest.NewRecorder()
+
+			// Act
+			writeResponse(rec, req, http.StatusOK, jsonFormat, tt.body)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedContentEncoding, rec.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+
+			body, err := tt.decode(rec.Body.Bytes())
+			require.NoError(t, err)
+			assert.Equal(t, expectedBody, body)
+		})
+	}
+
+	t.Run("small bodies are not compressed", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest(http.MethodGet, "/computers", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		rec := httptest.NewRecorder()
+
+		// Act
+		writeResponse(rec, req, http.StatusCreated, jsonFormat, AddComputerResponse{ID: 1})
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.JSONEq(t, `{"id":1}`, rec.Body.String())
+	})
+}
+
+func TestGetComputerByIDFormats(t *testing.T) {
+	purchaseDate := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	computer := model.Computer{
+		ID:                   1,
+		Name:                 "PC1",
+		IPAddress:            "192.168.0.1",
+		MACAddress:           "AA:BB:CC:DD:EE:FF",
+		EmployeeAbbreviation: toPointer("EMP"),
+		Description:          toPointer(`Office PC, "new"`),
+		Status:               "assigned",
+		PurchaseDate:         &purchaseDate,
+	}
+
+	tests := []struct {
+		name                 string
+		accept               string
+		expectedContentType  string
+		expectedResponseBody string
+	}{
+		{
+			name:                "YAML keeps the JSON field names and order",
+			accept:              "application/yaml",
+			expectedContentType: "application/yaml",
+			expectedResponseBody: strings.Join([]string{
+				"id: 1",
+				"name: PC1",
+				"ip_address: 192.168.0.1",
+				"mac_address: AA:BB:CC:DD:EE:FF",
+				"employee_abbreviation: EMP",
+				`description: Office PC, "new"`,
+				"status: assigned",
+				`purchase_date: "2024-05-02"`,
+				"",
+			}, "\n"),
+		},
+		{
+			name:                "CSV has a header and a record",
+			accept:              "text/csv",
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedResponseBody: "id,name,ip_address,mac_address,employee_abbreviation,description,subnet_id,status," +
+				"serial_number,purchase_date,warranty_end,vendor,model,operating_system,location\n" +
+				`1,PC1,192.168.0.1,AA:BB:CC:DD:EE:FF,EMP,"Office PC, ""new""",,assigned,,2024-05-02,,,,,` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockComputerMgmtService := mocks.NewMockComputerMgmtService(ctrl)
+			mockComputerMgmtService.EXPECT().GetComputerVersion(gomock.Any(), 1).Return(model.ComputersVersion{Version: 1}, nil)
+			mockComputerMgmtService.EXPECT().GetVersionedComputer(gomock.Any(), 1).Return(computer, model.ComputersVersion{Version: 1}, nil)
+
+			handler := New(mockComputerMgmtService)
+
+			req := httptest.NewRequest(http.MethodGet, "/computers/1", nil)
+			req = mux.SetURLVars(req, map[string]string{"computerID": "1"})
+			req.Header.Set("Accept", tt.accept)
+
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.GetComputerByID(rec, req)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.expectedContentType, rec.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedResponseBody, rec.Body.String())
+		})
+	}
+
+	t.Run("unsupported media type returns 406 without loading the computer", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		handler := New(mocks.NewMockComputerMgmtService(ctrl))
+
+		req := httptest.NewRequest(http.MethodGet, "/computers/1", nil)
+		req = mux.SetURLVars(req, map[string]string{"computerID": "1"})
+		req.Header.Set("Accept", "application/xml")
+
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.GetComputerByID(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+		assert.JSONEq(t, `{"type":"/problems/not_acceptable","title":"Not acceptable","status":406,"detail":"Supported media types: application/json, application/yaml, text/csv","code":"not_acceptable"}`, rec.Body.String())
+	})
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchComputersHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockComputerMgmtService)
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "success: return 200 with ranked results",
+			query: "dev",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					SearchComputers(gomock.Any(), "dev").
+					Return([]model.ComputerSearchResult{
+						{
+							Computer:   model.Computer{ID: 1, Name: "DevPC", IPAddress: "192.168.0.1", MACAddress: "AA:BB:CC:DD:EE:FF"},
+							Rank:       0.5,
+							Highlights: map[string]string{"name": "<mark>Dev</mark>PC"},
+						},
+					}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"results":[{
+				"computer":{"id":1,"name":"DevPC","ip_address":"192.168.0.1","mac_address":"AA:BB:CC:DD:EE:FF"},
+				"rank":0.5,
+				"highlights":{"name":"<mark>Dev</mark>PC"}
+			}]}`,
+		},
+		{
+			name:  "query is trimmed",
+			query: "  office pc ",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().SearchComputers(gomock.Any(), "office pc").Return([]model.ComputerSearchResult{}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"results":[]}`,
+		},
+		{
+			name:                 "missing query",
+			query:                " ",
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Missing query parameter 'q'","code":"validation_failed"}`,
+		},
+		{
+			name:  "service returns error",
+			query: "dev",
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().SearchComputers(gomock.Any(), "dev").Return(nil, fmt.Errorf("db failure"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockComputerMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/computers/search?q="+url.QueryEscape(tt.query), nil)
+			rec := httptest.NewRecorder()
+
+			handler := New(mockService)
+			handler.SearchComputers(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamComputerEventsHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockEventMgmtService, published chan model.ComputerEvent)
+
+	occurredAt := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	event := func(id int64, eventType model.ComputerEventType, computerID int, employee *string) model.ComputerEvent {
+		return model.ComputerEvent{ID: id, Type: eventType, ComputerID: computerID, EmployeeAbbreviation: employee, OccurredAt: occurredAt}
+	}
+
+	tests := []struct {
+		name                 string
+		query                string
+		lastEventID          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "success: streams the published events matching the employee",
+			query: "?employee=EMP",
+			mockBehavior: func(m *mocks.MockEventMgmtService, published chan model.ComputerEvent) {
+				m.EXPECT().SubscribeToComputerEvents().Return(published, func() {})
+
+				published <- event(1, model.ComputerCreated, 1, toPointer("EMP"))
+				published <- event(2, model.ComputerAssigned, 2, toPointer("OTH"))
+				published <- event(3, model.ComputerDeleted, 1, toPointer("EMP"))
+				close(published)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: "id: 1\nevent: computer.created\n" +
+				`data: {"id":1,"type":"computer.created","computer_id":1,"employee_abbreviation":"EMP","occurred_at":"2025-03-10T08:00:00Z"}` + "\n\n" +
+				"id: 3\nevent: computer.deleted\n" +
+				`data: {"id":3,"type":"computer.deleted","computer_id":1,"employee_abbreviation":"EMP","occurred_at":"2025-03-10T08:00:00Z"}` + "\n\n",
+		},
+		{
+			name:        "success: resumes from the event log and skips published events sent already",
+			query:       "?computer_id=2",
+			lastEventID: "3",
+			mockBehavior: func(m *mocks.MockEventMgmtService, published chan model.ComputerEvent) {
+				m.EXPECT().SubscribeToComputerEvents().Return(published, func() {})
+				m.EXPECT().GetComputerEventsSince(gomock.Any(), int64(3), eventLogPageSize).Return([]model.ComputerEvent{
+					event(4, model.ComputerUpdated, 2, nil),
+					event(5, model.ComputerUpdated, 1, nil),
+				}, nil)
+
+				published <- event(4, model.ComputerUpdated, 2, nil)
+				published <- event(6, model.ComputerDeleted, 2, nil)
+				close(published)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: "id: 4\nevent: computer.updated\n" +
+				`data: {"id":4,"type":"computer.updated","computer_id":2,"occurred_at":"2025-03-10T08:00:00Z"}` + "\n\n" +
+				"id: 6\nevent: computer.deleted\n" +
+				`data: {"id":6,"type":"computer.deleted","computer_id":2,"occurred_at":"2025-03-10T08:00:00Z"}` + "\n\n",
+		},
+		{
+			name:        "event log fails: stream ends without events",
+			lastEventID: "3",
+			mockBehavior: func(m *mocks.MockEventMgmtService, published chan model.ComputerEvent) {
+				m.EXPECT().SubscribeToComputerEvents().Return(published, func() {})
+				m.EXPECT().GetComputerEventsSince(gomock.Any(), int64(3), eventLogPageSize).Return(nil, fmt.Errorf("db failure"))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "",
+		},
+		{
+			name:                 "invalid employee",
+			query:                "?employee=EMPLOYEE",
+			mockBehavior:         func(m *mocks.MockEventMgmtService, published chan model.ComputerEvent) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid query parameter 'employee': it must be a 3-characters string","code":"validation_failed"}`,
+		},
+		{
+			name:                 "invalid computer ID",
+			query:                "?computer_id=abc",
+			mockBehavior:         func(m *mocks.MockEventMgmtService, published chan model.ComputerEvent) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid query parameter 'computer_id': it must be a positive integer","code":"validation_failed"}`,
+		},
+		{
+			name:                 "invalid Last-Event-ID",
+			lastEventID:          "-1",
+			mockBehavior:         func(m *mocks.MockEventMgmtService, published chan model.ComputerEvent) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid header 'Last-Event-ID': it must be the ID of a received event","code":"validation_failed"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockEventMgmtService := mocks.NewMockEventMgmtService(ctrl)
+			tt.mockBehavior(mockEventMgmtService, make(chan model.ComputerEvent, 10))
+
+			handler := NewEventHandler(mockEventMgmtService, time.Hour)
+
+			req := httptest.NewRequest(http.MethodGet, "/events"+tt.query, nil)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.StreamComputerEvents(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, rec.Code)
+
+			if tt.expectedStatusCode != http.StatusOK {
+				assert.JSONEq(t, tt.expectedResponseBody, rec.Body.String())
+				return
+			}
+
+			assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedResponseBody, rec.Body.String())
+		})
+	}
+}
+
+func TestStreamComputerEventsHandlerHeartbeat(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	unsubscribed := false
+
+	mockEventMgmtService := mocks.NewMockEventMgmtService(ctrl)
+	mockEventMgmtService.EXPECT().SubscribeToComputerEvents().Return(make(chan model.ComputerEvent), func() { unsubscribed = true })
+
+	handler := NewEventHandler(mockEventMgmtService, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.StreamComputerEvents(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), ": heartbeat\n\n")
+	assert.True(t, unsubscribed)
+}
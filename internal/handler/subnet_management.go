@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/bdlm/log"
+	"github.com/gorilla/mux"
+)
+
+type SubnetMgmtService interface {
+	AddSubnet(subnet model.Subnet) (int, error)
+	GetSubnet(subnetID int) (model.Subnet, error)
+	GetAllSubnets() ([]model.Subnet, error)
+	UpdateSubnet(subnetID int, data model.Subnet) error
+	DeleteSubnet(subnetID int) error
+	GetSubnetUtilization(subnetID int) (model.SubnetUtilization, error)
+	AllocateIPAddress(subnetID int) (string, error)
+	ReleaseIPAddress(subnetID int, ipAddress string) error
+}
+
+type SubnetMgmtHandler struct {
+	subnetMgmtService SubnetMgmtService
+}
+
+func NewSubnetHandler(service SubnetMgmtService) *SubnetMgmtHandler {
+	return &SubnetMgmtHandler{
+		subnetMgmtService: service,
+	}
+}
+
+// AddSubnet adds the provided subnet.
+func (s *SubnetMgmtHandler) AddSubnet(w http.ResponseWriter, r *http.Request) {
+	var data AddSubnetRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Error("failed to decode the request body: " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid request body"))
+		return
+	}
+
+	if msg := validateSubnet(data.Name, data.CIDR, data.Gateway, data.VLAN, data.ReservedRanges); msg != "" {
+		log.Error("failed to validate subnet: " + msg)
+		handleError(w, r, errs.NewValidation(msg))
+		return
+	}
+
+	subnet := model.Subnet{
+		Name:           data.Name,
+		CIDR:           data.CIDR,
+		Gateway:        data.Gateway,
+		VLAN:           data.VLAN,
+		ReservedRanges: convertIPRangeDTOsToModels(data.ReservedRanges),
+	}
+
+	subnetID, err := s.subnetMgmtService.AddSubnet(subnet)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to add subnet: %w", err))
+		return
+	}
+
+	response := AddSubnetResponse{ID: subnetID}
+
+	writeResponse(w, r, http.StatusCreated, jsonFormat, response)
+}
+
+// GetSubnetByID gets a subnet's data by its ID.
+func (s *SubnetMgmtHandler) GetSubnetByID(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateFormat(w, r, readFormats)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	paramSubnetID := vars["subnetID"]
+	subnetID, err := strconv.Atoi(paramSubnetID)
+	if err != nil {
+		log.Error("failed to parse URL parameter 'subnetID': " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'subnetID'"))
+		return
+	}
+
+	subnet, err := s.subnetMgmtService.GetSubnet(subnetID)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to get subnet by ID: %w", err))
+		return
+	}
+
+	response := convertSubnetModelToDTO(subnet)
+
+	writeResponse(w, r, http.StatusOK, format, response)
+}
+
+// GetAllSubnets retrieves all subnets from the storage.
+func (s *SubnetMgmtHandler) GetAllSubnets(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateFormat(w, r, readFormats)
+	if !ok {
+		return
+	}
+
+	subnets, err := s.subnetMgmtService.GetAllSubnets()
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to get all subnets: %w", err))
+		return
+	}
+
+	response := convertSubnetModelsToDTOs(subnets)
+
+	writeResponse(w, r, http.StatusOK, format, response)
+}
+
+// UpdateSubnet updates a subnet's data.
+func (s *SubnetMgmtHandler) UpdateSubnet(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	paramSubnetID := vars["subnetID"]
+	subnetID, err := strconv.Atoi(paramSubnetID)
+	if err != nil {
+		log.Error("failed to parse URL parameter 'subnetID': " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'subnetID'"))
+		return
+	}
+
+	var data UpdateSubnetRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Error("failed to decode the request body: " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid request body"))
+		return
+	}
+
+	if msg := validateSubnet(data.Name, data.CIDR, data.Gateway, data.VLAN, data.ReservedRanges); msg != "" {
+		log.Error("failed to validate subnet: " + msg)
+		handleError(w, r, errs.NewValidation(msg))
+		return
+	}
+
+	subnet := model.Subnet{
+		Name:           data.Name,
+		CIDR:           data.CIDR,
+		Gateway:        data.Gateway,
+		VLAN:           data.VLAN,
+		ReservedRanges: convertIPRangeDTOsToModels(data.ReservedRanges),
+	}
+
+	if err := s.subnetMgmtService.UpdateSubnet(subnetID, subnet); err != nil {
+		handleError(w, r, fmt.Errorf("failed to update subnet: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteSubnet deletes a subnet by its ID.
+func (s *SubnetMgmtHandler) DeleteSubnet(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	paramSubnetID := vars["subnetID"]
+	subnetID, err := strconv.Atoi(paramSubnetID)
+	if err != nil {
+		log.Error("failed to parse URL parameter 'subnetID': " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'subnetID'"))
+		return
+	}
+
+	if err := s.subnetMgmtService.DeleteSubnet(subnetID); err != nil {
+		handleError(w, r, fmt.Errorf("failed to delete subnet: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSubnetUtilization retrieves how many addresses of a subnet are used, allocated, reserved and free.
+func (s *SubnetMgmtHandler) GetSubnetUtilization(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateFormat(w, r, readFormats)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	paramSubnetID := vars["subnetID"]
+	subnetID, err := strconv.Atoi(paramSubnetID)
+	if err != nil {
+		log.Error("failed to parse URL parameter 'subnetID': " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'subnetID'"))
+		return
+	}
+
+	utilization, err := s.subnetMgmtService.GetSubnetUtilization(subnetID)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to get subnet utilization: %w", err))
+		return
+	}
+
+	response := convertSubnetUtilizationToDTO(utilization)
+
+	writeResponse(w, r, http.StatusOK, format, response)
+}
+
+// AllocateIPAddress reserves and returns the next free IP address of a subnet.
+func (s *SubnetMgmtHandler) AllocateIPAddress(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	paramSubnetID := vars["subnetID"]
+	subnetID, err := strconv.Atoi(paramSubnetID)
+	if err != nil {
+		log.Error("failed to parse URL parameter 'subnetID': " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'subnetID'"))
+		return
+	}
+
+	ipAddress, err := s.subnetMgmtService.AllocateIPAddress(subnetID)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to allocate IP address: %w", err))
+		return
+	}
+
+	response := AllocateIPAddressResponse{IPAddress: ipAddress}
+
+	writeResponse(w, r, http.StatusCreated, jsonFormat, response)
+}
+
+// ReleaseIPAddress releases an allocated IP address of a subnet, so that it can be allocated again.
+func (s *SubnetMgmtHandler) ReleaseIPAddress(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	paramSubnetID := vars["subnetID"]
+	subnetID, err := strconv.Atoi(paramSubnetID)
+	if err != nil {
+		log.Error("failed to parse URL parameter 'subnetID': " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'subnetID'"))
+		return
+	}
+
+	ipAddress := vars["ipAddress"]
+	if !isValidIPAddress(ipAddress) {
+		log.Error("failed to parse URL parameter 'ipAddress': " + ipAddress)
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'ipAddress'"))
+		return
+	}
+
+	if err := s.subnetMgmtService.ReleaseIPAddress(subnetID, ipAddress); err != nil {
+		handleError(w, r, fmt.Errorf("failed to release IP address: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
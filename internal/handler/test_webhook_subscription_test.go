@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestWebhookSubscriptionHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockWebhookMgmtService)
+
+	deliveredAt := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		urlParam             string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "success: returns the successful delivery",
+			urlParam: "1",
+			mockBehavior: func(m *mocks.MockWebhookMgmtService) {
+				m.EXPECT().TestWebhookSubscription(gomock.Any(), 1).Return(model.WebhookDelivery{
+					ID: 5, SubscriptionID: 1, EventType: model.WebhookTest, Attempt: 1, StatusCode: intToPointer(200),
+					Succeeded: true, Duration: 42 * time.Millisecond, DeliveredAt: deliveredAt,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"id":5,"event_type":"webhook.test","attempt":1,"status_code":200,"succeeded":true,
+				"duration_ms":42,"delivered_at":"2025-03-10T08:00:00Z"}`,
+		},
+		{
+			name:     "success: returns the failed delivery",
+			urlParam: "1",
+			mockBehavior: func(m *mocks.MockWebhookMgmtService) {
+				m.EXPECT().TestWebhookSubscription(gomock.Any(), 1).Return(model.WebhookDelivery{
+					ID: 6, SubscriptionID: 1, EventType: model.WebhookTest, Attempt: 1, Error: toPointer("connection refused"),
+					DeliveredAt: deliveredAt,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"id":6,"event_type":"webhook.test","attempt":1,"error":"connection refused","succeeded":false,
+				"duration_ms":0,"delivered_at":"2025-03-10T08:00:00Z"}`,
+		},
+		{
+			name:                 "invalid webhook ID",
+			urlParam:             "abc",
+			mockBehavior:         func(m *mocks.MockWebhookMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter 'webhookID'","code":"validation_failed"}`,
+		},
+		{
+			name:     "subscription not found",
+			urlParam: "2",
+			mockBehavior: func(m *mocks.MockWebhookMgmtService) {
+				m.EXPECT().TestWebhookSubscription(gomock.Any(), 2).Return(model.WebhookDelivery{}, errs.NewNotFound("webhook subscription not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"webhook subscription not found","code":"not_found"}`,
+		},
+		{
+			name:     "service returns error",
+			urlParam: "3",
+			mockBehavior: func(m *mocks.MockWebhookMgmtService) {
+				m.EXPECT().TestWebhookSubscription(gomock.Any(), 3).Return(model.WebhookDelivery{}, fmt.Errorf("db failure"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockWebhookMgmtService := mocks.NewMockWebhookMgmtService(ctrl)
+			tt.mockBehavior(mockWebhookMgmtService)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/"+tt.urlParam+"/test", nil)
+			req = mux.SetURLVars(req, map[string]string{"webhookID": tt.urlParam})
+			rec := httptest.NewRecorder()
+
+			handler := NewWebhookHandler(mockWebhookMgmtService)
+
+			// Act
+			handler.TestWebhookSubscription(rec, req)
+
+			// Assert
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			assert.JSONEq(t, tt.expectedResponseBody, string(body))
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransitionComputerStatusHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockComputerMgmtService)
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		urlParam             string
+		requestBody          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "success: transition is recorded",
+			urlParam:    "1",
+			requestBody: `{"status":"in_repair","reason":"Broken display","actor":"ADM"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					TransitionComputerStatus(1, model.StatusTransition{
+						To:     model.StatusInRepair,
+						Reason: "Broken display",
+						Actor:  "ADM",
+					}).
+					Return(model.StatusTransition{
+						ID:         7,
+						ComputerID: 1,
+						From:       model.StatusAssigned,
+						To:         model.StatusInRepair,
+						Reason:     "Broken display",
+						Actor:      "ADM",
+						CreatedAt:  createdAt,
+					}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponseBody: `{"id":7,"computer_id":1,"from_status":"assigned","to_status":"in_repair",
+				"reason":"Broken display","actor":"ADM","created_at":"2025-03-01T12:00:00Z"}`,
+		},
+		{
+			name:        "transition is not allowed",
+			urlParam:    "2",
+			requestBody: `{"status":"retired","reason":"End of life","actor":"ADM"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					TransitionComputerStatus(2, gomock.Any()).
+					Return(model.StatusTransition{}, errs.NewInvalidTransition(
+						`computer with ID=2 cannot change its status from "assigned" to "retired"`,
+						[]string{"in_stock", "in_repair", "lost"},
+					))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"type":"/problems/invalid_status_transition","title":"Invalid status transition","status":409,"detail":"computer with ID=2 cannot change its status from \"assigned\" to \"retired\"","code":"invalid_status_transition","allowed_next_states":["in_stock","in_repair","lost"]}`,
+		},
+		{
+			name:        "assigning requires a check-out",
+			urlParam:    "2",
+			requestBody: `{"status":"assigned","reason":"Hand out","actor":"ADM"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				// no call expected
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid status: computers are assigned via POST /computers/{computerID}/checkout","code":"validation_failed"}`,
+		},
+		{
+			name:        "computer not found",
+			urlParam:    "3",
+			requestBody: `{"status":"lost","reason":"Not returned","actor":"ADM"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					TransitionComputerStatus(3, gomock.Any()).
+					Return(model.StatusTransition{}, &errs.NotFoundError{Msg: "computer not found"})
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"computer not found","code":"not_found"}`,
+		},
+		{
+			name:        "unknown status",
+			urlParam:    "1",
+			requestBody: `{"status":"stolen","reason":"Gone","actor":"ADM"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				// no call expected
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid status: it must be one of in_stock, in_repair, lost or retired","code":"validation_failed"}`,
+		},
+		{
+			name:        "missing reason",
+			urlParam:    "1",
+			requestBody: `{"status":"lost","actor":"ADM"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				// no call expected
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Missing reason","code":"validation_failed"}`,
+		},
+		{
+			name:        "invalid computerID in URL",
+			urlParam:    "abc",
+			requestBody: `{}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				// no call expected
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter 'computerID'","code":"validation_failed"}`,
+		},
+		{
+			name:        "service layer returns error",
+			urlParam:    "4",
+			requestBody: `{"status":"retired","reason":"End of life","actor":"ADM"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					TransitionComputerStatus(4, gomock.Any()).
+					Return(model.StatusTransition{}, fmt.Errorf("db failure"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/computers/"+tt.urlParam+"/transitions", strings.NewReader(tt.requestBody))
+			req = mux.SetURLVars(req, map[string]string{"computerID": tt.urlParam})
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockComputerMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := New(mockService)
+			handler.TransitionComputerStatus(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"uhuaha/computers-management/internal/mocks"
 	"uhuaha/computers-management/internal/model"
 
+	errs "uhuaha/computers-management/internal/errors"
+
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -52,7 +54,7 @@ func TestUpdateComputerHandler(t *testing.T) {
 				// no call expected
 			},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"Invalid URL parameter"}`,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid URL parameter","code":"validation_failed"}`,
 		},
 		{
 			name:        "invalid JSON body",
@@ -62,7 +64,7 @@ func TestUpdateComputerHandler(t *testing.T) {
 				// no call expected
 			},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"Invalid request body"}`,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid request body","code":"validation_failed"}`,
 		},
 		{
 			name:     "invalid employee abbreviation length",
@@ -75,7 +77,55 @@ func TestUpdateComputerHandler(t *testing.T) {
 				// no call expected
 			},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"Invalid employee abbreviation"}`,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid employee abbreviation","code":"validation_failed"}`,
+		},
+		{
+			name:     "invalid IP address",
+			urlParam: "3",
+			requestBody: `{
+				"name": "PCWithBadIP",
+				"ip_address": "not-an-ip",
+				"mac_address": "AA:BB:CC:DD:EE:11"
+			}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				// no call expected
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid IP address","code":"validation_failed"}`,
+		},
+		{
+			name:     "invalid MAC address",
+			urlParam: "3",
+			requestBody: `{
+				"name": "PCWithBadMAC",
+				"ip_address": "10.0.0.11",
+				"mac_address": "AA:BB:CC"
+			}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				// no call expected
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid MAC address","code":"validation_failed"}`,
+		},
+		{
+			name:     "computer cannot be assigned to the new employee",
+			urlParam: "5",
+			requestBody: `{
+				"name": "RepairedPC",
+				"ip_address": "10.0.0.12",
+				"mac_address": "AA:BB:CC:DD:EE:12",
+				"employee_abbreviation": "NEW"
+			}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					UpdateComputer(5, gomock.Any()).
+					Return(errs.NewInvalidTransition(
+						`computer with ID=5 cannot be checked out while its status is "in_repair"`,
+						[]string{"in_stock", "lost", "retired"},
+					))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"type":"/problems/invalid_status_transition","title":"Invalid status transition","status":409,"detail":"computer with ID=5 cannot be checked out while its status is \"in_repair\"","code":"invalid_status_transition","allowed_next_states":["in_stock","lost","retired"]}`,
 		},
 		{
 			name:     "service layer returns error",
@@ -94,7 +144,7 @@ func TestUpdateComputerHandler(t *testing.T) {
 				m.EXPECT().UpdateComputer(4, expected).Return(fmt.Errorf("db failure"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"Failed to update computer"}`,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
 		},
 	}
 
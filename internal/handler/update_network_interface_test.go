@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uhuaha/computers-management/internal/mocks"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateNetworkInterfaceHandler(t *testing.T) {
+	type mockBehavior func(m *mocks.MockComputerMgmtService)
+
+	tests := []struct {
+		name                 string
+		computerID           string
+		interfaceID          string
+		requestBody          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "success: interface is updated",
+			computerID:  "1",
+			interfaceID: "2",
+			requestBody: `{"name":"eth1","mac_address":"AA:BB:CC:DD:EE:02","ip_address":"10.0.0.2","type":"ethernet","primary":true}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					UpdateNetworkInterface(1, 2, model.NetworkInterface{
+						Name:       "eth1",
+						MACAddress: "AA:BB:CC:DD:EE:02",
+						IPAddress:  toPointer("10.0.0.2"),
+						Type:       model.InterfaceTypeEthernet,
+						IsPrimary:  true,
+					}).
+					Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:                 "invalid request body",
+			computerID:           "1",
+			interfaceID:          "2",
+			requestBody:          `{"name":`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid request body","code":"validation_failed"}`,
+		},
+		{
+			name:                 "missing name",
+			computerID:           "1",
+			interfaceID:          "2",
+			requestBody:          `{"name":" ","mac_address":"AA:BB:CC:DD:EE:02"}`,
+			mockBehavior:         func(m *mocks.MockComputerMgmtService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"/problems/validation_failed","title":"Validation failed","status":400,"detail":"Invalid interface name: it must be a non-empty string of at most 255 characters","code":"validation_failed"}`,
+		},
+		{
+			name:        "primary interface cannot be demoted",
+			computerID:  "1",
+			interfaceID: "1",
+			requestBody: `{"name":"primary","mac_address":"AA:BB:CC:DD:EE:FF","ip_address":"10.0.0.1"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					UpdateNetworkInterface(1, 1, gomock.Any()).
+					Return(errs.NewConflict("the primary network interface cannot be demoted, make another interface primary instead"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"type":"/problems/conflict","title":"Conflict","status":409,"detail":"the primary network interface cannot be demoted, make another interface primary instead","code":"conflict"}`,
+		},
+		{
+			name:        "interface not found",
+			computerID:  "1",
+			interfaceID: "9",
+			requestBody: `{"name":"eth1","mac_address":"AA:BB:CC:DD:EE:02"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					UpdateNetworkInterface(1, 9, gomock.Any()).
+					Return(errs.NewNotFound("network interface not found"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"/problems/not_found","title":"Resource not found","status":404,"detail":"network interface not found","code":"not_found"}`,
+		},
+		{
+			name:        "service error",
+			computerID:  "1",
+			interfaceID: "2",
+			requestBody: `{"name":"eth1","mac_address":"AA:BB:CC:DD:EE:02"}`,
+			mockBehavior: func(m *mocks.MockComputerMgmtService) {
+				m.EXPECT().
+					UpdateNetworkInterface(1, 2, gomock.Any()).
+					Return(fmt.Errorf("database unavailable"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"/problems/internal_error","title":"Internal server error","status":500,"detail":"The request could not be processed due to an unexpected error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/computers/"+tt.computerID+"/interfaces/"+tt.interfaceID, strings.NewReader(tt.requestBody))
+			req = mux.SetURLVars(req, map[string]string{"computerID": tt.computerID, "interfaceID": tt.interfaceID})
+			rec := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockComputerMgmtService(ctrl)
+			tt.mockBehavior(mockService)
+
+			handler := New(mockService)
+			handler.UpdateNetworkInterface(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, res.StatusCode)
+
+			if tt.expectedResponseBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tt.expectedResponseBody, string(body))
+			}
+		})
+	}
+}
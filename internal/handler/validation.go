@@ -0,0 +1,383 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"uhuaha/computers-management/internal/model"
+	"unicode/utf8"
+)
+
+// maxAttributeLength limits the length of the free-text hardware and lifecycle attributes.
+const maxAttributeLength = 255
+
+// isValidIPAddress reports whether s is an IPv4 or IPv6 address.
+func isValidIPAddress(s string) bool {
+	return net.ParseIP(s) != nil
+}
+
+// isValidMACAddress reports whether s is a 48-bit MAC address.
+func isValidMACAddress(s string) bool {
+	mac, err := net.ParseMAC(s)
+	return err == nil && len(mac) == 6
+}
+
+// validateSubnet checks the data of a subnet and returns a message describing the first problem found,
+// or an empty string if the subnet is valid. Only IPv4 subnets are supported.
+func validateSubnet(name, cidr string, gateway *string, vlan *int, ranges []IPRangeDTO) string {
+	if name == "" {
+		return "Missing subnet name"
+	}
+
+	ip, network, err := net.ParseCIDR(cidr)
+	if err != nil || ip.To4() == nil || !ip.Equal(network.IP) {
+		return "Invalid CIDR: it must be an IPv4 network address such as 10.0.0.0/24"
+	}
+
+	if gateway != nil {
+		gatewayIP := net.ParseIP(*gateway)
+		if gatewayIP == nil || !network.Contains(gatewayIP) {
+			return "Invalid gateway: it must be an IP address within the subnet"
+		}
+	}
+
+	if vlan != nil && (*vlan < 1 || *vlan > 4094) {
+		return "Invalid VLAN: it must be between 1 and 4094"
+	}
+
+	for _, r := range ranges {
+		start := net.ParseIP(r.Start).To4()
+		end := net.ParseIP(r.End).To4()
+
+		if start == nil || end == nil || !network.Contains(start) || !network.Contains(end) || bytes.Compare(start, end) > 0 {
+			return "Invalid reserved range: start and end must be IP addresses within the subnet with start <= end"
+		}
+	}
+
+	return ""
+}
+
+// validateLifecycleAttributes checks the hardware and lifecycle attributes of a computer and returns a message
+// describing the first problem found, or an empty string if they are valid. now is used to reject purchase
+// dates in the future.
+func validateLifecycleAttributes(attrs LifecycleAttributes, now time.Time) string {
+	texts := []struct {
+		name  string
+		value *string
+	}{
+		{"serial number", attrs.SerialNumber},
+		{"vendor", attrs.Vendor},
+		{"model", attrs.Model},
+		{"operating system", attrs.OperatingSystem},
+		{"location", attrs.Location},
+	}
+
+	for _, text := range texts {
+		if text.value != nil && (strings.TrimSpace(*text.value) == "" || len(*text.value) > maxAttributeLength) {
+			return fmt.Sprintf("Invalid %s: it must be a non-empty string of at most %d characters", text.name, maxAttributeLength)
+		}
+	}
+
+	if attrs.PurchaseDate != nil && attrs.PurchaseDate.After(now) {
+		return "Invalid purchase date: it must not be in the future"
+	}
+
+	if attrs.PurchaseDate != nil && attrs.WarrantyEnd != nil && attrs.WarrantyEnd.Before(attrs.PurchaseDate.Time) {
+		return "Invalid warranty end: it must not be before the purchase date"
+	}
+
+	return ""
+}
+
+// computerFilterParams lists the names of the attributes a list of computers can be filtered by.
+var computerFilterParams = []string{
+	"serial_number", "vendor", "model", "operating_system", "location",
+	"purchased_after", "purchased_before", "warranty_ends_after", "warranty_ends_before",
+}
+
+// parseComputerFilter builds a computer filter from the query parameters of a list request.
+// The returned error message is suitable for the client.
+func parseComputerFilter(query url.Values) (model.ComputerFilter, error) {
+	return parseFilterValues(query, "query parameter")
+}
+
+// parseBatchFilter builds a computer filter from the filter of a batch request. At least one supported
+// attribute has to be given. The returned error message is suitable for the client.
+func parseBatchFilter(attributes map[string]string) (model.ComputerFilter, error) {
+	values := url.Values{}
+
+	for name, value := range attributes {
+		if !slices.Contains(computerFilterParams, name) {
+			return model.ComputerFilter{}, errors.New("Invalid filter attribute '" + name + "': it is not supported")
+		}
+
+		values.Set(name, value)
+	}
+
+	filter, err := parseFilterValues(values, "filter attribute")
+	if err != nil {
+		return model.ComputerFilter{}, err
+	}
+
+	if filter == (model.ComputerFilter{}) {
+		return model.ComputerFilter{}, errors.New("Invalid filter: it must contain at least one non-empty attribute")
+	}
+
+	return filter, nil
+}
+
+// parseFilterValues builds a computer filter from the given values. kind names the values in error messages.
+func parseFilterValues(query url.Values, kind string) (model.ComputerFilter, error) {
+	filter := model.ComputerFilter{
+		SerialNumber:    queryString(query, "serial_number"),
+		Vendor:          queryString(query, "vendor"),
+		Model:           queryString(query, "model"),
+		OperatingSystem: queryString(query, "operating_system"),
+		Location:        queryString(query, "location"),
+	}
+
+	dates := []struct {
+		param string
+		dest  **time.Time
+	}{
+		{"purchased_after", &filter.PurchasedAfter},
+		{"purchased_before", &filter.PurchasedBefore},
+		{"warranty_ends_after", &filter.WarrantyEndsAfter},
+		{"warranty_ends_before", &filter.WarrantyEndsBefore},
+	}
+
+	for _, date := range dates {
+		value := query.Get(date.param)
+		if value == "" {
+			continue
+		}
+
+		t, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return model.ComputerFilter{}, errors.New("Invalid " + kind + " '" + date.param + "': it must be a date formatted as YYYY-MM-DD")
+		}
+
+		*date.dest = &t
+	}
+
+	return filter, nil
+}
+
+func queryString(query url.Values, param string) *string {
+	if value := query.Get(param); value != "" {
+		return &value
+	}
+
+	return nil
+}
+
+// validateStatusTransition checks a status transition request and returns a message describing the first problem found,
+// or an empty string if the request is valid.
+func validateStatusTransition(data AddStatusTransitionRequest) string {
+	if data.Status == string(model.StatusAssigned) {
+		return "Invalid status: computers are assigned via POST /computers/{computerID}/checkout"
+	}
+
+	if !model.ComputerStatus(data.Status).IsValid() {
+		return "Invalid status: it must be one of in_stock, in_repair, lost or retired"
+	}
+
+	if strings.TrimSpace(data.Reason) == "" {
+		return "Missing reason"
+	}
+
+	return validateActor(data.Actor)
+}
+
+// validateActor returns a message describing why actor is invalid, or an empty string if it is valid.
+func validateActor(actor string) string {
+	if strings.TrimSpace(actor) == "" || len(actor) > maxAttributeLength {
+		return fmt.Sprintf("Invalid actor: it must be a non-empty string of at most %d characters", maxAttributeLength)
+	}
+
+	return ""
+}
+
+// validateNetworkInterface checks a network interface request and returns a message describing the first problem found,
+// or an empty string if the request is valid.
+func validateNetworkInterface(data NetworkInterfaceRequest) string {
+	if strings.TrimSpace(data.Name) == "" || len(data.Name) > maxAttributeLength {
+		return fmt.Sprintf("Invalid interface name: it must be a non-empty string of at most %d characters", maxAttributeLength)
+	}
+
+	if !isValidMACAddress(data.MACAddress) {
+		return "Invalid MAC address"
+	}
+
+	if data.IPAddress != nil && !isValidIPAddress(*data.IPAddress) {
+		return "Invalid IP address"
+	}
+
+	if data.Primary && data.IPAddress == nil {
+		return "Missing IP address: the primary interface must have an IP address"
+	}
+
+	if data.Type != "" && !model.NetworkInterfaceType(data.Type).IsValid() {
+		return "Invalid interface type: it must be one of ethernet, wifi, virtual or other"
+	}
+
+	return ""
+}
+
+// parseBatchSelector builds the selector of a batch request, which must either contain IDs or a filter.
+// The returned error message is suitable for the client.
+func parseBatchSelector(data BatchSelector) (model.ComputerSelector, error) {
+	if (len(data.IDs) > 0) == (data.Filter != nil) {
+		return model.ComputerSelector{}, errors.New("Invalid selection: either 'ids' or 'filter' must be given")
+	}
+
+	if data.Filter != nil {
+		filter, err := parseBatchFilter(data.Filter)
+		if err != nil {
+			return model.ComputerSelector{}, err
+		}
+
+		return model.ComputerSelector{Filter: &filter}, nil
+	}
+
+	if len(data.IDs) > model.MaxBatchSize {
+		return model.ComputerSelector{}, fmt.Errorf("Invalid selection: at most %d IDs can be given", model.MaxBatchSize)
+	}
+
+	for _, id := range data.IDs {
+		if id <= 0 {
+			return model.ComputerSelector{}, errors.New("Invalid selection: IDs must be positive")
+		}
+	}
+
+	return model.ComputerSelector{IDs: data.IDs}, nil
+}
+
+// validateComputerChanges checks the changes of a batch update and returns a message describing the first problem
+// found, or an empty string if they are valid.
+func validateComputerChanges(changes ComputerChangesDTO) string {
+	if changes == (ComputerChangesDTO{}) {
+		return "Missing changes"
+	}
+
+	if changes.EmployeeAbbreviation != nil && utf8.RuneCountInString(*changes.EmployeeAbbreviation) != 3 {
+		return "Invalid employee abbreviation"
+	}
+
+	return validateLifecycleAttributes(LifecycleAttributes{
+		Vendor:          changes.Vendor,
+		Model:           changes.Model,
+		OperatingSystem: changes.OperatingSystem,
+		Location:        changes.Location,
+	}, time.Now())
+}
+
+// webhookEventTypes lists the event types a webhook subscription can subscribe to.
+var webhookEventTypes = []string{
+	string(model.ComputerCreated), string(model.ComputerUpdated), string(model.ComputerDeleted), string(model.ComputerAssigned),
+}
+
+// minWebhookSecretLength is the minimum length of a secret chosen by the client.
+const minWebhookSecretLength = 16
+
+// validateWebhookSubscription checks the data of a webhook subscription and returns a message describing the first
+// problem found, or an empty string if the subscription is valid.
+func validateWebhookSubscription(data WebhookSubscriptionRequest) string {
+	target, err := url.Parse(data.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return "Invalid URL: it must be an absolute http or https URL"
+	}
+
+	if len(data.EventTypes) == 0 {
+		return "Missing event types"
+	}
+
+	for _, eventType := range data.EventTypes {
+		if !slices.Contains(webhookEventTypes, eventType) {
+			return fmt.Sprintf("Invalid event type '%s': it must be one of %s", eventType, strings.Join(webhookEventTypes, ", "))
+		}
+	}
+
+	if data.Secret != "" && len(data.Secret) < minWebhookSecretLength {
+		return fmt.Sprintf("Invalid secret: it must be at least %d characters long", minWebhookSecretLength)
+	}
+
+	return ""
+}
+
+// parseEventFilter builds an event filter from the optional query parameters employee and computer_id of a
+// stream request. The returned error message is suitable for the client.
+func parseEventFilter(query url.Values) (model.ComputerEventFilter, error) {
+	var filter model.ComputerEventFilter
+
+	if employee := query.Get("employee"); employee != "" {
+		if utf8.RuneCountInString(employee) != 3 {
+			return model.ComputerEventFilter{}, errors.New("Invalid query parameter 'employee': it must be a 3-characters string")
+		}
+
+		filter.EmployeeAbbreviation = &employee
+	}
+
+	if value := query.Get("computer_id"); value != "" {
+		computerID, err := strconv.Atoi(value)
+		if err != nil || computerID <= 0 {
+			return model.ComputerEventFilter{}, errors.New("Invalid query parameter 'computer_id': it must be a positive integer")
+		}
+
+		filter.ComputerID = &computerID
+	}
+
+	return filter, nil
+}
+
+// parseLastEventID parses the optional Last-Event-ID header of a client resuming a stream. It returns 0 if the
+// header is missing. The returned error message is suitable for the client.
+func parseLastEventID(header string) (int64, error) {
+	if header == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(header, 10, 64)
+	if err != nil || id < 0 {
+		return 0, errors.New("Invalid header 'Last-Event-ID': it must be the ID of a received event")
+	}
+
+	return id, nil
+}
+
+// parseDryRun parses the optional dry_run query parameter. The returned error message is suitable for the client.
+func parseDryRun(query url.Values) (bool, error) {
+	value := query.Get("dry_run")
+	if value == "" {
+		return false, nil
+	}
+
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("Invalid query parameter 'dry_run': it must be true or false")
+	}
+
+	return dryRun, nil
+}
+
+// isValidLocale reports whether s is a language tag such as "en" or "de-CH", which names a directory of
+// notification templates.
+func isValidLocale(s string) bool {
+	if s == "" || len(s) > 35 {
+		return false
+	}
+
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"uhuaha/computers-management/internal/model"
+)
+
+type WarrantyMgmtService interface {
+	CheckWarranties() ([]model.Computer, error)
+}
+
+type WarrantyMgmtHandler struct {
+	warrantyMgmtService WarrantyMgmtService
+}
+
+func NewWarrantyHandler(service WarrantyMgmtService) *WarrantyMgmtHandler {
+	return &WarrantyMgmtHandler{
+		warrantyMgmtService: service,
+	}
+}
+
+// CheckWarranties triggers a warranty check outside of the schedule and returns the computers that are reported.
+func (h *WarrantyMgmtHandler) CheckWarranties(w http.ResponseWriter, r *http.Request) {
+	computers, err := h.warrantyMgmtService.CheckWarranties()
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to check warranties: %w", err))
+		return
+	}
+
+	response := WarrantyCheckResponse{
+		Notified:  len(computers),
+		Computers: convertComputerModelsToDTOs(computers).Computers,
+	}
+
+	writeResponse(w, r, http.StatusOK, jsonFormat, response)
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/bdlm/log"
+	"github.com/gorilla/mux"
+)
+
+type WebhookMgmtService interface {
+	AddWebhookSubscription(subscription model.WebhookSubscription) (model.WebhookSubscription, error)
+	GetWebhookSubscription(subscriptionID int) (model.WebhookSubscription, error)
+	GetAllWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	UpdateWebhookSubscription(subscriptionID int, data model.WebhookSubscription) error
+	DeleteWebhookSubscription(subscriptionID int) error
+	GetWebhookDeliveries(subscriptionID int) ([]model.WebhookDelivery, error)
+	TestWebhookSubscription(ctx context.Context, subscriptionID int) (model.WebhookDelivery, error)
+}
+
+type WebhookMgmtHandler struct {
+	webhookMgmtService WebhookMgmtService
+}
+
+func NewWebhookHandler(service WebhookMgmtService) *WebhookMgmtHandler {
+	return &WebhookMgmtHandler{
+		webhookMgmtService: service,
+	}
+}
+
+// AddWebhookSubscription adds the provided webhook subscription and returns its ID and secret.
+func (h *WebhookMgmtHandler) AddWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	var data WebhookSubscriptionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Error("failed to decode the request body: " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid request body"))
+		return
+	}
+
+	if msg := validateWebhookSubscription(data); msg != "" {
+		log.Error("failed to validate webhook subscription: " + msg)
+		handleError(w, r, errs.NewValidation(msg))
+		return
+	}
+
+	subscription, err := h.webhookMgmtService.AddWebhookSubscription(convertWebhookSubscriptionRequestToModel(data))
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to add webhook subscription: %w", err))
+		return
+	}
+
+	response := AddWebhookSubscriptionResponse{ID: subscription.ID, Secret: subscription.Secret}
+
+	writeResponse(w, r, http.StatusCreated, jsonFormat, response)
+}
+
+// GetWebhookSubscriptionByID gets a webhook subscription's data by its ID. The secret is not returned.
+func (h *WebhookMgmtHandler) GetWebhookSubscriptionByID(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateFormat(w, r, readFormats)
+	if !ok {
+		return
+	}
+
+	subscriptionID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.webhookMgmtService.GetWebhookSubscription(subscriptionID)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to get webhook subscription by ID: %w", err))
+		return
+	}
+
+	response := convertWebhookSubscriptionModelToDTO(subscription)
+
+	writeResponse(w, r, http.StatusOK, format, response)
+}
+
+// GetAllWebhookSubscriptions retrieves all webhook subscriptions from the storage.
+func (h *WebhookMgmtHandler) GetAllWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateFormat(w, r, readFormats)
+	if !ok {
+		return
+	}
+
+	subscriptions, err := h.webhookMgmtService.GetAllWebhookSubscriptions(r.Context())
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to get all webhook subscriptions: %w", err))
+		return
+	}
+
+	response := convertWebhookSubscriptionModelsToDTOs(subscriptions)
+
+	writeResponse(w, r, http.StatusOK, format, response)
+}
+
+// UpdateWebhookSubscription updates a webhook subscription's data.
+func (h *WebhookMgmtHandler) UpdateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	var data WebhookSubscriptionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Error("failed to decode the request body: " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid request body"))
+		return
+	}
+
+	if msg := validateWebhookSubscription(data); msg != "" {
+		log.Error("failed to validate webhook subscription: " + msg)
+		handleError(w, r, errs.NewValidation(msg))
+		return
+	}
+
+	if err := h.webhookMgmtService.UpdateWebhookSubscription(subscriptionID, convertWebhookSubscriptionRequestToModel(data)); err != nil {
+		handleError(w, r, fmt.Errorf("failed to update webhook subscription: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteWebhookSubscription deletes a webhook subscription by its ID.
+func (h *WebhookMgmtHandler) DeleteWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookMgmtService.DeleteWebhookSubscription(subscriptionID); err != nil {
+		handleError(w, r, fmt.Errorf("failed to delete webhook subscription: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries retrieves the most recent delivery attempts of a webhook subscription, newest first.
+func (h *WebhookMgmtHandler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateFormat(w, r, readFormats)
+	if !ok {
+		return
+	}
+
+	subscriptionID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.webhookMgmtService.GetWebhookDeliveries(subscriptionID)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to get webhook deliveries: %w", err))
+		return
+	}
+
+	response := convertWebhookDeliveryModelsToDTOs(deliveries)
+
+	writeResponse(w, r, http.StatusOK, format, response)
+}
+
+// TestWebhookSubscription sends a test event to a webhook subscription and returns the delivery. A failed
+// delivery is reported within the response, not as an error.
+func (h *WebhookMgmtHandler) TestWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookMgmtService.TestWebhookSubscription(r.Context(), subscriptionID)
+	if err != nil {
+		handleError(w, r, fmt.Errorf("failed to test webhook subscription: %w", err))
+		return
+	}
+
+	response := convertWebhookDeliveryModelToDTO(delivery)
+
+	writeResponse(w, r, http.StatusOK, jsonFormat, response)
+}
+
+// parseWebhookID parses the URL parameter 'webhookID'. It writes an error response and returns false if it is invalid.
+func parseWebhookID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	subscriptionID, err := strconv.Atoi(mux.Vars(r)["webhookID"])
+	if err != nil {
+		log.Error("failed to parse URL parameter 'webhookID': " + err.Error())
+		handleError(w, r, errs.NewValidation("Invalid URL parameter 'webhookID'"))
+		return 0, false
+	}
+
+	return subscriptionID, true
+}
@@ -8,18 +8,26 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
-	"path/filepath"
 	"strconv"
 	"sync"
 	"testing"
+	"time"
+	"uhuaha/computers-management/internal/consistency"
+	"uhuaha/computers-management/internal/events"
 	"uhuaha/computers-management/internal/handler"
+	"uhuaha/computers-management/internal/model"
+	"uhuaha/computers-management/internal/notify"
 	"uhuaha/computers-management/internal/service"
+	"uhuaha/computers-management/templates/notifications"
 
 	"github.com/gorilla/mux"
 	_ "github.com/lib/pq"
@@ -29,24 +37,23 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
 
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-
+	internal_db "uhuaha/computers-management/internal/db"
 	internal_postgres "uhuaha/computers-management/internal/db/postgres"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
 )
 
 const (
-	testDBName          = "testdb"
-	testDBUser          = "testuser"
-	testDBPassword      = "testpw"
-	testDBHost          = "localhost"
-	defaultPostgresPort = "5432"
+	testDBName     = "testdb"
+	testDBUser     = "testuser"
+	testDBPassword = "testpw"
 )
 
 var (
 	db                  *sql.DB
+	dbConnectionString  string
 	h                   *handler.ComputerMgmtHandler
+	sh                  *handler.SubnetMgmtHandler
+	notifier            *service.Notifier
 	wg                  sync.WaitGroup
 	notificationPayload []byte
 )
@@ -71,10 +78,7 @@ func TestMain(m *testing.M) {
 		log.Fatalf("failed to get connection string: %v", err)
 	}
 
-	externalPort, err := testContainer.MappedPort(ctx, defaultPostgresPort)
-	if err != nil {
-		log.Fatal("failed to get externally mapped port: ", err)
-	}
+	dbConnectionString = connectionString
 
 	// Create connection to DB
 	db, err = sql.Open("postgres", connectionString)
@@ -84,24 +88,8 @@ func TestMain(m *testing.M) {
 
 	defer db.Close()
 
-	// Exectute migrations
-	workingDirectory, err := os.Getwd()
-	if err != nil {
-		log.Fatalf("failed to get working directory: %v", err)
-	}
-
-	databaseURL := "postgres://" + testDBUser + ":" + testDBPassword + "@" +
-		testDBHost + ":" + externalPort.Port() + "/" + testDBName + "?sslmode=disable"
-
-	migrationDriver, err := migrate.New(
-		"file://"+filepath.Join(workingDirectory, "../../migrations"), // Path to migration files
-		databaseURL,
-	)
-	if err != nil {
-		log.Fatalf("failed to init migrations: %v", err)
-	}
-
-	if err := migrationDriver.Up(); err != nil && err != migrate.ErrNoChange {
+	// Execute the embedded migrations
+	if err := internal_db.RunMigrations(db); err != nil {
 		log.Fatalf("failed to run migrations: %v", err)
 	}
 
@@ -118,9 +106,18 @@ func TestMain(m *testing.M) {
 
 	// Create repository, services and API handler
 	repository := internal_postgres.NewRepository(db)
-	notifier := service.NewNotifier(notifyServer.URL)
+	defer repository.Close()
+
+	templates, err := notify.LoadTemplates(notifications.FS)
+	if err != nil {
+		log.Fatalf("failed to load notification templates: %v", err)
+	}
+
+	notifier = service.NewNotifier(notify.NewHTTPChannel(notifyServer.URL), templates, notify.DefaultLocale)
+	subnetMgmtService := service.NewSubnetMgmtService(repository, 0)
 	computerMgmtService := service.NewComputerMgmtService(repository, notifier)
 	h = handler.New(computerMgmtService)
+	sh = handler.NewSubnetHandler(subnetMgmtService)
 
 	// Run all tests
 	exitCode := m.Run()
@@ -139,19 +136,19 @@ func TestAddAndGetComputersIntegration(t *testing.T) {
 	computersToBeAdded := []map[string]any{
 		{
 			"name":        "TestPC-01",
-			"ip_address":  "192.168.1.100",
+			"ip_address":  "192.168.1.101",
 			"mac_address": "AA:BB:CC:DD:EE:F1",
 		},
 		{
 			"name":                  "TestPC-02",
-			"ip_address":            "192.168.1.100",
+			"ip_address":            "192.168.1.102",
 			"mac_address":           "AA:BB:CC:DD:EE:F2",
 			"employee_abbreviation": "EMP",
 			"description":           "Test computer #1 for employee EMP",
 		},
 		{
 			"name":                  "TestPC-03",
-			"ip_address":            "192.168.1.100",
+			"ip_address":            "192.168.1.103",
 			"mac_address":           "AA:BB:CC:DD:EE:F3",
 			"employee_abbreviation": "EMP",
 			"description":           "Test computer #2 for employee EMP",
@@ -241,19 +238,19 @@ func TestAddAndUpdateComputersIntegration(t *testing.T) {
 	computersToBeAdded := []map[string]any{
 		{
 			"name":        "TestPC-01",
-			"ip_address":  "192.168.1.100",
+			"ip_address":  "192.168.1.101",
 			"mac_address": "AA:BB:CC:DD:EE:F1",
 		},
 		{
 			"name":                  "TestPC-02",
-			"ip_address":            "192.168.1.100",
+			"ip_address":            "192.168.1.102",
 			"mac_address":           "AA:BB:CC:DD:EE:F2",
 			"employee_abbreviation": "EMP",
 			"description":           "Test computer #1 for employee EMP",
 		},
 		{
 			"name":                  "TestPC-03",
-			"ip_address":            "192.168.1.100",
+			"ip_address":            "192.168.1.103",
 			"mac_address":           "AA:BB:CC:DD:EE:F3",
 			"employee_abbreviation": "EMP",
 			"description":           "Test computer #2 for employee EMP",
@@ -292,7 +289,7 @@ func TestAddAndUpdateComputersIntegration(t *testing.T) {
 		updateRequest := map[string]any{
 			"name":                  "UpdatedPC-01",
 			"ip_address":            "192.168.1.200",
-			"mac_address":           "AA:BB:CC:DD:EE:GG",
+			"mac_address":           "AA:BB:CC:DD:EE:FF",
 			"employee_abbreviation": "STR",
 			"description":           "Updated description for TestPC-01",
 		}
@@ -321,8 +318,9 @@ func TestAddAndUpdateComputersIntegration(t *testing.T) {
 
 		assert.Equal(t, "UpdatedPC-01", updatedComputer.Name)
 		assert.Equal(t, "192.168.1.200", updatedComputer.IPAddress)
-		assert.Equal(t, "AA:BB:CC:DD:EE:GG", updatedComputer.MACAddress)
+		assert.Equal(t, "AA:BB:CC:DD:EE:FF", updatedComputer.MACAddress)
 		assert.Equal(t, "STR", *updatedComputer.EmployeeAbbreviation)
+		assert.Equal(t, "assigned", updatedComputer.Status)
 		assert.Equal(t, "Updated description for TestPC-01", *updatedComputer.Description)
 	})
 }
@@ -333,19 +331,19 @@ func TestNotificationIntegration(t *testing.T) {
 	computersToBeAdded := []map[string]any{
 		{
 			"name":        "TestPC-01",
-			"ip_address":  "192.168.1.100",
+			"ip_address":  "192.168.1.101",
 			"mac_address": "AA:BB:CC:DD:EE:F1",
 		},
 		{
 			"name":                  "TestPC-02",
-			"ip_address":            "192.168.1.100",
+			"ip_address":            "192.168.1.102",
 			"mac_address":           "AA:BB:CC:DD:EE:F2",
 			"employee_abbreviation": "EMP",
 			"description":           "Test computer #1 for employee EMP",
 		},
 		{
 			"name":                  "TestPC-03",
-			"ip_address":            "192.168.1.100",
+			"ip_address":            "192.168.1.103",
 			"mac_address":           "AA:BB:CC:DD:EE:F3",
 			"employee_abbreviation": "EMP",
 			"description":           "Test computer #2 for employee EMP",
@@ -367,7 +365,7 @@ func TestNotificationIntegration(t *testing.T) {
 		// Add a third computer for the same employee
 		data := map[string]any{
 			"name":                  "TestPC-04",
-			"ip_address":            "192.168.1.100",
+			"ip_address":            "192.168.1.104",
 			"mac_address":           "AA:BB:CC:DD:EE:F4",
 			"employee_abbreviation": "EMP",
 			"description":           "Test computer #3 for employee EMP",
@@ -387,13 +385,13 @@ func TestNotificationIntegration(t *testing.T) {
 
 		require.NotEmpty(t, notificationPayload, "Expected a notification to be sent")
 
-		var sentMessage service.NotificationPayload
+		var sentMessage notify.HTTPPayload
 
 		err = json.Unmarshal(notificationPayload, &sentMessage)
 		require.NoError(t, err)
 
 		assert.Equal(t, "warning", sentMessage.Level)
-		assert.Equal(t, "There are 3 or more computers assigned to the same employee.", sentMessage.Message)
+		assert.Contains(t, sentMessage.Message, "There are 3 computers assigned to the employee EMP, which reaches the threshold of 3")
 		assert.Equal(t, "EMP", sentMessage.EmployeeAbbreviation)
 	})
 
@@ -415,13 +413,25 @@ func TestNotificationIntegration(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, computers.Computers, 3)
 
-		// Delete the first computer
+		// Delete the first computer, which resolves the warning
+		wg.Add(1)
+
 		computerID := computers.Computers[0].ID
 		resp = deleteComputer(computerID)
 		defer resp.Body.Close()
 
 		require.Equal(t, http.StatusNoContent, resp.StatusCode)
 
+		wg.Wait()
+
+		var sentMessage notify.HTTPPayload
+
+		err = json.Unmarshal(notificationPayload, &sentMessage)
+		require.NoError(t, err)
+
+		assert.Equal(t, "resolved", sentMessage.Level)
+		assert.Equal(t, "EMP", sentMessage.EmployeeAbbreviation)
+
 		// Verify that there is one computer less in the DB for the same employee
 		resp = getComputersByEmployee(employee)
 		defer resp.Body.Close()
@@ -467,6 +477,9 @@ func TestNotificationIntegration(t *testing.T) {
 
 		// Updating a computer should trigger sending a notification
 		updateReq := map[string]any{
+			"name":                  computersWithoutEmployee[0].Name,
+			"ip_address":            computersWithoutEmployee[0].IPAddress,
+			"mac_address":           computersWithoutEmployee[0].MACAddress,
 			"description":           "Updated description for TestPC",
 			"employee_abbreviation": "EMP",
 		}
@@ -485,20 +498,1708 @@ func TestNotificationIntegration(t *testing.T) {
 
 		require.NotEmpty(t, notificationPayload, "Expected a notification to be sent")
 
-		var sentMessage service.NotificationPayload
+		var sentMessage notify.HTTPPayload
 
 		err = json.Unmarshal(notificationPayload, &sentMessage)
 		require.NoError(t, err)
 
 		assert.Equal(t, "warning", sentMessage.Level)
-		assert.Equal(t, "There are 3 or more computers assigned to the same employee.", sentMessage.Message)
+		assert.Contains(t, sentMessage.Message, "There are 3 computers assigned to the employee EMP, which reaches the threshold of 3")
 		assert.Equal(t, "EMP", sentMessage.EmployeeAbbreviation)
 	})
 }
 
-// truncateTable clears the computers table and resets the identity column.
+func TestNetworkAddressColumnsIntegration(t *testing.T) {
+	defer truncateTable()
+
+	t.Run("MAC addresses are returned in canonical form", func(t *testing.T) {
+		resp, err := addComputer(map[string]any{
+			"name":        "TestPC-01",
+			"ip_address":  "10.0.0.1",
+			"mac_address": "aa-bb-cc-dd-ee-01",
+		})
+		require.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var added handler.AddComputerResponse
+		err = json.NewDecoder(resp.Body).Decode(&added)
+		require.NoError(t, err)
+
+		resp = getComputerByID(added.ID)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var computer handler.GetComputerByIDResponse
+		err = json.NewDecoder(resp.Body).Decode(&computer)
+		require.NoError(t, err)
+
+		assert.Equal(t, "10.0.0.1", computer.IPAddress)
+		assert.Equal(t, "AA:BB:CC:DD:EE:01", computer.MACAddress)
+	})
+
+	t.Run("Employee abbreviations of invalid length are rejected by the database", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO computers (name, employee_abbreviation) VALUES ('TestPC-02', 'TOOLONG')`)
+		require.Error(t, err)
+	})
+}
+
+func TestIPConflictIntegration(t *testing.T) {
+	defer truncateTable()
+
+	t.Run("Adding a computer with an IP address already in use sends a notification", func(t *testing.T) {
+		resp, err := addComputer(map[string]any{
+			"name":        "TestPC-01",
+			"ip_address":  "10.0.0.1",
+			"mac_address": "AA:BB:CC:DD:EE:01",
+		})
+		require.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		wg.Add(1)
+
+		resp, err = addComputer(map[string]any{
+			"name":        "TestPC-02",
+			"ip_address":  "10.0.0.1",
+			"mac_address": "AA:BB:CC:DD:EE:02",
+		})
+		require.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		// Wait for the notification to be sent and verify the payload.
+		wg.Wait()
+
+		var sentMessage notify.HTTPPayload
+
+		err = json.Unmarshal(notificationPayload, &sentMessage)
+		require.NoError(t, err)
+
+		assert.Equal(t, "warning", sentMessage.Level)
+		assert.Equal(t, "The IP address 10.0.0.1 is used by more than one computer: TestPC-01, TestPC-02.", sentMessage.Message)
+	})
+
+	t.Run("Get IP conflicts lists the duplicate IP address and the computers involved", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/computers/conflicts", nil)
+		rec := httptest.NewRecorder()
+		h.GetIPConflicts(rec, req)
+
+		resp := rec.Result()
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var conflicts handler.GetIPConflictsResponse
+		err := json.NewDecoder(resp.Body).Decode(&conflicts)
+		require.NoError(t, err)
+
+		require.Len(t, conflicts.Conflicts, 1)
+		assert.Equal(t, "10.0.0.1", conflicts.Conflicts[0].IPAddress)
+		assert.Len(t, conflicts.Conflicts[0].Computers, 2)
+	})
+
+	t.Run("Enforcing unique IP addresses rejects a duplicate with 409", func(t *testing.T) {
+		_, scope, err := net.ParseCIDR("10.0.0.0/24")
+		require.NoError(t, err)
+
+		enforcingService := service.NewComputerMgmtService(internal_postgres.NewRepository(db), notifier,
+			service.WithIPConflictPolicy(service.IPConflictPolicy{
+				Mode:   service.IPConflictModeEnforce,
+				Scopes: []*net.IPNet{scope},
+			}),
+		)
+		enforcingHandler := handler.New(enforcingService)
+
+		jsonBody, err := json.Marshal(map[string]any{
+			"name":        "TestPC-03",
+			"ip_address":  "10.0.0.1",
+			"mac_address": "AA:BB:CC:DD:EE:03",
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/computers", bytes.NewReader(jsonBody))
+		rec := httptest.NewRecorder()
+		enforcingHandler.AddComputer(rec, req)
+
+		resp := rec.Result()
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Enforcing unique IP addresses accepts only one of concurrent computers with the same address", func(t *testing.T) {
+		enforcingService := service.NewComputerMgmtService(internal_postgres.NewRepository(db), notifier,
+			service.WithIPConflictPolicy(service.IPConflictPolicy{Mode: service.IPConflictModeEnforce}),
+		)
+		enforcingHandler := handler.New(enforcingService)
+
+		const concurrentRequests = 5
+
+		statusCodes := make(chan int, concurrentRequests)
+
+		var requests sync.WaitGroup
+
+		for i := range concurrentRequests {
+			requests.Add(1)
+
+			go func() {
+				defer requests.Done()
+
+				jsonBody, _ := json.Marshal(map[string]any{
+					"name":        fmt.Sprintf("TestPC-1%d", i),
+					"ip_address":  "10.0.0.50",
+					"mac_address": fmt.Sprintf("AA:BB:CC:DD:EE:1%d", i),
+				})
+
+				req := httptest.NewRequest(http.MethodPost, "/computers", bytes.NewReader(jsonBody))
+				rec := httptest.NewRecorder()
+				enforcingHandler.AddComputer(rec, req)
+
+				statusCodes <- rec.Code
+			}()
+		}
+
+		requests.Wait()
+		close(statusCodes)
+
+		created := 0
+		for statusCode := range statusCodes {
+			if statusCode == http.StatusCreated {
+				created++
+			} else {
+				assert.Equal(t, http.StatusConflict, statusCode)
+			}
+		}
+
+		assert.Equal(t, 1, created)
+	})
+}
+
+func TestSubnetIntegration(t *testing.T) {
+	defer truncateTable()
+
+	var subnetID, computerID int
+
+	t.Run("Add a subnet returns 201", func(t *testing.T) {
+		jsonBody, err := json.Marshal(map[string]any{
+			"name":            "Office",
+			"cidr":            "10.1.0.0/29",
+			"gateway":         "10.1.0.1",
+			"reserved_ranges": []map[string]any{{"start": "10.1.0.2", "end": "10.1.0.3"}},
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/subnets", bytes.NewReader(jsonBody))
+		rec := httptest.NewRecorder()
+		sh.AddSubnet(rec, req)
+
+		resp := rec.Result()
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var added handler.AddSubnetResponse
+		err = json.NewDecoder(resp.Body).Decode(&added)
+		require.NoError(t, err)
+
+		subnetID = added.ID
+	})
+
+	t.Run("Adding an overlapping subnet returns 409", func(t *testing.T) {
+		jsonBody, err := json.Marshal(map[string]any{"name": "Overlap", "cidr": "10.1.0.0/24"})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/subnets", bytes.NewReader(jsonBody))
+		rec := httptest.NewRecorder()
+		sh.AddSubnet(rec, req)
+
+		resp := rec.Result()
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Adding a computer with automatic IP address gets the next free address", func(t *testing.T) {
+		resp, err := addComputer(map[string]any{
+			"name":        "TestPC-01",
+			"ip_address":  "auto",
+			"mac_address": "AA:BB:CC:DD:EE:01",
+			"subnet_id":   subnetID,
+		})
+		require.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var added handler.AddComputerResponse
+		err = json.NewDecoder(resp.Body).Decode(&added)
+		require.NoError(t, err)
+
+		resp = getComputerByID(added.ID)
+		defer resp.Body.Close()
+
+		var computer handler.GetComputerByIDResponse
+		err = json.NewDecoder(resp.Body).Decode(&computer)
+		require.NoError(t, err)
+
+		assert.Equal(t, "10.1.0.4", computer.IPAddress)
+		require.NotNil(t, computer.SubnetID)
+		assert.Equal(t, subnetID, *computer.SubnetID)
+
+		computerID = added.ID
+	})
+
+	t.Run("Allocating an IP address skips allocated and used addresses", func(t *testing.T) {
+		targetSubnetID := strconv.Itoa(subnetID)
+		req := httptest.NewRequest(http.MethodPost, "/subnets/"+targetSubnetID+"/allocate", nil)
+		req = mux.SetURLVars(req, map[string]string{"subnetID": targetSubnetID})
+		rec := httptest.NewRecorder()
+		sh.AllocateIPAddress(rec, req)
+
+		resp := rec.Result()
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var allocated handler.AllocateIPAddressResponse
+		err := json.NewDecoder(resp.Body).Decode(&allocated)
+		require.NoError(t, err)
+
+		assert.Equal(t, "10.1.0.5", allocated.IPAddress)
+	})
+
+	t.Run("Get subnet utilization counts every host address once", func(t *testing.T) {
+		targetSubnetID := strconv.Itoa(subnetID)
+		req := httptest.NewRequest(http.MethodGet, "/subnets/"+targetSubnetID+"/utilization", nil)
+		req = mux.SetURLVars(req, map[string]string{"subnetID": targetSubnetID})
+		rec := httptest.NewRecorder()
+		sh.GetSubnetUtilization(rec, req)
+
+		resp := rec.Result()
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var utilization handler.GetSubnetUtilizationResponse
+		err := json.NewDecoder(resp.Body).Decode(&utilization)
+		require.NoError(t, err)
+
+		assert.Equal(t, 6, utilization.Total)
+		assert.Equal(t, 1, utilization.Used)
+		assert.Equal(t, 1, utilization.Allocated)
+		assert.Equal(t, 3, utilization.Reserved)
+		assert.Equal(t, 1, utilization.Free)
+	})
+
+	t.Run("Using and deleting computers releases their addresses", func(t *testing.T) {
+		resp, err := addComputer(map[string]any{
+			"name":        "TestPC-02",
+			"ip_address":  "10.1.0.5",
+			"mac_address": "AA:BB:CC:DD:EE:02",
+		})
+		require.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var added handler.AddComputerResponse
+		err = json.NewDecoder(resp.Body).Decode(&added)
+		require.NoError(t, err)
+
+		utilization := getSubnetUtilizationResponse(t, subnetID)
+		assert.Equal(t, 2, utilization.Used)
+		assert.Equal(t, 0, utilization.Allocated, "the allocation is released once the address is used")
+
+		for _, id := range []int{computerID, added.ID} {
+			resp := deleteComputer(id)
+			defer resp.Body.Close()
+
+			require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		}
+
+		utilization = getSubnetUtilizationResponse(t, subnetID)
+		assert.Equal(t, 0, utilization.Used)
+		assert.Equal(t, 0, utilization.Allocated)
+		assert.Equal(t, 3, utilization.Free)
+	})
+
+	t.Run("Releasing an allocated IP address frees it", func(t *testing.T) {
+		targetSubnetID := strconv.Itoa(subnetID)
+		req := httptest.NewRequest(http.MethodPost, "/subnets/"+targetSubnetID+"/allocate", nil)
+		req = mux.SetURLVars(req, map[string]string{"subnetID": targetSubnetID})
+		rec := httptest.NewRecorder()
+		sh.AllocateIPAddress(rec, req)
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		var allocated handler.AllocateIPAddressResponse
+		err := json.NewDecoder(rec.Body).Decode(&allocated)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, getSubnetUtilizationResponse(t, subnetID).Allocated)
+
+		releaseIPAddress := func() int {
+			req := httptest.NewRequest(http.MethodDelete, "/subnets/"+targetSubnetID+"/allocations/"+allocated.IPAddress, nil)
+			req = mux.SetURLVars(req, map[string]string{"subnetID": targetSubnetID, "ipAddress": allocated.IPAddress})
+			rec := httptest.NewRecorder()
+			sh.ReleaseIPAddress(rec, req)
+
+			return rec.Code
+		}
+
+		assert.Equal(t, http.StatusNoContent, releaseIPAddress())
+		assert.Equal(t, 0, getSubnetUtilizationResponse(t, subnetID).Allocated)
+		assert.Equal(t, http.StatusNotFound, releaseIPAddress())
+	})
+
+	t.Run("Allocations that have not been used expire", func(t *testing.T) {
+		repository := internal_postgres.NewRepository(db)
+		require.NoError(t, repository.AddIPAllocation(subnetID, "10.1.0.4"))
+
+		released, err := repository.DeleteIPAllocationsBefore(time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, released, "recent allocations are kept")
+
+		released, err = repository.DeleteIPAllocationsBefore(time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 1, released)
+		assert.Equal(t, 0, getSubnetUtilizationResponse(t, subnetID).Allocated)
+	})
+}
+
+func getSubnetUtilizationResponse(t *testing.T, subnetID int) handler.GetSubnetUtilizationResponse {
+	targetSubnetID := strconv.Itoa(subnetID)
+	req := httptest.NewRequest(http.MethodGet, "/subnets/"+targetSubnetID+"/utilization", nil)
+	req = mux.SetURLVars(req, map[string]string{"subnetID": targetSubnetID})
+	rec := httptest.NewRecorder()
+	sh.GetSubnetUtilization(rec, req)
+
+	resp := rec.Result()
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var utilization handler.GetSubnetUtilizationResponse
+	err := json.NewDecoder(resp.Body).Decode(&utilization)
+	require.NoError(t, err)
+
+	return utilization
+}
+
+func TestSearchComputersIntegration(t *testing.T) {
+	defer truncateTable()
+
+	computersToBeAdded := []map[string]any{
+		{
+			"name":        "DevPC-01",
+			"ip_address":  "192.168.1.101",
+			"mac_address": "AA:BB:CC:DD:EE:F1",
+			"description": "Build machine of the platform team",
+		},
+		{
+			"name":                  "Laptop-02",
+			"ip_address":            "192.168.1.102",
+			"mac_address":           "AA:BB:CC:DD:EE:F2",
+			"employee_abbreviation": "EMP",
+		},
+	}
+
+	for _, computer := range computersToBeAdded {
+		resp, err := addComputer(computer)
+		require.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedNames []string
+	}{
+		{name: "part of a hostname", query: "devpc", expectedNames: []string{"DevPC-01"}},
+		{name: "word of the description", query: "platform", expectedNames: []string{"DevPC-01"}},
+		{name: "employee abbreviation", query: "emp", expectedNames: []string{"Laptop-02"}},
+		{name: "part of a MAC address", query: "ee:f2", expectedNames: []string{"Laptop-02"}},
+		{name: "misspelled hostname", query: "Laptpo-02", expectedNames: []string{"Laptop-02"}},
+		{name: "no match", query: "printer", expectedNames: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run("Search by "+tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/computers/search?q="+url.QueryEscape(tt.query), nil)
+			rec := httptest.NewRecorder()
+			h.SearchComputers(rec, req)
+
+			resp := rec.Result()
+			defer resp.Body.Close()
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var results handler.SearchComputersResponse
+			err := json.NewDecoder(resp.Body).Decode(&results)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(results.Results))
+			for _, result := range results.Results {
+				names = append(names, result.Computer.Name)
+			}
+
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func TestLifecycleAttributesIntegration(t *testing.T) {
+	defer truncateTable()
+
+	computersToBeAdded := []map[string]any{
+		{
+			"name":             "LabPC-01",
+			"ip_address":       "192.168.1.101",
+			"mac_address":      "AA:BB:CC:DD:EE:F1",
+			"serial_number":    "SN-001",
+			"purchase_date":    "2022-03-01",
+			"warranty_end":     "2025-03-01",
+			"vendor":           "Lenovo",
+			"model":            "ThinkCentre M70q",
+			"operating_system": "Ubuntu 24.04",
+			"location":         "Berlin",
+		},
+		{
+			"name":         "LabPC-02",
+			"ip_address":   "192.168.1.102",
+			"mac_address":  "AA:BB:CC:DD:EE:F2",
+			"vendor":       "Dell",
+			"warranty_end": "2028-06-30",
+			"location":     "Hamburg",
+		},
+	}
+
+	for _, computer := range computersToBeAdded {
+		resp, err := addComputer(computer)
+		require.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	t.Run("Lifecycle attributes are stored and returned", func(t *testing.T) {
+		resp := getComputerByID(1)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{
+			"id": 1,
+			"name": "LabPC-01",
+			"ip_address": "192.168.1.101",
+			"mac_address": "AA:BB:CC:DD:EE:F1",
+			"status": "in_stock",
+			"serial_number": "SN-001",
+			"purchase_date": "2022-03-01",
+			"warranty_end": "2025-03-01",
+			"vendor": "Lenovo",
+			"model": "ThinkCentre M70q",
+			"operating_system": "Ubuntu 24.04",
+			"location": "Berlin"
+		}`, string(body))
+	})
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedNames []string
+	}{
+		{name: "vendor", query: "vendor=dell", expectedNames: []string{"LabPC-02"}},
+		{name: "location", query: "location=BERLIN", expectedNames: []string{"LabPC-01"}},
+		{name: "warranty end", query: "warranty_ends_before=2025-12-31", expectedNames: []string{"LabPC-01"}},
+		{name: "purchase date", query: "purchased_after=2022-03-01&purchased_before=2022-03-01", expectedNames: []string{"LabPC-01"}},
+		{name: "vendor and location", query: "vendor=Dell&location=Berlin", expectedNames: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run("Filter by "+tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/computers?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			h.GetAllComputers(rec, req)
+
+			resp := rec.Result()
+			defer resp.Body.Close()
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var computers handler.GetComputersResponse
+			err := json.NewDecoder(resp.Body).Decode(&computers)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(computers.Computers))
+			for _, computer := range computers.Computers {
+				names = append(names, computer.Name)
+			}
+
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func TestStatusTransitionsIntegration(t *testing.T) {
+	defer truncateTable()
+
+	resp, err := addComputer(map[string]any{
+		"name":                  "TestPC-01",
+		"ip_address":            "192.168.1.101",
+		"mac_address":           "AA:BB:CC:DD:EE:F1",
+		"employee_abbreviation": "EMP",
+	})
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	t.Run("A computer added with an employee is assigned", func(t *testing.T) {
+		computer := getComputerResponse(t, 1)
+		assert.Equal(t, "assigned", computer.Status)
+	})
+
+	t.Run("A transition to assigned returns 400", func(t *testing.T) {
+		resp := transitionComputerStatus(1, map[string]any{"status": "assigned", "reason": "Test", "actor": "ADM"})
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Allowed transitions change the status and are recorded", func(t *testing.T) {
+		for _, status := range []string{"in_repair", "in_stock"} {
+			resp := transitionComputerStatus(1, map[string]any{"status": status, "reason": "Test", "actor": "ADM"})
+			defer resp.Body.Close()
+
+			require.Equal(t, http.StatusCreated, resp.StatusCode)
+		}
+
+		// Leaving assigned takes the computer back from its employee.
+		computer := getComputerResponse(t, 1)
+		assert.Equal(t, "in_stock", computer.Status)
+		assert.Nil(t, computer.EmployeeAbbreviation)
+
+		assignments := getAssignmentsResponse(t, "EMP")
+		require.Len(t, assignments.Assignments, 1)
+		assert.NotNil(t, assignments.Assignments[0].EndedAt)
+
+		req := httptest.NewRequest(http.MethodGet, "/computers/1/transitions", nil)
+		req = mux.SetURLVars(req, map[string]string{"computerID": "1"})
+		rec := httptest.NewRecorder()
+		h.GetStatusTransitions(rec, req)
+
+		resp := rec.Result()
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var history handler.GetStatusTransitionsResponse
+		err := json.NewDecoder(resp.Body).Decode(&history)
+		require.NoError(t, err)
+
+		require.Len(t, history.Transitions, 2)
+		assert.Equal(t, "assigned", history.Transitions[0].FromStatus)
+		assert.Equal(t, "in_repair", history.Transitions[0].ToStatus)
+		assert.Equal(t, "ADM", history.Transitions[0].Actor)
+		assert.Equal(t, "in_repair", history.Transitions[1].FromStatus)
+		assert.Equal(t, "in_stock", history.Transitions[1].ToStatus)
+	})
+
+	t.Run("Retired is terminal", func(t *testing.T) {
+		resp := transitionComputerStatus(1, map[string]any{"status": "retired", "reason": "End of life", "actor": "ADM"})
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = transitionComputerStatus(1, map[string]any{"status": "in_stock", "reason": "Test", "actor": "ADM"})
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		var conflict handler.Problem
+		err := json.NewDecoder(resp.Body).Decode(&conflict)
+		require.NoError(t, err)
+
+		assert.Equal(t, "invalid_status_transition", conflict.Code)
+		assert.Empty(t, conflict.AllowedNextStates)
+	})
+}
+
+func getComputerResponse(t *testing.T, computerID int) handler.GetComputerByIDResponse {
+	t.Helper()
+
+	resp := getComputerByID(computerID)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var computer handler.GetComputerByIDResponse
+	err := json.NewDecoder(resp.Body).Decode(&computer)
+	require.NoError(t, err)
+
+	return computer
+}
+
+func transitionComputerStatus(computerID int, data map[string]any) *http.Response {
+	jsonBody, _ := json.Marshal(data)
+
+	targetComputerID := strconv.Itoa(computerID)
+	req := httptest.NewRequest(http.MethodPost, "/computers/"+targetComputerID+"/transitions", bytes.NewReader(jsonBody))
+	req = mux.SetURLVars(req, map[string]string{"computerID": targetComputerID})
+
+	rec := httptest.NewRecorder()
+	h.TransitionComputerStatus(rec, req)
+
+	return rec.Result()
+}
+
+func TestNetworkInterfacesIntegration(t *testing.T) {
+	defer truncateTable()
+
+	resp, err := addComputer(map[string]any{
+		"name":        "TestPC-01",
+		"ip_address":  "192.168.1.101",
+		"mac_address": "AA:BB:CC:DD:EE:F1",
+	})
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	t.Run("A computer is added with a primary interface", func(t *testing.T) {
+		interfaces := getNetworkInterfacesResponse(t, 1)
+
+		require.Len(t, interfaces.Interfaces, 1)
+		assert.Equal(t, "primary", interfaces.Interfaces[0].Name)
+		assert.Equal(t, "AA:BB:CC:DD:EE:F1", interfaces.Interfaces[0].MACAddress)
+		assert.Equal(t, "192.168.1.101", *interfaces.Interfaces[0].IPAddress)
+		assert.True(t, interfaces.Interfaces[0].Primary)
+	})
+
+	t.Run("A second interface can be added and found by its IP address", func(t *testing.T) {
+		resp := addNetworkInterface(1, map[string]any{
+			"name":        "wlan0",
+			"mac_address": "AA:BB:CC:DD:EE:F2",
+			"ip_address":  "192.168.2.101",
+			"type":        "wifi",
+		})
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		interfaces := getNetworkInterfacesResponse(t, 1)
+		require.Len(t, interfaces.Interfaces, 2)
+		assert.Equal(t, "wlan0", interfaces.Interfaces[1].Name)
+		assert.Equal(t, "wifi", interfaces.Interfaces[1].Type)
+		assert.False(t, interfaces.Interfaces[1].Primary)
+
+		computer := getComputerResponse(t, 1)
+		assert.Equal(t, "192.168.1.101", computer.IPAddress)
+		assert.Equal(t, "AA:BB:CC:DD:EE:F1", computer.MACAddress)
+	})
+
+	t.Run("A MAC address can only be used once", func(t *testing.T) {
+		resp := addNetworkInterface(1, map[string]any{
+			"name":        "eth1",
+			"mac_address": "AA:BB:CC:DD:EE:F2",
+		})
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Promoting an interface changes the addresses of the computer", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/computers/1/interfaces/2", bytes.NewReader([]byte(
+			`{"name":"wlan0","mac_address":"AA:BB:CC:DD:EE:F2","ip_address":"192.168.2.101","type":"wifi","primary":true}`)))
+		req = mux.SetURLVars(req, map[string]string{"computerID": "1", "interfaceID": "2"})
+		rec := httptest.NewRecorder()
+		h.UpdateNetworkInterface(rec, req)
+
+		resp := rec.Result()
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		computer := getComputerResponse(t, 1)
+		assert.Equal(t, "192.168.2.101", computer.IPAddress)
+		assert.Equal(t, "AA:BB:CC:DD:EE:F2", computer.MACAddress)
+	})
+
+	t.Run("The primary interface cannot be deleted", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/computers/1/interfaces/2", nil)
+		req = mux.SetURLVars(req, map[string]string{"computerID": "1", "interfaceID": "2"})
+		rec := httptest.NewRecorder()
+		h.DeleteNetworkInterface(rec, req)
+
+		resp := rec.Result()
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		req = httptest.NewRequest(http.MethodDelete, "/computers/1/interfaces/1", nil)
+		req = mux.SetURLVars(req, map[string]string{"computerID": "1", "interfaceID": "1"})
+		rec = httptest.NewRecorder()
+		h.DeleteNetworkInterface(rec, req)
+
+		resp = rec.Result()
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		interfaces := getNetworkInterfacesResponse(t, 1)
+		require.Len(t, interfaces.Interfaces, 1)
+		assert.Equal(t, "wlan0", interfaces.Interfaces[0].Name)
+	})
+}
+
+func getNetworkInterfacesResponse(t *testing.T, computerID int) handler.GetNetworkInterfacesResponse {
+	t.Helper()
+
+	targetComputerID := strconv.Itoa(computerID)
+	req := httptest.NewRequest(http.MethodGet, "/computers/"+targetComputerID+"/interfaces", nil)
+	req = mux.SetURLVars(req, map[string]string{"computerID": targetComputerID})
+	rec := httptest.NewRecorder()
+	h.GetNetworkInterfaces(rec, req)
+
+	resp := rec.Result()
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var interfaces handler.GetNetworkInterfacesResponse
+	err := json.NewDecoder(resp.Body).Decode(&interfaces)
+	require.NoError(t, err)
+
+	return interfaces
+}
+
+func addNetworkInterface(computerID int, data map[string]any) *http.Response {
+	jsonBody, _ := json.Marshal(data)
+
+	targetComputerID := strconv.Itoa(computerID)
+	req := httptest.NewRequest(http.MethodPost, "/computers/"+targetComputerID+"/interfaces", bytes.NewReader(jsonBody))
+	req = mux.SetURLVars(req, map[string]string{"computerID": targetComputerID})
+
+	rec := httptest.NewRecorder()
+	h.AddNetworkInterface(rec, req)
+
+	return rec.Result()
+}
+
+func TestAssignmentsIntegration(t *testing.T) {
+	defer truncateTable()
+
+	resp, err := addComputer(map[string]any{
+		"name":        "TestPC-01",
+		"ip_address":  "192.168.1.101",
+		"mac_address": "AA:BB:CC:DD:EE:F1",
+	})
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	t.Run("Checking out assigns the computer", func(t *testing.T) {
+		resp := checkOutComputer(1, map[string]any{"employee_abbreviation": "EMP", "actor": "ADM"})
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		computer := getComputerResponse(t, 1)
+		assert.Equal(t, "assigned", computer.Status)
+		require.NotNil(t, computer.EmployeeAbbreviation)
+		assert.Equal(t, "EMP", *computer.EmployeeAbbreviation)
+
+		resp = checkOutComputer(1, map[string]any{"employee_abbreviation": "ABC", "actor": "ADM"})
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Checking in ends the assignment", func(t *testing.T) {
+		resp := checkInComputer(1, map[string]any{"actor": "ADM"})
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var assignment handler.AssignmentResponse
+		err := json.NewDecoder(resp.Body).Decode(&assignment)
+		require.NoError(t, err)
+
+		assert.Equal(t, "EMP", assignment.EmployeeAbbreviation)
+		assert.False(t, assignment.Active)
+		require.NotNil(t, assignment.EndedAt)
+
+		computer := getComputerResponse(t, 1)
+		assert.Equal(t, "in_stock", computer.Status)
+		assert.Nil(t, computer.EmployeeAbbreviation)
+
+		resp = checkInComputer(1, map[string]any{"actor": "ADM"})
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("The assignment history keeps previous owners", func(t *testing.T) {
+		resp := checkOutComputer(1, map[string]any{"employee_abbreviation": "ABC", "actor": "ADM"})
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		// Reassigning via PUT checks the computer in from ABC and out to XYZ.
+		resp, err := updateComputer(1, map[string]any{
+			"name":                  "TestPC-01",
+			"ip_address":            "192.168.1.101",
+			"mac_address":           "AA:BB:CC:DD:EE:F1",
+			"employee_abbreviation": "XYZ",
+		})
+		require.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		emp := getAssignmentsResponse(t, "EMP")
+		require.Len(t, emp.Assignments, 1)
+		assert.False(t, emp.Assignments[0].Active)
+		assert.Equal(t, "TestPC-01", emp.Assignments[0].ComputerName)
+
+		abc := getAssignmentsResponse(t, "ABC")
+		require.Len(t, abc.Assignments, 1)
+		assert.False(t, abc.Assignments[0].Active)
+
+		xyz := getAssignmentsResponse(t, "XYZ")
+		require.Len(t, xyz.Assignments, 1)
+		assert.True(t, xyz.Assignments[0].Active)
+	})
+}
+
+func checkOutComputer(computerID int, data map[string]any) *http.Response {
+	jsonBody, _ := json.Marshal(data)
+
+	targetComputerID := strconv.Itoa(computerID)
+	req := httptest.NewRequest(http.MethodPost, "/computers/"+targetComputerID+"/checkout", bytes.NewReader(jsonBody))
+	req = mux.SetURLVars(req, map[string]string{"computerID": targetComputerID})
+
+	rec := httptest.NewRecorder()
+	h.CheckOutComputer(rec, req)
+
+	return rec.Result()
+}
+
+func checkInComputer(computerID int, data map[string]any) *http.Response {
+	jsonBody, _ := json.Marshal(data)
+
+	targetComputerID := strconv.Itoa(computerID)
+	req := httptest.NewRequest(http.MethodPost, "/computers/"+targetComputerID+"/checkin", bytes.NewReader(jsonBody))
+	req = mux.SetURLVars(req, map[string]string{"computerID": targetComputerID})
+
+	rec := httptest.NewRecorder()
+	h.CheckInComputer(rec, req)
+
+	return rec.Result()
+}
+
+func getAssignmentsResponse(t *testing.T, employee string) handler.GetAssignmentsResponse {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/employees/"+employee+"/assignments", nil)
+	req = mux.SetURLVars(req, map[string]string{"employee": employee})
+	rec := httptest.NewRecorder()
+	h.GetAssignmentsByEmployee(rec, req)
+
+	resp := rec.Result()
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var assignments handler.GetAssignmentsResponse
+	err := json.NewDecoder(resp.Body).Decode(&assignments)
+	require.NoError(t, err)
+
+	return assignments
+}
+
+func TestBatchIntegration(t *testing.T) {
+	defer truncateTable()
+
+	computersToBeAdded := []map[string]any{
+		{"name": "TestPC-01", "ip_address": "192.168.1.101", "mac_address": "AA:BB:CC:DD:EE:F1", "location": "Berlin"},
+		{"name": "TestPC-02", "ip_address": "192.168.1.102", "mac_address": "AA:BB:CC:DD:EE:F2", "location": "Berlin"},
+		{"name": "TestPC-03", "ip_address": "192.168.1.103", "mac_address": "AA:BB:CC:DD:EE:F3", "location": "Munich"},
+	}
+
+	for _, computer := range computersToBeAdded {
+		resp, err := addComputer(computer)
+		require.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	t.Run("A dry run returns the affected computers without changing them", func(t *testing.T) {
+		resp := batchRequest(h.BatchUpdateComputers, "/computers:batchUpdate?dry_run=true", map[string]any{
+			"filter":  map[string]string{"location": "berlin"},
+			"changes": map[string]string{"operating_system": "Linux"},
+		})
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result handler.BatchResponse
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		require.NoError(t, err)
+
+		assert.True(t, result.DryRun)
+		require.Len(t, result.Computers, 2)
+		assert.Equal(t, "Linux", *result.Computers[0].OperatingSystem)
+
+		computer := getComputerResponse(t, 1)
+		assert.Nil(t, computer.OperatingSystem)
+	})
+
+	t.Run("A batch update changes all selected computers", func(t *testing.T) {
+		resp := batchRequest(h.BatchUpdateComputers, "/computers:batchUpdate", map[string]any{
+			"ids":     []int{1, 2},
+			"changes": map[string]string{"operating_system": "Linux"},
+		})
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		for _, id := range []int{1, 2} {
+			computer := getComputerResponse(t, id)
+			assert.Equal(t, "Linux", *computer.OperatingSystem)
+			assert.Equal(t, "Berlin", *computer.Location)
+		}
+	})
+
+	t.Run("A batch update moves the selected computers to another employee", func(t *testing.T) {
+		for _, employee := range []string{"EMP", "NEW"} {
+			resp := batchRequest(h.BatchUpdateComputers, "/computers:batchUpdate", map[string]any{
+				"ids":     []int{1, 2},
+				"changes": map[string]string{"employee_abbreviation": employee},
+			})
+			defer resp.Body.Close()
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+
+		for _, id := range []int{1, 2} {
+			computer := getComputerResponse(t, id)
+			assert.Equal(t, "NEW", *computer.EmployeeAbbreviation)
+			assert.Equal(t, "assigned", computer.Status)
+		}
+
+		emp := getAssignmentsResponse(t, "EMP")
+		require.Len(t, emp.Assignments, 2)
+
+		for _, assignment := range emp.Assignments {
+			assert.False(t, assignment.Active)
+		}
+
+		assignments := getAssignmentsResponse(t, "NEW")
+		require.Len(t, assignments.Assignments, 2)
+
+		for _, assignment := range assignments.Assignments {
+			assert.True(t, assignment.Active)
+		}
+	})
+
+	t.Run("Unknown IDs reject the whole batch", func(t *testing.T) {
+		resp := batchRequest(h.BatchUpdateComputers, "/computers:batchUpdate", map[string]any{
+			"ids":     []int{3, 99},
+			"changes": map[string]string{"location": "Hamburg"},
+		})
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		computer := getComputerResponse(t, 3)
+		assert.Equal(t, "Munich", *computer.Location)
+	})
+
+	t.Run("A batch delete removes all selected computers and keeps their assignments", func(t *testing.T) {
+		resp := batchRequest(h.BatchDeleteComputers, "/computers:batchDelete", map[string]any{
+			"filter": map[string]string{"location": "Berlin"},
+		})
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result handler.BatchResponse
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		require.NoError(t, err)
+
+		assert.False(t, result.DryRun)
+		assert.Len(t, result.Computers, 2)
+
+		resp = getAllComputers()
+		defer resp.Body.Close()
+
+		var computers handler.GetComputersResponse
+		err = json.NewDecoder(resp.Body).Decode(&computers)
+		require.NoError(t, err)
+
+		require.Len(t, computers.Computers, 1)
+		assert.Equal(t, "TestPC-03", computers.Computers[0].Name)
+
+		assignments := getAssignmentsResponse(t, "NEW")
+		require.Len(t, assignments.Assignments, 2)
+
+		var names []string
+		for _, assignment := range assignments.Assignments {
+			assert.False(t, assignment.Active)
+			assert.Zero(t, assignment.ComputerID)
+			names = append(names, assignment.ComputerName)
+		}
+
+		assert.ElementsMatch(t, []string{"TestPC-01", "TestPC-02"}, names)
+	})
+}
+
+func batchRequest(handle http.HandlerFunc, target string, data map[string]any) *http.Response {
+	jsonBody, _ := json.Marshal(data)
+
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(jsonBody))
+	rec := httptest.NewRecorder()
+	handle(rec, req)
+
+	return rec.Result()
+}
+
+func TestWithTxIntegration(t *testing.T) {
+	defer truncateTable()
+
+	repository := internal_postgres.NewRepository(db)
+
+	countComputers := func(t *testing.T) int {
+		var count int
+		err := db.QueryRow("SELECT count(*) FROM computers").Scan(&count)
+		require.NoError(t, err)
+
+		return count
+	}
+
+	t.Run("All changes are rolled back if the function fails", func(t *testing.T) {
+		errAbort := errors.New("abort")
+
+		err := repository.WithTx(context.Background(), func(repo service.ComputerRepository) error {
+			computerID, err := repo.AddComputer(dbo.Computer{
+				Name:                 "TestPC-01",
+				IPAddress:            "10.0.0.1",
+				MACAddress:           "AA:BB:CC:DD:EE:01",
+				EmployeeAbbreviation: sql.NullString{String: "EMP", Valid: true},
+				Status:               "assigned",
+			})
+			require.NoError(t, err)
+
+			// Queries within the transaction see the uncommitted computer.
+			_, err = repo.GetComputer(computerID)
+			require.NoError(t, err)
+
+			return errAbort
+		})
+		require.ErrorIs(t, err, errAbort)
+
+		assert.Equal(t, 0, countComputers(t))
+
+		var assignments int
+		err = db.QueryRow("SELECT count(*) FROM assignments").Scan(&assignments)
+		require.NoError(t, err)
+		assert.Equal(t, 0, assignments)
+	})
+
+	t.Run("All changes are stored if the function succeeds", func(t *testing.T) {
+		err := repository.WithTx(context.Background(), func(repo service.ComputerRepository) error {
+			_, err := repo.AddComputer(dbo.Computer{
+				Name:       "TestPC-02",
+				IPAddress:  "10.0.0.2",
+				MACAddress: "AA:BB:CC:DD:EE:02",
+				Status:     "in_stock",
+			})
+
+			return err
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, countComputers(t))
+	})
+
+	t.Run("A failing service operation leaves no partial changes", func(t *testing.T) {
+		resp, err := addComputer(map[string]any{
+			"name":        "TestPC-03",
+			"ip_address":  "10.0.0.3",
+			"mac_address": "AA:BB:CC:DD:EE:03",
+		})
+		require.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		// Identities are not rolled back, so the computers added above have the IDs 2 and 3. Adding an interface
+		// with the MAC address of TestPC-02 fails after the IP conflict check and must not leave anything behind.
+		resp = addNetworkInterface(3, map[string]any{
+			"name":        "eth1",
+			"mac_address": "AA:BB:CC:DD:EE:02",
+			"type":        "ethernet",
+		})
+
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Len(t, getNetworkInterfacesResponse(t, 3).Interfaces, 1)
+	})
+}
+
+func TestAssignmentWarningsIntegration(t *testing.T) {
+	defer truncateTable()
+
+	repository := internal_postgres.NewRepository(db)
+	defer repository.Close()
+
+	claim := func(count int, cooldown time.Duration) bool {
+		claimed, err := repository.ClaimAssignmentWarning("EMP", count, cooldown)
+		require.NoError(t, err)
+
+		return claimed
+	}
+
+	assert.True(t, claim(3, time.Hour), "first warning")
+	assert.False(t, claim(3, time.Hour), "same count within the cool-down")
+	assert.True(t, claim(4, time.Hour), "increased count within the cool-down")
+	assert.False(t, claim(3, time.Hour), "lower count within the cool-down")
+	assert.True(t, claim(4, 0), "same count after the cool-down")
+
+	resolved, err := repository.ResolveAssignmentWarning("EMP")
+	require.NoError(t, err)
+	assert.True(t, resolved)
+
+	resolved, err = repository.ResolveAssignmentWarning("EMP")
+	require.NoError(t, err)
+	assert.False(t, resolved)
+
+	assert.True(t, claim(3, time.Hour), "warning after the resolution")
+}
+
+func TestCountComputersByEmployeeIntegration(t *testing.T) {
+	defer truncateTable()
+
+	for i := 1; i <= 3; i++ {
+		if i == 3 {
+			// The third computer triggers a notification.
+			wg.Add(1)
+		}
+
+		resp, err := addComputer(map[string]any{
+			"name":                  fmt.Sprintf("TestPC-%02d", i),
+			"ip_address":            fmt.Sprintf("10.0.1.%d", i),
+			"mac_address":           fmt.Sprintf("AA:BB:CC:DD:EE:%02X", i),
+			"employee_abbreviation": "EMP",
+		})
+		require.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	wg.Wait()
+
+	repository := internal_postgres.NewRepository(db)
+	defer repository.Close()
+
+	count, err := repository.CountComputersByEmployee("EMP")
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	resp := checkInComputer(1, map[string]any{"actor": "ADM"})
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	count, err = repository.CountComputersByEmployee("EMP")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "past assignments must not be counted")
+}
+
+// BenchmarkRepositoryIntegration compares preparing a statement for every call with the cached prepared
+// statements of the repository and fetching the computers of an employee with counting them.
+func BenchmarkRepositoryIntegration(b *testing.B) {
+	defer truncateTable()
+
+	repository := internal_postgres.NewRepository(db)
+	defer repository.Close()
+
+	var computerID int
+
+	for i := 1; i <= 3; i++ {
+		id, err := repository.AddComputer(dbo.Computer{
+			Name:                 fmt.Sprintf("BenchPC-%02d", i),
+			IPAddress:            fmt.Sprintf("10.0.2.%d", i),
+			MACAddress:           fmt.Sprintf("AA:BB:CC:DD:EF:%02X", i),
+			EmployeeAbbreviation: sql.NullString{String: "EMP", Valid: true},
+			Status:               "assigned",
+		})
+		require.NoError(b, err)
+
+		computerID = id
+	}
+
+	// Both cases run the same query of GetComputer. A new repository starts with an empty statement cache, so it
+	// prepares the statement on every call.
+	b.Run("GetComputer with Prepare per call", func(b *testing.B) {
+		for b.Loop() {
+			uncached := internal_postgres.NewRepository(db)
+
+			_, err := uncached.GetComputer(computerID)
+			require.NoError(b, err)
+
+			require.NoError(b, uncached.Close())
+		}
+	})
+
+	b.Run("GetComputer with cached statement", func(b *testing.B) {
+		for b.Loop() {
+			_, err := repository.GetComputer(computerID)
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("Threshold check with GetComputersByEmployee", func(b *testing.B) {
+		for b.Loop() {
+			computers, err := repository.GetComputersByEmployee(context.Background(), "EMP")
+			require.NoError(b, err)
+			require.Len(b, computers, 3)
+		}
+	})
+
+	b.Run("Threshold check with CountComputersByEmployee", func(b *testing.B) {
+		for b.Loop() {
+			count, err := repository.CountComputersByEmployee("EMP")
+			require.NoError(b, err)
+			require.Equal(b, 3, count)
+		}
+	})
+}
+
+func TestReadReplicaIntegration(t *testing.T) {
+	defer truncateTable()
+
+	// A second database of the same container stands in for the replica. It holds different computers, so that
+	// the results show which database answered a query.
+	_, err := db.Exec("CREATE DATABASE replicadb")
+	require.NoError(t, err)
+
+	replicaURL, err := url.Parse(dbConnectionString)
+	require.NoError(t, err)
+
+	replicaURL.Path = "/replicadb"
+
+	replicaDB, err := sql.Open("postgres", replicaURL.String())
+	require.NoError(t, err)
+
+	defer replicaDB.Close()
+
+	require.NoError(t, internal_db.RunMigrations(replicaDB))
+
+	addTo := func(repository *internal_postgres.Repository, name, macAddress string) {
+		_, err := repository.AddComputer(dbo.Computer{
+			Name:                 name,
+			IPAddress:            "10.0.3.1",
+			MACAddress:           macAddress,
+			EmployeeAbbreviation: sql.NullString{String: "EMP", Valid: true},
+			Status:               "assigned",
+		})
+		require.NoError(t, err)
+	}
+
+	primaryRepository := internal_postgres.NewRepository(db)
+	defer primaryRepository.Close()
+
+	addTo(primaryRepository, "PrimaryPC", "AA:BB:CC:DD:F0:01")
+	addTo(internal_postgres.NewRepository(replicaDB), "ReplicaPC", "AA:BB:CC:DD:F0:02")
+
+	repository := internal_postgres.NewRepository(db, internal_postgres.WithReplica(replicaDB))
+	defer repository.Close()
+
+	names := func(computers []dbo.Computer) []string {
+		var names []string
+		for _, computer := range computers {
+			names = append(names, computer.Name)
+		}
+
+		return names
+	}
+
+	t.Run("List and search queries are served by the replica", func(t *testing.T) {
+		computers, err := repository.GetAllComputers(context.Background(), dbo.ComputerFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ReplicaPC"}, names(computers))
+
+		computers, err = repository.GetComputersByEmployee(context.Background(), "EMP")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ReplicaPC"}, names(computers))
+
+		results, err := repository.SearchComputers(context.Background(), "ReplicaPC")
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "ReplicaPC", results[0].Computer.Name)
+	})
+
+	t.Run("Reads of a request that has to see its own writes are served by the primary", func(t *testing.T) {
+		ctx := consistency.WithReadYourWrites(context.Background())
+
+		computers, err := repository.GetAllComputers(ctx, dbo.ComputerFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"PrimaryPC"}, names(computers))
+	})
+
+	t.Run("Reads within a transaction are served by the primary", func(t *testing.T) {
+		err := repository.WithTx(context.Background(), func(repo service.ComputerRepository) error {
+			computers, err := repo.GetAllComputers(context.Background(), dbo.ComputerFilter{})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"PrimaryPC"}, names(computers))
+
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Reads fall back to the primary if the replica is unreachable", func(t *testing.T) {
+		unreachableDB, err := sql.Open("postgres", "postgres://nobody@127.0.0.1:1/replicadb?sslmode=disable&connect_timeout=1")
+		require.NoError(t, err)
+
+		defer unreachableDB.Close()
+
+		fallbackRepository := internal_postgres.NewRepository(db, internal_postgres.WithReplica(unreachableDB))
+		defer fallbackRepository.Close()
+
+		computers, err := fallbackRepository.GetAllComputers(context.Background(), dbo.ComputerFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"PrimaryPC"}, names(computers))
+	})
+}
+
+func TestConditionalGetIntegration(t *testing.T) {
+	defer truncateTable()
+
+	resp, err := addComputer(map[string]any{
+		"name":        "TestPC-01",
+		"ip_address":  "10.0.4.1",
+		"mac_address": "AA:BB:CC:DD:F1:01",
+	})
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	getAll := func(ifNoneMatch string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/computers", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+
+		rec := httptest.NewRecorder()
+		h.GetAllComputers(rec, req)
+
+		return rec.Result()
+	}
+
+	resp = getAll("")
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.NotEmpty(t, resp.Header.Get("Last-Modified"))
+
+	t.Run("An unchanged collection returns 304", func(t *testing.T) {
+		resp := getAll(etag)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	})
+
+	t.Run("Changing a network interface returns 200 with a new ETag", func(t *testing.T) {
+		resp := addNetworkInterface(1, map[string]any{
+			"name":        "wlan0",
+			"mac_address": "AA:BB:CC:DD:F1:02",
+			"type":        "wifi",
+		})
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = getAll(etag)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotEqual(t, etag, resp.Header.Get("ETag"))
+	})
+
+	t.Run("A transaction committed after a later started one does not move Last-Modified backwards", func(t *testing.T) {
+		changedAt := func() time.Time {
+			var changedAt time.Time
+			err := db.QueryRow("SELECT changed_at FROM computers_version").Scan(&changedAt)
+			require.NoError(t, err)
+
+			return changedAt
+		}
+
+		earlier, err := db.Begin()
+		require.NoError(t, err)
+
+		defer earlier.Rollback()
+
+		_, err = earlier.Exec("SELECT 1")
+		require.NoError(t, err)
+
+		time.Sleep(10 * time.Millisecond)
+
+		_, err = db.Exec("UPDATE computers SET description = 'later' WHERE id = 1")
+		require.NoError(t, err)
+
+		afterLater := changedAt()
+
+		_, err = earlier.Exec("UPDATE computers SET description = 'earlier' WHERE id = 1")
+		require.NoError(t, err)
+		require.NoError(t, earlier.Commit())
+
+		assert.False(t, changedAt().Before(afterLater), "changed_at has moved backwards")
+	})
+}
+
+func TestWarrantyCheckIntegration(t *testing.T) {
+	defer truncateTable()
+
+	today := time.Now()
+
+	computersToBeAdded := []map[string]any{
+		{
+			"name":          "TestPC-01",
+			"ip_address":    "192.168.1.101",
+			"mac_address":   "AA:BB:CC:DD:EE:F1",
+			"serial_number": "SN-001",
+			"purchase_date": today.AddDate(-3, 0, 0).Format(time.DateOnly),
+			"warranty_end":  today.AddDate(0, 0, 10).Format(time.DateOnly),
+		},
+		{
+			"name":         "TestPC-02",
+			"ip_address":   "192.168.1.102",
+			"mac_address":  "AA:BB:CC:DD:EE:F2",
+			"warranty_end": today.AddDate(0, 0, 100).Format(time.DateOnly),
+		},
+	}
+
+	for _, computer := range computersToBeAdded {
+		resp, err := addComputer(computer)
+		require.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	wh := handler.NewWarrantyHandler(service.NewWarrantyMgmtService(internal_postgres.NewRepository(db), notifier, 30))
+
+	checkWarranties := func(t *testing.T) handler.WarrantyCheckResponse {
+		req := httptest.NewRequest(http.MethodPost, "/jobs/warranty-check", nil)
+		rec := httptest.NewRecorder()
+		wh.CheckWarranties(rec, req)
+
+		resp := rec.Result()
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result handler.WarrantyCheckResponse
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		require.NoError(t, err)
+
+		return result
+	}
+
+	t.Run("Computers whose warranty ends within the window are reported", func(t *testing.T) {
+		wg.Add(1)
+
+		result := checkWarranties(t)
+
+		wg.Wait()
+
+		require.Equal(t, 1, result.Notified)
+		assert.Equal(t, "TestPC-01", result.Computers[0].Name)
+
+		var sentMessage notify.HTTPPayload
+
+		err := json.Unmarshal(notificationPayload, &sentMessage)
+		require.NoError(t, err)
+
+		assert.Equal(t, "warranty", sentMessage.Level)
+		assert.Contains(t, sentMessage.Message, "SN-001")
+	})
+
+	t.Run("Computers are not reported twice for the same warranty end", func(t *testing.T) {
+		result := checkWarranties(t)
+
+		assert.Equal(t, 0, result.Notified)
+		assert.Empty(t, result.Computers)
+	})
+}
+
+func TestComputerEventsIntegration(t *testing.T) {
+	defer truncateTable()
+
+	repository := internal_postgres.NewRepository(db)
+	bus := events.NewBus(10)
+
+	relay, err := service.NewEventRelay(context.Background(), repository, bus)
+	require.NoError(t, err)
+
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+
+	go relay.Run(relayCtx, time.Minute)
+
+	computerMgmtService := service.NewComputerMgmtService(repository, notifier, service.WithEventPublisher(relay))
+	eventMgmtService := service.NewEventMgmtService(repository, bus, time.Hour)
+	eh := handler.NewEventHandler(eventMgmtService, time.Minute)
+
+	sub := bus.Subscribe()
+	defer sub.Close()
+
+	computerID, err := computerMgmtService.AddComputer(model.Computer{Name: "TestPC-01", IPAddress: "10.0.0.1", MACAddress: "AA:BB:CC:DD:EE:01"})
+	require.NoError(t, err)
+
+	_, err = computerMgmtService.CheckOutComputer(computerID, "EMP", "ADM")
+	require.NoError(t, err)
+
+	require.NoError(t, computerMgmtService.DeleteComputer(computerID))
+
+	t.Run("Committed changes are published in order", func(t *testing.T) {
+		var types []model.ComputerEventType
+		for range 3 {
+			event := <-sub.Events()
+			types = append(types, event.Type)
+		}
+
+		assert.Equal(t, []model.ComputerEventType{model.ComputerCreated, model.ComputerAssigned, model.ComputerDeleted}, types)
+	})
+
+	t.Run("A client resumes the stream from the event log", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		req := httptest.NewRequest(http.MethodGet, "/events?employee=EMP", nil).WithContext(ctx)
+		req.Header.Set("Last-Event-ID", "1")
+		rec := httptest.NewRecorder()
+		eh.StreamComputerEvents(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		body := rec.Body.String()
+		assert.NotContains(t, body, "computer.created")
+		assert.Contains(t, body, "id: 2\nevent: computer.assigned\n")
+		assert.Contains(t, body, "id: 3\nevent: computer.deleted\n")
+	})
+
+	t.Run("Events are numbered in the order in which they are committed", func(t *testing.T) {
+		type result struct {
+			events []dbo.ComputerEvent
+			err    error
+		}
+
+		event := dbo.ComputerEvent{Type: string(model.ComputerUpdated), ComputerID: computerID}
+
+		recorded := make(chan struct{})
+		commit := make(chan struct{})
+		first := make(chan result, 1)
+
+		go func() {
+			var stored []dbo.ComputerEvent
+
+			err := repository.WithTx(context.Background(), func(repo service.ComputerRepository) error {
+				var err error
+				stored, err = repo.AddComputerEvents([]dbo.ComputerEvent{event})
+				close(recorded)
+				<-commit
+
+				return err
+			})
+			first <- result{stored, err}
+		}()
+
+		<-recorded
+
+		second := make(chan result, 1)
+
+		go func() {
+			stored, err := repository.AddComputerEvents([]dbo.ComputerEvent{event})
+			second <- result{stored, err}
+		}()
+
+		select {
+		case <-second:
+			assert.Fail(t, "an event has been recorded while an earlier transaction recording events was open")
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		close(commit)
+
+		firstResult, secondResult := <-first, <-second
+		require.NoError(t, firstResult.err)
+		require.NoError(t, secondResult.err)
+		assert.Greater(t, secondResult.events[0].ID, firstResult.events[0].ID)
+
+		// The relay reads the events from the event log, so a single hint publishes both in the order of their IDs.
+		relay.Publish()
+
+		assert.Equal(t, firstResult.events[0].ID, (<-sub.Events()).ID)
+		assert.Equal(t, secondResult.events[0].ID, (<-sub.Events()).ID)
+	})
+
+	t.Run("Events older than the retention are deleted", func(t *testing.T) {
+		_, err := db.Exec("UPDATE computer_events SET occurred_at = now() - interval '2 hours' WHERE id = 1")
+		require.NoError(t, err)
+
+		deleted, err := eventMgmtService.DeleteExpiredComputerEvents()
+		require.NoError(t, err)
+		assert.Equal(t, 1, deleted)
+
+		events, err := eventMgmtService.GetComputerEventsSince(context.Background(), 0, 10)
+		require.NoError(t, err)
+		require.NotEmpty(t, events)
+		assert.Equal(t, int64(2), events[0].ID)
+	})
+}
+
+// truncateTable clears the computers, subnets, computer_events, webhook_subscriptions and assignment_warnings tables and
+// resets the identity columns.
 func truncateTable() {
-	_, err := db.Exec("TRUNCATE TABLE computers RESTART IDENTITY CASCADE")
+	_, err := db.Exec("TRUNCATE TABLE computers, subnets, computer_events, webhook_subscriptions, assignment_warnings RESTART IDENTITY CASCADE")
 	if err != nil {
 		log.Fatalf("failed to truncate table: %v", err)
 	}
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 	model "uhuaha/computers-management/internal/model"
 
@@ -49,6 +50,81 @@ func (mr *MockComputerMgmtServiceMockRecorder) AddComputer(computer interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComputer", reflect.TypeOf((*MockComputerMgmtService)(nil).AddComputer), computer)
 }
 
+// AddNetworkInterface mocks base method.
+func (m *MockComputerMgmtService) AddNetworkInterface(computerID int, iface model.NetworkInterface) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNetworkInterface", computerID, iface)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddNetworkInterface indicates an expected call of AddNetworkInterface.
+func (mr *MockComputerMgmtServiceMockRecorder) AddNetworkInterface(computerID, iface interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNetworkInterface", reflect.TypeOf((*MockComputerMgmtService)(nil).AddNetworkInterface), computerID, iface)
+}
+
+// BatchDeleteComputers mocks base method.
+func (m *MockComputerMgmtService) BatchDeleteComputers(selector model.ComputerSelector, dryRun bool) ([]model.Computer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchDeleteComputers", selector, dryRun)
+	ret0, _ := ret[0].([]model.Computer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchDeleteComputers indicates an expected call of BatchDeleteComputers.
+func (mr *MockComputerMgmtServiceMockRecorder) BatchDeleteComputers(selector, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchDeleteComputers", reflect.TypeOf((*MockComputerMgmtService)(nil).BatchDeleteComputers), selector, dryRun)
+}
+
+// BatchUpdateComputers mocks base method.
+func (m *MockComputerMgmtService) BatchUpdateComputers(selector model.ComputerSelector, changes model.ComputerChanges, dryRun bool) ([]model.Computer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchUpdateComputers", selector, changes, dryRun)
+	ret0, _ := ret[0].([]model.Computer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchUpdateComputers indicates an expected call of BatchUpdateComputers.
+func (mr *MockComputerMgmtServiceMockRecorder) BatchUpdateComputers(selector, changes, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchUpdateComputers", reflect.TypeOf((*MockComputerMgmtService)(nil).BatchUpdateComputers), selector, changes, dryRun)
+}
+
+// CheckInComputer mocks base method.
+func (m *MockComputerMgmtService) CheckInComputer(computerID int, actor string) (model.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckInComputer", computerID, actor)
+	ret0, _ := ret[0].(model.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckInComputer indicates an expected call of CheckInComputer.
+func (mr *MockComputerMgmtServiceMockRecorder) CheckInComputer(computerID, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckInComputer", reflect.TypeOf((*MockComputerMgmtService)(nil).CheckInComputer), computerID, actor)
+}
+
+// CheckOutComputer mocks base method.
+func (m *MockComputerMgmtService) CheckOutComputer(computerID int, employee, actor string) (model.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckOutComputer", computerID, employee, actor)
+	ret0, _ := ret[0].(model.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckOutComputer indicates an expected call of CheckOutComputer.
+func (mr *MockComputerMgmtServiceMockRecorder) CheckOutComputer(computerID, employee, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckOutComputer", reflect.TypeOf((*MockComputerMgmtService)(nil).CheckOutComputer), computerID, employee, actor)
+}
+
 // DeleteComputer mocks base method.
 func (m *MockComputerMgmtService) DeleteComputer(computerID int) error {
 	m.ctrl.T.Helper()
@@ -63,49 +139,201 @@ func (mr *MockComputerMgmtServiceMockRecorder) DeleteComputer(computerID interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComputer", reflect.TypeOf((*MockComputerMgmtService)(nil).DeleteComputer), computerID)
 }
 
-// GetAllComputers mocks base method.
-func (m *MockComputerMgmtService) GetAllComputers() ([]model.Computer, error) {
+// DeleteNetworkInterface mocks base method.
+func (m *MockComputerMgmtService) DeleteNetworkInterface(computerID, interfaceID int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllComputers")
-	ret0, _ := ret[0].([]model.Computer)
+	ret := m.ctrl.Call(m, "DeleteNetworkInterface", computerID, interfaceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNetworkInterface indicates an expected call of DeleteNetworkInterface.
+func (mr *MockComputerMgmtServiceMockRecorder) DeleteNetworkInterface(computerID, interfaceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNetworkInterface", reflect.TypeOf((*MockComputerMgmtService)(nil).DeleteNetworkInterface), computerID, interfaceID)
+}
+
+// GetAssignmentsByEmployee mocks base method.
+func (m *MockComputerMgmtService) GetAssignmentsByEmployee(employee string) ([]model.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignmentsByEmployee", employee)
+	ret0, _ := ret[0].([]model.Assignment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllComputers indicates an expected call of GetAllComputers.
-func (mr *MockComputerMgmtServiceMockRecorder) GetAllComputers() *gomock.Call {
+// GetAssignmentsByEmployee indicates an expected call of GetAssignmentsByEmployee.
+func (mr *MockComputerMgmtServiceMockRecorder) GetAssignmentsByEmployee(employee interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllComputers", reflect.TypeOf((*MockComputerMgmtService)(nil).GetAllComputers))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignmentsByEmployee", reflect.TypeOf((*MockComputerMgmtService)(nil).GetAssignmentsByEmployee), employee)
 }
 
-// GetComputer mocks base method.
-func (m *MockComputerMgmtService) GetComputer(computerID int) (model.Computer, error) {
+// GetComputerVersion mocks base method.
+func (m *MockComputerMgmtService) GetComputerVersion(ctx context.Context, computerID int) (model.ComputersVersion, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetComputer", computerID)
-	ret0, _ := ret[0].(model.Computer)
+	ret := m.ctrl.Call(m, "GetComputerVersion", ctx, computerID)
+	ret0, _ := ret[0].(model.ComputersVersion)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetComputer indicates an expected call of GetComputer.
-func (mr *MockComputerMgmtServiceMockRecorder) GetComputer(computerID interface{}) *gomock.Call {
+// GetComputerVersion indicates an expected call of GetComputerVersion.
+func (mr *MockComputerMgmtServiceMockRecorder) GetComputerVersion(ctx, computerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComputer", reflect.TypeOf((*MockComputerMgmtService)(nil).GetComputer), computerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComputerVersion", reflect.TypeOf((*MockComputerMgmtService)(nil).GetComputerVersion), ctx, computerID)
 }
 
-// GetComputersByEmployee mocks base method.
-func (m *MockComputerMgmtService) GetComputersByEmployee(employee string) ([]model.Computer, error) {
+// GetComputersVersion mocks base method.
+func (m *MockComputerMgmtService) GetComputersVersion(ctx context.Context) (model.ComputersVersion, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetComputersByEmployee", employee)
+	ret := m.ctrl.Call(m, "GetComputersVersion", ctx)
+	ret0, _ := ret[0].(model.ComputersVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComputersVersion indicates an expected call of GetComputersVersion.
+func (mr *MockComputerMgmtServiceMockRecorder) GetComputersVersion(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComputersVersion", reflect.TypeOf((*MockComputerMgmtService)(nil).GetComputersVersion), ctx)
+}
+
+// GetIPConflicts mocks base method.
+func (m *MockComputerMgmtService) GetIPConflicts() ([]model.IPConflict, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIPConflicts")
+	ret0, _ := ret[0].([]model.IPConflict)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIPConflicts indicates an expected call of GetIPConflicts.
+func (mr *MockComputerMgmtServiceMockRecorder) GetIPConflicts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIPConflicts", reflect.TypeOf((*MockComputerMgmtService)(nil).GetIPConflicts))
+}
+
+// GetNetworkInterface mocks base method.
+func (m *MockComputerMgmtService) GetNetworkInterface(computerID, interfaceID int) (model.NetworkInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNetworkInterface", computerID, interfaceID)
+	ret0, _ := ret[0].(model.NetworkInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNetworkInterface indicates an expected call of GetNetworkInterface.
+func (mr *MockComputerMgmtServiceMockRecorder) GetNetworkInterface(computerID, interfaceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetworkInterface", reflect.TypeOf((*MockComputerMgmtService)(nil).GetNetworkInterface), computerID, interfaceID)
+}
+
+// GetNetworkInterfaces mocks base method.
+func (m *MockComputerMgmtService) GetNetworkInterfaces(computerID int) ([]model.NetworkInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNetworkInterfaces", computerID)
+	ret0, _ := ret[0].([]model.NetworkInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNetworkInterfaces indicates an expected call of GetNetworkInterfaces.
+func (mr *MockComputerMgmtServiceMockRecorder) GetNetworkInterfaces(computerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetworkInterfaces", reflect.TypeOf((*MockComputerMgmtService)(nil).GetNetworkInterfaces), computerID)
+}
+
+// GetStatusTransitions mocks base method.
+func (m *MockComputerMgmtService) GetStatusTransitions(computerID int) ([]model.StatusTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusTransitions", computerID)
+	ret0, _ := ret[0].([]model.StatusTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusTransitions indicates an expected call of GetStatusTransitions.
+func (mr *MockComputerMgmtServiceMockRecorder) GetStatusTransitions(computerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusTransitions", reflect.TypeOf((*MockComputerMgmtService)(nil).GetStatusTransitions), computerID)
+}
+
+// GetVersionedComputer mocks base method.
+func (m *MockComputerMgmtService) GetVersionedComputer(ctx context.Context, computerID int) (model.Computer, model.ComputersVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersionedComputer", ctx, computerID)
+	ret0, _ := ret[0].(model.Computer)
+	ret1, _ := ret[1].(model.ComputersVersion)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVersionedComputer indicates an expected call of GetVersionedComputer.
+func (mr *MockComputerMgmtServiceMockRecorder) GetVersionedComputer(ctx, computerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersionedComputer", reflect.TypeOf((*MockComputerMgmtService)(nil).GetVersionedComputer), ctx, computerID)
+}
+
+// GetVersionedComputers mocks base method.
+func (m *MockComputerMgmtService) GetVersionedComputers(ctx context.Context, filter model.ComputerFilter) ([]model.Computer, model.ComputersVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersionedComputers", ctx, filter)
 	ret0, _ := ret[0].([]model.Computer)
+	ret1, _ := ret[1].(model.ComputersVersion)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVersionedComputers indicates an expected call of GetVersionedComputers.
+func (mr *MockComputerMgmtServiceMockRecorder) GetVersionedComputers(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersionedComputers", reflect.TypeOf((*MockComputerMgmtService)(nil).GetVersionedComputers), ctx, filter)
+}
+
+// GetVersionedComputersByEmployee mocks base method.
+func (m *MockComputerMgmtService) GetVersionedComputersByEmployee(ctx context.Context, employee string) ([]model.Computer, model.ComputersVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersionedComputersByEmployee", ctx, employee)
+	ret0, _ := ret[0].([]model.Computer)
+	ret1, _ := ret[1].(model.ComputersVersion)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVersionedComputersByEmployee indicates an expected call of GetVersionedComputersByEmployee.
+func (mr *MockComputerMgmtServiceMockRecorder) GetVersionedComputersByEmployee(ctx, employee interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersionedComputersByEmployee", reflect.TypeOf((*MockComputerMgmtService)(nil).GetVersionedComputersByEmployee), ctx, employee)
+}
+
+// SearchComputers mocks base method.
+func (m *MockComputerMgmtService) SearchComputers(ctx context.Context, query string) ([]model.ComputerSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchComputers", ctx, query)
+	ret0, _ := ret[0].([]model.ComputerSearchResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetComputersByEmployee indicates an expected call of GetComputersByEmployee.
-func (mr *MockComputerMgmtServiceMockRecorder) GetComputersByEmployee(employee interface{}) *gomock.Call {
+// SearchComputers indicates an expected call of SearchComputers.
+func (mr *MockComputerMgmtServiceMockRecorder) SearchComputers(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComputersByEmployee", reflect.TypeOf((*MockComputerMgmtService)(nil).GetComputersByEmployee), employee)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchComputers", reflect.TypeOf((*MockComputerMgmtService)(nil).SearchComputers), ctx, query)
+}
+
+// TransitionComputerStatus mocks base method.
+func (m *MockComputerMgmtService) TransitionComputerStatus(computerID int, transition model.StatusTransition) (model.StatusTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionComputerStatus", computerID, transition)
+	ret0, _ := ret[0].(model.StatusTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransitionComputerStatus indicates an expected call of TransitionComputerStatus.
+func (mr *MockComputerMgmtServiceMockRecorder) TransitionComputerStatus(computerID, transition interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionComputerStatus", reflect.TypeOf((*MockComputerMgmtService)(nil).TransitionComputerStatus), computerID, transition)
 }
 
 // UpdateComputer mocks base method.
@@ -122,37 +350,16 @@ func (mr *MockComputerMgmtServiceMockRecorder) UpdateComputer(computerID, data i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateComputer", reflect.TypeOf((*MockComputerMgmtService)(nil).UpdateComputer), computerID, data)
 }
 
-// MockNotifier is a mock of Notifier interface.
-type MockNotifier struct {
-	ctrl     *gomock.Controller
-	recorder *MockNotifierMockRecorder
-}
-
-// MockNotifierMockRecorder is the mock recorder for MockNotifier.
-type MockNotifierMockRecorder struct {
-	mock *MockNotifier
-}
-
-// NewMockNotifier creates a new mock instance.
-func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
-	mock := &MockNotifier{ctrl: ctrl}
-	mock.recorder = &MockNotifierMockRecorder{mock}
-	return mock
-}
-
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
-	return m.recorder
-}
-
-// SendMessage mocks base method.
-func (m *MockNotifier) SendMessage(employeeAbbreviation string) {
+// UpdateNetworkInterface mocks base method.
+func (m *MockComputerMgmtService) UpdateNetworkInterface(computerID, interfaceID int, data model.NetworkInterface) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "SendMessage", employeeAbbreviation)
+	ret := m.ctrl.Call(m, "UpdateNetworkInterface", computerID, interfaceID, data)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// SendMessage indicates an expected call of SendMessage.
-func (mr *MockNotifierMockRecorder) SendMessage(employeeAbbreviation interface{}) *gomock.Call {
+// UpdateNetworkInterface indicates an expected call of UpdateNetworkInterface.
+func (mr *MockComputerMgmtServiceMockRecorder) UpdateNetworkInterface(computerID, interfaceID, data interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockNotifier)(nil).SendMessage), employeeAbbreviation)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNetworkInterface", reflect.TypeOf((*MockComputerMgmtService)(nil).UpdateNetworkInterface), computerID, interfaceID, data)
 }
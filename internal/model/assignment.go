@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Assignment records the period during which a computer was handed out to an employee.
+// EndedAt is nil as long as the assignment is active.
+type Assignment struct {
+	ID                   int
+	ComputerID           int
+	ComputerName         string
+	EmployeeAbbreviation string
+	StartedAt            time.Time
+	EndedAt              *time.Time
+}
+
+// IsActive reports whether the computer is still assigned to the employee.
+func (a Assignment) IsActive() bool {
+	return a.EndedAt == nil
+}
+
+// AssignmentCount is the number of computers actively assigned to an employee compared with the threshold from
+// which on the system administrator is notified.
+type AssignmentCount struct {
+	EmployeeAbbreviation string
+	Count                int
+	Threshold            int
+	ComputerNames        []string
+}
@@ -1,8 +1,19 @@
 // Package model defines the core domain models for the computer management system.
 package model
 
+import "time"
+
+// AutoIPAddress can be passed as a new computer's IP address to allocate
+// the next free address of the subnet given by Computer.SubnetID.
+const AutoIPAddress = "auto"
+
+// MaxBatchSize limits the number of computers a batch operation can affect, whether they are selected by their IDs
+// or by a filter.
+const MaxBatchSize = 1000
+
 // Computer represents a computer in the management system, including
-// its network information and optional employee assignment.
+// its network information and optional employee assignment. IPAddress and MACAddress
+// are the addresses of the computer's primary NetworkInterface.
 type Computer struct {
 	ID                   int
 	Name                 string
@@ -10,4 +21,69 @@ type Computer struct {
 	MACAddress           string
 	EmployeeAbbreviation *string
 	Description          *string
+	// SubnetID references the subnet containing IPAddress, if any.
+	SubnetID *int
+	// Status is the lifecycle status. It can only be changed by a StatusTransition after the computer has been added.
+	Status ComputerStatus
+
+	// Hardware and lifecycle attributes. PurchaseDate and WarrantyEnd are calendar dates.
+	SerialNumber    *string
+	PurchaseDate    *time.Time
+	WarrantyEnd     *time.Time
+	Vendor          *string
+	Model           *string
+	OperatingSystem *string
+	Location        *string
+}
+
+// ComputerFilter restricts a list of computers to those matching all non-nil fields.
+// Text fields are compared case-insensitively, date bounds are inclusive.
+type ComputerFilter struct {
+	SerialNumber       *string
+	Vendor             *string
+	Model              *string
+	OperatingSystem    *string
+	Location           *string
+	PurchasedAfter     *time.Time
+	PurchasedBefore    *time.Time
+	WarrantyEndsAfter  *time.Time
+	WarrantyEndsBefore *time.Time
+}
+
+// ComputerSelector selects the computers affected by a batch operation, either by their IDs or by a filter.
+type ComputerSelector struct {
+	IDs    []int
+	Filter *ComputerFilter
+}
+
+// ComputerChanges holds the attributes set on all computers of a batch update. Nil fields are left unchanged.
+type ComputerChanges struct {
+	EmployeeAbbreviation *string
+	Description          *string
+	Vendor               *string
+	Model                *string
+	OperatingSystem      *string
+	Location             *string
+}
+
+// IPConflict lists the computers that share the same IP address on any of their network interfaces.
+type IPConflict struct {
+	IPAddress string
+	Computers []Computer
+}
+
+// ComputerSearchResult is a computer matching a search query. Highlights maps the names of the
+// matching fields (name, description, employee_abbreviation, mac_address) to their values with
+// every occurrence of a search term enclosed in <mark></mark>.
+type ComputerSearchResult struct {
+	Computer   Computer
+	Rank       float64
+	Highlights map[string]string
+}
+
+// ComputersVersion identifies the state of all computers. It changes with every change of a computer, of its
+// network interfaces or of the subnets.
+type ComputersVersion struct {
+	Version   int64
+	ChangedAt time.Time
 }
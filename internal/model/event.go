@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// ComputerEventType is the kind of change a ComputerEvent reports.
+type ComputerEventType string
+
+const (
+	ComputerCreated  ComputerEventType = "computer.created"
+	ComputerUpdated  ComputerEventType = "computer.updated"
+	ComputerDeleted  ComputerEventType = "computer.deleted"
+	ComputerAssigned ComputerEventType = "computer.assigned"
+)
+
+// ComputerEvent reports a committed change of a computer. EmployeeAbbreviation is the employee the computer is
+// assigned to after the change or, if the change removed the computer from its employee, the previous one.
+// IDs increase in the order the events have been recorded.
+type ComputerEvent struct {
+	ID                   int64
+	Type                 ComputerEventType
+	ComputerID           int
+	EmployeeAbbreviation *string
+	OccurredAt           time.Time
+}
+
+// ComputerEventFilter holds the optional conditions a streamed event has to match.
+type ComputerEventFilter struct {
+	EmployeeAbbreviation *string
+	ComputerID           *int
+}
+
+// Matches reports whether the event meets all conditions of the filter.
+func (f ComputerEventFilter) Matches(event ComputerEvent) bool {
+	if f.ComputerID != nil && event.ComputerID != *f.ComputerID {
+		return false
+	}
+
+	if f.EmployeeAbbreviation != nil &&
+		(event.EmployeeAbbreviation == nil || *event.EmployeeAbbreviation != *f.EmployeeAbbreviation) {
+		return false
+	}
+
+	return true
+}
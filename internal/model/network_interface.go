@@ -0,0 +1,35 @@
+package model
+
+// NetworkInterfaceType is the kind of a network interface.
+type NetworkInterfaceType string
+
+const (
+	InterfaceTypeEthernet NetworkInterfaceType = "ethernet"
+	InterfaceTypeWiFi     NetworkInterfaceType = "wifi"
+	InterfaceTypeVirtual  NetworkInterfaceType = "virtual"
+	InterfaceTypeOther    NetworkInterfaceType = "other"
+)
+
+// IsValid reports whether t is one of the known interface types.
+func (t NetworkInterfaceType) IsValid() bool {
+	switch t {
+	case InterfaceTypeEthernet, InterfaceTypeWiFi, InterfaceTypeVirtual, InterfaceTypeOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// NetworkInterface is one of the network interfaces of a computer. Every computer has exactly one primary
+// interface whose addresses are exposed as Computer.IPAddress and Computer.MACAddress. MAC addresses are
+// unique across all computers.
+type NetworkInterface struct {
+	ID         int
+	ComputerID int
+	Name       string
+	MACAddress string
+	// IPAddress is optional except for the primary interface.
+	IPAddress *string
+	Type      NetworkInterfaceType
+	IsPrimary bool
+}
@@ -0,0 +1,11 @@
+package model
+
+// NotificationPreview is the notification template of an event type rendered with sample data.
+type NotificationPreview struct {
+	Event string
+	// Locale is the locale of the rendered template. It differs from the requested one if that has no template
+	// for the event type.
+	Locale  string
+	Subject string
+	Body    string
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// ComputerStatus is the lifecycle status of a computer.
+type ComputerStatus string
+
+const (
+	StatusInStock  ComputerStatus = "in_stock"
+	StatusAssigned ComputerStatus = "assigned"
+	StatusInRepair ComputerStatus = "in_repair"
+	StatusLost     ComputerStatus = "lost"
+	StatusRetired  ComputerStatus = "retired"
+)
+
+// IsValid reports whether s is one of the known statuses.
+func (s ComputerStatus) IsValid() bool {
+	switch s {
+	case StatusInStock, StatusAssigned, StatusInRepair, StatusLost, StatusRetired:
+		return true
+	default:
+		return false
+	}
+}
+
+// StatusTransition records a change of a computer's status together with who changed it and why.
+type StatusTransition struct {
+	ID         int
+	ComputerID int
+	From       ComputerStatus
+	To         ComputerStatus
+	Reason     string
+	Actor      string
+	CreatedAt  time.Time
+}
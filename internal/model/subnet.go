@@ -0,0 +1,33 @@
+package model
+
+// Subnet represents an IPv4 network of the company's address plan.
+type Subnet struct {
+	ID             int
+	Name           string
+	CIDR           string
+	Gateway        *string
+	VLAN           *int
+	ReservedRanges []IPRange
+}
+
+// IPRange is an inclusive range of IP addresses that must not be allocated automatically.
+type IPRange struct {
+	Start       string
+	End         string
+	Description *string
+}
+
+// SubnetUtilization summarizes how the host addresses of a subnet are used.
+// Every host address is counted in exactly one of Used, Allocated, Reserved and Free.
+type SubnetUtilization struct {
+	SubnetID int
+	// Total is the number of host addresses of the subnet.
+	Total int
+	// Used is the number of addresses assigned to computers.
+	Used int
+	// Allocated is the number of addresses handed out via allocation but not yet used by a computer.
+	Allocated int
+	// Reserved is the number of unused addresses that are the gateway or lie within a reserved range.
+	Reserved int
+	Free     int
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// WebhookTest is the type of the event sent to test a webhook subscription.
+const WebhookTest ComputerEventType = "webhook.test"
+
+// WebhookSubscription is an endpoint that is notified about the computer events of the given types. The payloads
+// are signed with the secret.
+type WebhookSubscription struct {
+	ID         int
+	URL        string
+	EventTypes []ComputerEventType
+	Secret     string
+	CreatedAt  time.Time
+}
+
+// Subscribes reports whether the subscription wants to receive events of the given type.
+func (s WebhookSubscription) Subscribes(eventType ComputerEventType) bool {
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WebhookDelivery records one attempt to deliver an event to a webhook subscription. StatusCode is nil if no
+// response has been received and Error describes why the attempt failed.
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int
+	EventID        *int64
+	EventType      ComputerEventType
+	Attempt        int
+	StatusCode     *int
+	Error          *string
+	Succeeded      bool
+	Duration       time.Duration
+	DeliveredAt    time.Time
+}
@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultChatTemplate renders the message posted to a chat webhook.
+const DefaultChatTemplate = `*{{.Level}}*{{with .EmployeeAbbreviation}} ({{.}}){{end}}: {{.Message}}`
+
+// ChatPayload is the request body of an incoming chat webhook as understood by Slack, Mattermost and Rocket.Chat.
+type ChatPayload struct {
+	Text string `json:"text"`
+}
+
+// ChatChannel posts notifications to the incoming webhook of a chat service.
+type ChatChannel struct {
+	url    string
+	client *http.Client
+}
+
+// NewChatChannel returns a channel posting to the given incoming webhook URL.
+func NewChatChannel(url string) *ChatChannel {
+	return &ChatChannel{
+		url:    url,
+		client: &http.Client{},
+	}
+}
+
+// Send posts the message of the notification as text.
+func (c *ChatChannel) Send(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(ChatPayload{Text: notification.Message})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send POST request to chat webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("chat webhook responded with status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatChannelPostsText(t *testing.T) {
+	var body string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer server.Close()
+
+	err := NewChatChannel(server.URL).Send(context.Background(), Notification{Level: "warning", Message: "too many computers"})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"text":"too many computers"}`, body)
+}
+
+func TestChatChannelReportsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := NewChatChannel(server.URL).Send(context.Background(), Notification{Level: "warning", Message: "too many computers"})
+
+	assert.EqualError(t, err, "chat webhook responded with status 403: invalid_token\n")
+}
@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/bdlm/log"
+)
+
+// HTTPPayload is the request body expected by the notify service.
+type HTTPPayload struct {
+	Level                string `json:"level"`
+	EmployeeAbbreviation string `json:"employeeAbbreviation"`
+	Message              string `json:"message"`
+}
+
+// HTTPChannel sends notifications to the notify service.
+type HTTPChannel struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPChannel returns a channel posting to the notify service reachable at baseURL, e.g. "http://localhost:8080".
+func NewHTTPChannel(baseURL string) *HTTPChannel {
+	return &HTTPChannel{
+		baseURL: baseURL,
+		client:  &http.Client{},
+	}
+}
+
+// Send posts the notification to /api/notify.
+func (c *HTTPChannel) Send(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(HTTPPayload{
+		Level:                notification.Level,
+		EmployeeAbbreviation: notification.EmployeeAbbreviation,
+		Message:              notification.Message,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/notify", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send POST request to /api/notify: %w", err)
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Error("failed to close response body: " + err.Error())
+		}
+	}()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("/api/notify responded with status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	log.Infof("/api/notify responded with status %d: %s", resp.StatusCode, string(bodyBytes))
+
+	return nil
+}
@@ -0,0 +1,129 @@
+// Package notify delivers notifications to the administrators over the configured channels.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"text/template"
+)
+
+// Notification is a message about a situation the administrators have to take care of.
+type Notification struct {
+	// Level classifies the notification, e.g. "warning" or "warranty".
+	Level string
+	// EmployeeAbbreviation is the employee the notification is about, if any.
+	EmployeeAbbreviation string
+	// Subject summarizes the message. Channels without a subject ignore it.
+	Subject string
+	// Message describes the situation.
+	Message string
+}
+
+// Channel delivers notifications to one destination.
+type Channel interface {
+	Send(ctx context.Context, notification Notification) error
+}
+
+// Target is a named channel together with the template that renders the message of a notification for it.
+type Target struct {
+	Name    string
+	Channel Channel
+	// Template is executed with the Notification. Nil sends the message unchanged.
+	Template *template.Template
+}
+
+// FanOut dispatches every notification to the channels selected for its level. A failing channel does not keep
+// the notification from being sent to the other ones.
+type FanOut struct {
+	targets      map[string]Target
+	defaultRoute []string
+	levelRoutes  map[string][]string
+}
+
+// NewFanOut returns a sender that dispatches notifications of the levels in levelRoutes to the named targets and
+// notifications of all other levels to the targets of defaultRoute. Every name has to belong to one of the targets.
+func NewFanOut(targets []Target, defaultRoute []string, levelRoutes map[string][]string) (*FanOut, error) {
+	f := &FanOut{
+		targets:      make(map[string]Target, len(targets)),
+		defaultRoute: defaultRoute,
+		levelRoutes:  levelRoutes,
+	}
+
+	for _, target := range targets {
+		f.targets[target.Name] = target
+	}
+
+	routes := [][]string{defaultRoute}
+	for _, route := range levelRoutes {
+		routes = append(routes, route)
+	}
+
+	for _, route := range routes {
+		for _, name := range route {
+			if _, ok := f.targets[name]; !ok {
+				return nil, fmt.Errorf("unknown notification channel %q", name)
+			}
+		}
+	}
+
+	return f, nil
+}
+
+// ErrNotDelivered is returned by FanOut.Send, joined with the errors of the channels, if none of the selected
+// channels has delivered the notification.
+var ErrNotDelivered = errors.New("the notification has not been delivered to any channel")
+
+// Send renders the notification for every channel selected for its level and sends it to all of them concurrently.
+// It returns the joined errors of the channels that failed and, if all of them failed, ErrNotDelivered.
+func (f *FanOut) Send(ctx context.Context, notification Notification) error {
+	route, ok := f.levelRoutes[notification.Level]
+	if !ok {
+		route = f.defaultRoute
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	for _, name := range route {
+		target := f.targets[name]
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := target.send(ctx, notification); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("channel %s: %w", target.Name, err))
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(errs) > 0 && len(errs) == len(route) {
+		errs = append([]error{ErrNotDelivered}, errs...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (t Target) send(ctx context.Context, notification Notification) error {
+	if t.Template != nil {
+		var message bytes.Buffer
+		if err := t.Template.Execute(&message, notification); err != nil {
+			return fmt.Errorf("failed to render template: %w", err)
+		}
+
+		notification.Message = message.String()
+	}
+
+	return t.Channel.Send(ctx, notification)
+}
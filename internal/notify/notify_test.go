@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChannel records the notifications sent to it.
+type fakeChannel struct {
+	mu            sync.Mutex
+	notifications []Notification
+	err           error
+}
+
+func (c *fakeChannel) Send(ctx context.Context, notification Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.notifications = append(c.notifications, notification)
+
+	return c.err
+}
+
+func TestFanOutRoutesByLevel(t *testing.T) {
+	notifyService := &fakeChannel{}
+	mail := &fakeChannel{}
+	chat := &fakeChannel{}
+
+	fanOut, err := NewFanOut([]Target{
+		{Name: "notify", Channel: notifyService},
+		{Name: "smtp", Channel: mail},
+		{Name: "chat", Channel: chat},
+	}, []string{"notify"}, map[string][]string{
+		"warranty": {"smtp", "chat"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, fanOut.Send(context.Background(), Notification{Level: "warning", Message: "too many computers"}))
+	require.NoError(t, fanOut.Send(context.Background(), Notification{Level: "warranty", Message: "warranty ends"}))
+
+	assert.Equal(t, []Notification{{Level: "warning", Message: "too many computers"}}, notifyService.notifications)
+	assert.Equal(t, []Notification{{Level: "warranty", Message: "warranty ends"}}, mail.notifications)
+	assert.Equal(t, []Notification{{Level: "warranty", Message: "warranty ends"}}, chat.notifications)
+}
+
+func TestFanOutRendersTemplatePerChannel(t *testing.T) {
+	plain := &fakeChannel{}
+	chat := &fakeChannel{}
+
+	fanOut, err := NewFanOut([]Target{
+		{Name: "notify", Channel: plain},
+		{Name: "chat", Channel: chat, Template: template.Must(template.New("chat").Parse(DefaultChatTemplate))},
+	}, []string{"notify", "chat"}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, fanOut.Send(context.Background(), Notification{Level: "warning", EmployeeAbbreviation: "EMP", Message: "too many computers"}))
+
+	require.Len(t, plain.notifications, 1)
+	assert.Equal(t, "too many computers", plain.notifications[0].Message)
+	require.Len(t, chat.notifications, 1)
+	assert.Equal(t, "*warning* (EMP): too many computers", chat.notifications[0].Message)
+}
+
+func TestFanOutSendsToRemainingChannelsIfOneFails(t *testing.T) {
+	failing := &fakeChannel{err: errors.New("connection refused")}
+	working := &fakeChannel{}
+
+	fanOut, err := NewFanOut([]Target{
+		{Name: "smtp", Channel: failing},
+		{Name: "chat", Channel: working},
+	}, []string{"smtp", "chat"}, nil)
+	require.NoError(t, err)
+
+	err = fanOut.Send(context.Background(), Notification{Level: "warning", Message: "too many computers"})
+
+	assert.EqualError(t, err, "channel smtp: connection refused")
+	assert.NotErrorIs(t, err, ErrNotDelivered)
+	assert.Len(t, working.notifications, 1)
+}
+
+func TestFanOutReportsNotificationDeliveredToNoChannel(t *testing.T) {
+	fanOut, err := NewFanOut([]Target{
+		{Name: "smtp", Channel: &fakeChannel{err: errors.New("connection refused")}},
+		{Name: "chat", Channel: &fakeChannel{err: errors.New("bad gateway")}},
+	}, []string{"smtp", "chat"}, nil)
+	require.NoError(t, err)
+
+	err = fanOut.Send(context.Background(), Notification{Level: "warning", Message: "too many computers"})
+
+	assert.ErrorIs(t, err, ErrNotDelivered)
+}
+
+func TestNewFanOutRejectsUnknownChannel(t *testing.T) {
+	_, err := NewFanOut([]Target{{Name: "notify", Channel: &fakeChannel{}}}, []string{"notify"}, map[string][]string{
+		"warranty": {"pager"},
+	})
+
+	assert.EqualError(t, err, `unknown notification channel "pager"`)
+}
@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// DefaultSMTPTemplate renders the body of a notification email.
+const DefaultSMTPTemplate = `{{.Message}}
+{{with .EmployeeAbbreviation}}
+Employee: {{.}}{{end}}
+Level: {{.Level}}
+`
+
+// SMTPChannel sends notifications as plain text emails. The connection is upgraded with STARTTLS if the server
+// supports it.
+type SMTPChannel struct {
+	addr string
+	host string
+	from string
+	to   []string
+	auth smtp.Auth
+	now  func() time.Time
+}
+
+// NewSMTPChannel returns a channel sending emails from the given address to the recipients via the server at addr,
+// e.g. "mail.example.com:587". A nil auth sends the emails without authentication.
+func NewSMTPChannel(addr, from string, to []string, auth smtp.Auth) (*SMTPChannel, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP address %q: %w", addr, err)
+	}
+
+	if len(to) == 0 {
+		return nil, errors.New("missing recipients")
+	}
+
+	return &SMTPChannel{
+		addr: addr,
+		host: host,
+		from: from,
+		to:   to,
+		auth: auth,
+		now:  time.Now,
+	}, nil
+}
+
+// Send delivers the notification as email with its message as body.
+func (c *SMTPChannel) Send(ctx context.Context, notification Notification) error {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return fmt.Errorf("failed to set deadline: %w", err)
+		}
+	}
+
+	client, err := smtp.NewClient(conn, c.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to greet SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: c.host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	if c.auth != nil {
+		if err := client.Auth(c.auth); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	if err := client.Mail(c.from); err != nil {
+		return fmt.Errorf("failed to set sender: %w", err)
+	}
+
+	for _, recipient := range c.to {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("failed to add recipient %s: %w", recipient, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to start data: %w", err)
+	}
+
+	if _, err := w.Write(c.message(notification)); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// message builds the email with the headers and the message of the notification as CRLF separated body.
+func (c *SMTPChannel) message(notification Notification) []byte {
+	subject := notification.Subject
+	if subject == "" {
+		subject = fmt.Sprintf("[%s] Computers management notification", notification.Level)
+	}
+
+	var msg bytes.Buffer
+
+	fmt.Fprintf(&msg, "From: %s\r\n", c.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(c.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", c.now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	msg.WriteString("\r\n")
+
+	body := strings.ReplaceAll(notification.Message, "\r\n", "\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return msg.Bytes()
+}
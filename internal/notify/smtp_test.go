@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpStub is a minimal SMTP server accepting a single email.
+type smtpStub struct {
+	listener net.Listener
+	commands []string
+	data     string
+	done     chan struct{}
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	stub := &smtpStub{listener: listener, done: make(chan struct{})}
+	go stub.serve()
+
+	t.Cleanup(func() { listener.Close() })
+
+	return stub
+}
+
+func (s *smtpStub) serve() {
+	defer close(s.done)
+
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP stub")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		command := strings.TrimRight(line, "\r\n")
+		s.commands = append(s.commands, command)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(command, "AUTH"):
+			reply("235 2.7.0 Authentication successful")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+
+			var data strings.Builder
+
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil || line == ".\r\n" {
+					break
+				}
+
+				data.WriteString(line)
+			}
+
+			s.data = data.String()
+			reply("250 2.0.0 OK")
+		case command == "QUIT":
+			reply("221 2.0.0 Bye")
+			return
+		default:
+			reply("250 2.0.0 OK")
+		}
+	}
+}
+
+func TestSMTPChannelSendsEmail(t *testing.T) {
+	stub := newSMTPStub(t)
+	addr := stub.listener.Addr().String()
+	host, _, _ := net.SplitHostPort(addr)
+
+	channel, err := NewSMTPChannel(addr, "cmdb@example.com", []string{"admin@example.com", "it@example.com"},
+		smtp.PlainAuth("", "cmdb", "secret", host))
+	require.NoError(t, err)
+
+	channel.now = func() time.Time { return time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC) }
+
+	err = channel.Send(context.Background(), Notification{Level: "warning", Message: "too many computers\nEmployee: EMP"})
+	require.NoError(t, err)
+
+	<-stub.done
+
+	assert.Equal(t, []string{
+		"EHLO localhost",
+		"AUTH PLAIN AGNtZGIAc2VjcmV0",
+		"MAIL FROM:<cmdb@example.com>",
+		"RCPT TO:<admin@example.com>",
+		"RCPT TO:<it@example.com>",
+		"DATA",
+		"QUIT",
+	}, stub.commands)
+
+	assert.Equal(t, "From: cmdb@example.com\r\n"+
+		"To: admin@example.com, it@example.com\r\n"+
+		"Subject: [warning] Computers management notification\r\n"+
+		"Date: Mon, 10 Mar 2025 08:00:00 +0000\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=utf-8\r\n"+
+		"Content-Transfer-Encoding: 8bit\r\n"+
+		"\r\n"+
+		"too many computers\r\n"+
+		"Employee: EMP\r\n", stub.data)
+}
+
+func TestNewSMTPChannelRejectsInvalidConfiguration(t *testing.T) {
+	_, err := NewSMTPChannel("mail.example.com", "cmdb@example.com", []string{"admin@example.com"}, nil)
+	assert.ErrorContains(t, err, `invalid SMTP address "mail.example.com"`)
+
+	_, err = NewSMTPChannel("mail.example.com:587", "cmdb@example.com", nil, nil)
+	assert.EqualError(t, err, "missing recipients")
+}
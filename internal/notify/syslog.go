@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultSyslogTemplate renders the message of a syslog entry.
+const DefaultSyslogTemplate = `level={{.Level}}{{with .EmployeeAbbreviation}} employee={{.}}{{end}} {{.Message}}`
+
+const (
+	// syslogFacility is the facility "daemon" of RFC 5424.
+	syslogFacility = 3
+	// syslogNotice is the severity of notifications whose level has no own severity.
+	syslogNotice = 5
+)
+
+// syslogSeverities maps notification levels to syslog severities.
+var syslogSeverities = map[string]int{
+	"warning":  4,
+	"resolved": 6,
+}
+
+// SyslogChannel writes notifications as RFC 5424 messages to a syslog server. Messages sent over TCP are framed
+// by octet counting (RFC 6587).
+type SyslogChannel struct {
+	network  string
+	addr     string
+	tag      string
+	hostname string
+	now      func() time.Time
+}
+
+// NewSyslogChannel returns a channel writing to the syslog server at addr over "udp" or "tcp". The tag is sent as
+// the application name.
+func NewSyslogChannel(network, addr, tag string) (*SyslogChannel, error) {
+	if network != "udp" && network != "tcp" {
+		return nil, fmt.Errorf("invalid syslog network %q: must be udp or tcp", network)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	return &SyslogChannel{
+		network:  network,
+		addr:     addr,
+		tag:      tag,
+		hostname: hostname,
+		now:      time.Now,
+	}, nil
+}
+
+// Send writes the message of the notification with the severity of its level.
+func (c *SyslogChannel) Send(ctx context.Context, notification Notification) error {
+	conn, err := (&net.Dialer{}).DialContext(ctx, c.network, c.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog server: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("failed to set deadline: %w", err)
+		}
+	}
+
+	msg := c.message(notification)
+	if c.network == "tcp" {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+
+	if _, err := conn.Write([]byte(msg)); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+
+	return nil
+}
+
+// message formats the notification as "<PRI>1 TIMESTAMP HOSTNAME APP-NAME - - - MSG".
+func (c *SyslogChannel) message(notification Notification) string {
+	severity, ok := syslogSeverities[notification.Level]
+	if !ok {
+		severity = syslogNotice
+	}
+
+	text := strings.ReplaceAll(notification.Message, "\n", " ")
+
+	return fmt.Sprintf("<%d>1 %s %s %s - - - %s", syslogFacility*8+severity,
+		c.now().UTC().Format(time.RFC3339), c.hostname, c.tag, text)
+}
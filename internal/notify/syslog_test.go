@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSyslogChannel(t *testing.T, network, addr string) *SyslogChannel {
+	channel, err := NewSyslogChannel(network, addr, "computers-management")
+	require.NoError(t, err)
+
+	channel.hostname = "host"
+	channel.now = func() time.Time { return time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC) }
+
+	return channel
+}
+
+func TestSyslogChannelWritesUDPMessage(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	channel := newTestSyslogChannel(t, "udp", conn.LocalAddr().String())
+
+	err = channel.Send(context.Background(), Notification{Level: "warning", Message: "too many\ncomputers"})
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, "<28>1 2025-03-10T08:00:00Z host computers-management - - - too many computers", string(buf[:n]))
+}
+
+func TestSyslogChannelFramesTCPMessage(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		msg, _ := io.ReadAll(conn)
+		received <- string(msg)
+	}()
+
+	channel := newTestSyslogChannel(t, "tcp", listener.Addr().String())
+
+	err = channel.Send(context.Background(), Notification{Level: "warranty", Message: "warranty ends"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "72 <29>1 2025-03-10T08:00:00Z host computers-management - - - warranty ends", <-received)
+}
+
+func TestNewSyslogChannelRejectsUnknownNetwork(t *testing.T) {
+	_, err := NewSyslogChannel("unix", "/dev/log", "computers-management")
+
+	assert.EqualError(t, err, `invalid syslog network "unix": must be udp or tcp`)
+}
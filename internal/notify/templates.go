@@ -0,0 +1,195 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Event types of the notifications rendered by Templates.
+const (
+	EventAssignmentThreshold = "assignment_threshold"
+	EventAssignmentResolved  = "assignment_resolved"
+	EventIPConflict          = "ip_conflict"
+	EventWarrantyExpiry      = "warranty_expiry"
+)
+
+// Events lists the event types every set of templates has to provide in the default locale.
+var Events = []string{EventAssignmentThreshold, EventAssignmentResolved, EventIPConflict, EventWarrantyExpiry}
+
+// DefaultLocale is the locale whose templates are used if another locale lacks the template of an event type.
+const DefaultLocale = "en"
+
+// ErrTemplateNotFound is returned when rendering an unknown event type.
+var ErrTemplateNotFound = errors.New("notification template not found")
+
+// TemplateData holds the variables of the notification templates. Which of them are set depends on the event type.
+type TemplateData struct {
+	// Employee is the abbreviation of the employee the notification is about.
+	Employee string
+	// Count is the number of computers actively assigned to the employee.
+	Count int
+	// Threshold is the number of actively assigned computers from which on an employee is warned.
+	Threshold int
+	// Computers are the names of the computers assigned to the employee or, for IP conflicts, using the address.
+	Computers []string
+	IPAddress string
+	// ComputerID, Computer, SerialNumber and WarrantyEnd describe the computer whose warranty is about to end.
+	ComputerID   int
+	Computer     string
+	SerialNumber string
+	WarrantyEnd  string
+}
+
+// RenderedTemplate is the subject and body of a notification rendered in Locale.
+type RenderedTemplate struct {
+	Locale  string
+	Subject string
+	Body    string
+}
+
+// Templates renders the subject and body of notifications per event type and locale.
+type Templates struct {
+	// templates maps a locale to the templates of its event types.
+	templates map[string]map[string]*template.Template
+}
+
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// LoadTemplates parses the templates of fsys, which holds a directory per locale with a file per event type, e.g.
+// "de/ip_conflict.tmpl". Each file defines the templates "subject" and "body". LoadTemplates fails if a file does
+// not belong to a known event type, lacks one of the templates or cannot be rendered with the sample data of its
+// event type, and if the default locale lacks an event type.
+func LoadTemplates(fsys fs.FS) (*Templates, error) {
+	files, err := fs.Glob(fsys, "*/*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification templates: %w", err)
+	}
+
+	t := &Templates{templates: make(map[string]map[string]*template.Template)}
+
+	for _, file := range files {
+		locale := path.Dir(file)
+		event := strings.TrimSuffix(path.Base(file), ".tmpl")
+
+		if !slices.Contains(Events, event) {
+			return nil, fmt.Errorf("invalid notification template %s: unknown event type %q", file, event)
+		}
+
+		tmpl, err := template.New(event).Funcs(templateFuncs).ParseFS(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notification template %s: %w", file, err)
+		}
+
+		if err := validateTemplate(tmpl, event); err != nil {
+			return nil, fmt.Errorf("invalid notification template %s: %w", file, err)
+		}
+
+		if t.templates[locale] == nil {
+			t.templates[locale] = make(map[string]*template.Template)
+		}
+
+		t.templates[locale][event] = tmpl
+	}
+
+	for _, event := range Events {
+		if t.templates[DefaultLocale][event] == nil {
+			return nil, fmt.Errorf("missing notification template %s/%s.tmpl", DefaultLocale, event)
+		}
+	}
+
+	return t, nil
+}
+
+// validateTemplate checks that the template defines a subject and a body and that both can be rendered.
+func validateTemplate(tmpl *template.Template, event string) error {
+	for _, name := range []string{"subject", "body"} {
+		if tmpl.Lookup(name) == nil {
+			return fmt.Errorf("missing template %q", name)
+		}
+	}
+
+	_, err := render(tmpl, SampleData(event))
+
+	return err
+}
+
+// HasLocale reports whether there are templates for the locale.
+func (t *Templates) HasLocale(locale string) bool {
+	return t.templates[locale] != nil
+}
+
+// Locales returns the locales with templates in alphabetical order.
+func (t *Templates) Locales() []string {
+	locales := make([]string, 0, len(t.templates))
+	for locale := range t.templates {
+		locales = append(locales, locale)
+	}
+
+	slices.Sort(locales)
+
+	return locales
+}
+
+// Render renders the template of the event in the locale. If the locale has no template for the event, the one of
+// the default locale is used.
+func (t *Templates) Render(event, locale string, data TemplateData) (RenderedTemplate, error) {
+	tmpl, ok := t.templates[locale][event]
+	if !ok {
+		locale = DefaultLocale
+
+		tmpl, ok = t.templates[locale][event]
+		if !ok {
+			return RenderedTemplate{}, fmt.Errorf("%w: %s", ErrTemplateNotFound, event)
+		}
+	}
+
+	rendered, err := render(tmpl, data)
+	if err != nil {
+		return RenderedTemplate{}, err
+	}
+
+	rendered.Locale = locale
+
+	return rendered, nil
+}
+
+func render(tmpl *template.Template, data TemplateData) (RenderedTemplate, error) {
+	var subject, body bytes.Buffer
+
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return RenderedTemplate{}, fmt.Errorf("failed to render subject: %w", err)
+	}
+
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return RenderedTemplate{}, fmt.Errorf("failed to render body: %w", err)
+	}
+
+	return RenderedTemplate{
+		Subject: strings.TrimSpace(subject.String()),
+		Body:    strings.TrimSpace(body.String()),
+	}, nil
+}
+
+// SampleData returns the variables used to validate and preview the templates of the event type.
+func SampleData(event string) TemplateData {
+	switch event {
+	case EventAssignmentThreshold:
+		return TemplateData{Employee: "EMP", Count: 3, Threshold: 3, Computers: []string{"PC-01", "PC-02", "PC-03"}}
+	case EventAssignmentResolved:
+		return TemplateData{Employee: "EMP", Count: 2, Threshold: 3, Computers: []string{"PC-01", "PC-02"}}
+	case EventIPConflict:
+		return TemplateData{IPAddress: "192.168.1.10", Computers: []string{"PC-01", "PC-02"}}
+	case EventWarrantyExpiry:
+		return TemplateData{Employee: "EMP", ComputerID: 1, Computer: "PC-01", SerialNumber: "SN-12345", WarrantyEnd: "2025-03-31"}
+	default:
+		return TemplateData{}
+	}
+}
@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"testing"
+	"testing/fstest"
+	"uhuaha/computers-management/templates/notifications"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// defaultLocaleFiles returns valid templates of every event type in the default locale.
+func defaultLocaleFiles() fstest.MapFS {
+	files := fstest.MapFS{}
+	for _, event := range Events {
+		files["en/"+event+".tmpl"] = &fstest.MapFile{Data: []byte(`{{define "subject"}}Subject{{end}}{{define "body"}}Body{{end}}`)}
+	}
+
+	return files
+}
+
+func TestLoadTemplatesShippedWithTheBinary(t *testing.T) {
+	templates, err := LoadTemplates(notifications.FS)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"de", "en"}, templates.Locales())
+}
+
+func TestLoadTemplatesValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		// wantErr is contained in the error.
+		wantErr string
+	}{
+		{
+			name:    "unknown event type",
+			file:    "en/computer_stolen.tmpl",
+			content: `{{define "subject"}}Subject{{end}}{{define "body"}}Body{{end}}`,
+			wantErr: `invalid notification template en/computer_stolen.tmpl: unknown event type "computer_stolen"`,
+		},
+		{
+			name:    "syntax error",
+			file:    "de/ip_conflict.tmpl",
+			content: `{{define "subject"}}{{.IPAddress{{end}}`,
+			wantErr: "invalid notification template de/ip_conflict.tmpl: template: ip_conflict.tmpl:1:",
+		},
+		{
+			name:    "missing body",
+			file:    "de/ip_conflict.tmpl",
+			content: `{{define "subject"}}Subject{{end}}`,
+			wantErr: `invalid notification template de/ip_conflict.tmpl: missing template "body"`,
+		},
+		{
+			name:    "unknown variable",
+			file:    "de/ip_conflict.tmpl",
+			content: `{{define "subject"}}Subject{{end}}{{define "body"}}{{.Address}}{{end}}`,
+			wantErr: "can't evaluate field Address in type notify.TemplateData",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := defaultLocaleFiles()
+			files[tt.file] = &fstest.MapFile{Data: []byte(tt.content)}
+
+			_, err := LoadTemplates(files)
+
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadTemplatesRequiresDefaultLocale(t *testing.T) {
+	files := defaultLocaleFiles()
+	delete(files, "en/warranty_expiry.tmpl")
+
+	_, err := LoadTemplates(files)
+
+	assert.EqualError(t, err, "missing notification template en/warranty_expiry.tmpl")
+}
+
+func TestRenderTemplate(t *testing.T) {
+	templates, err := LoadTemplates(notifications.FS)
+	require.NoError(t, err)
+
+	data := TemplateData{Employee: "EMP", Count: 4, Threshold: 3, Computers: []string{"PC-01", "PC-02", "PC-03", "PC-04"}}
+
+	tests := []struct {
+		name     string
+		locale   string
+		rendered RenderedTemplate
+	}{
+		{
+			name:   "english",
+			locale: "en",
+			rendered: RenderedTemplate{
+				Locale:  "en",
+				Subject: "EMP has 4 computers assigned",
+				Body:    "There are 4 computers assigned to the employee EMP, which reaches the threshold of 3: PC-01, PC-02, PC-03, PC-04.",
+			},
+		},
+		{
+			name:   "german",
+			locale: "de",
+			rendered: RenderedTemplate{
+				Locale:  "de",
+				Subject: "EMP sind 4 Computer zugewiesen",
+				Body:    "Dem Mitarbeiter EMP sind 4 Computer zugewiesen, womit der Schwellenwert von 3 erreicht ist: PC-01, PC-02, PC-03, PC-04.",
+			},
+		},
+		{
+			name:   "unknown locale falls back to the default locale",
+			locale: "fr",
+			rendered: RenderedTemplate{
+				Locale:  "en",
+				Subject: "EMP has 4 computers assigned",
+				Body:    "There are 4 computers assigned to the employee EMP, which reaches the threshold of 3: PC-01, PC-02, PC-03, PC-04.",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := templates.Render(EventAssignmentThreshold, tt.locale, data)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.rendered, rendered)
+		})
+	}
+}
+
+func TestRenderUnknownEvent(t *testing.T) {
+	templates, err := LoadTemplates(notifications.FS)
+	require.NoError(t, err)
+
+	_, err = templates.Render("computer_stolen", "en", TemplateData{})
+
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+}
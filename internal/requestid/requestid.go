@@ -0,0 +1,17 @@
+// Package requestid carries the ID of the current request so that it can be reported in error responses and logs.
+package requestid
+
+import "context"
+
+type requestIDKey struct{}
+
+// NewContext returns a copy of ctx carrying the request ID.
+func NewContext(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// FromContext returns the request ID of ctx or an empty string if it has none.
+func FromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
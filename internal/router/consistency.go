@@ -0,0 +1,60 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"uhuaha/computers-management/internal/consistency"
+)
+
+const (
+	// readYourWritesHeader lets a client request that its reads are served by the primary database.
+	readYourWritesHeader = "X-Read-Your-Writes"
+	// lastWriteCookie holds the Unix time of the last write request of a client.
+	lastWriteCookie = "last_write"
+	// readYourWritesWindow is the time after a write during which the client reads from the primary database
+	// since a replica may not have caught up yet.
+	readYourWritesWindow = 10 * time.Second
+)
+
+// readYourWrites marks a request with consistency.WithReadYourWrites if it sends the X-Read-Your-Writes header
+// or if the same client has written within the readYourWritesWindow. Every write request sets the last_write
+// cookie to track the latter.
+func readYourWrites(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.SetCookie(w, &http.Cookie{
+				Name:     lastWriteCookie,
+				Value:    strconv.FormatInt(now.Unix(), 10),
+				Path:     "/",
+				MaxAge:   int(readYourWritesWindow.Seconds()),
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+		} else if mustReadYourWrites(r, now) {
+			r = r.WithContext(consistency.WithReadYourWrites(r.Context()))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func mustReadYourWrites(r *http.Request, now time.Time) bool {
+	if header, err := strconv.ParseBool(r.Header.Get(readYourWritesHeader)); err == nil && header {
+		return true
+	}
+
+	cookie, err := r.Cookie(lastWriteCookie)
+	if err != nil {
+		return false
+	}
+
+	lastWrite, err := strconv.ParseInt(cookie.Value, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return now.Sub(time.Unix(lastWrite, 0)) <= readYourWritesWindow
+}
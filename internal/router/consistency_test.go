@@ -0,0 +1,87 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/consistency"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadYourWrites(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		header         string
+		lastWrite      *time.Time
+		readYourWrites bool
+		setsCookie     bool
+	}{
+		{
+			name:           "read without header and cookie",
+			method:         http.MethodGet,
+			readYourWrites: false,
+		},
+		{
+			name:           "read with header",
+			method:         http.MethodGet,
+			header:         "true",
+			readYourWrites: true,
+		},
+		{
+			name:           "read shortly after a write",
+			method:         http.MethodGet,
+			lastWrite:      toPointer(time.Now().Add(-2 * time.Second)),
+			readYourWrites: true,
+		},
+		{
+			name:           "read long after a write",
+			method:         http.MethodGet,
+			lastWrite:      toPointer(time.Now().Add(-time.Minute)),
+			readYourWrites: false,
+		},
+		{
+			name:       "write",
+			method:     http.MethodPost,
+			setsCookie: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var primary bool
+			handler := readYourWrites(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				primary = consistency.ReadYourWrites(r.Context())
+			}))
+
+			req := httptest.NewRequest(tt.method, "/computers", nil)
+			if tt.header != "" {
+				req.Header.Set(readYourWritesHeader, tt.header)
+			}
+
+			if tt.lastWrite != nil {
+				req.AddCookie(&http.Cookie{Name: lastWriteCookie, Value: strconv.FormatInt(tt.lastWrite.Unix(), 10)})
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.readYourWrites, primary)
+
+			cookies := rec.Result().Cookies()
+			if tt.setsCookie {
+				assert.Len(t, cookies, 1)
+				assert.Equal(t, lastWriteCookie, cookies[0].Name)
+			} else {
+				assert.Empty(t, cookies)
+			}
+		})
+	}
+}
+
+func toPointer[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,53 @@
+package router
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"uhuaha/computers-management/internal/requestid"
+)
+
+const (
+	// requestIDHeader carries the ID of a request. It is taken over from the client or a proxy if present and
+	// valid and returned in the response.
+	requestIDHeader = "X-Request-ID"
+	// maxRequestIDLength limits the length of request IDs taken over from the request.
+	maxRequestIDLength = 128
+)
+
+// requestID attaches an ID to every request via requestid.NewContext and returns it in the X-Request-ID header.
+func requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(requestIDHeader, id)
+
+		next.ServeHTTP(w, r.WithContext(requestid.NewContext(r.Context(), id)))
+	})
+}
+
+// validRequestID reports whether id is short enough and consists of printable ASCII characters only, so that it
+// can be logged and returned safely.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
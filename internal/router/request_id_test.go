@@ -0,0 +1,65 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uhuaha/computers-management/internal/requestid"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		expectedID  string
+		generatesID bool
+	}{
+		{
+			name:        "missing header generates an ID",
+			generatesID: true,
+		},
+		{
+			name:       "valid header is taken over",
+			header:     "abc-123",
+			expectedID: "abc-123",
+		},
+		{
+			name:        "header with whitespace is replaced",
+			header:      "abc 123",
+			generatesID: true,
+		},
+		{
+			name:        "too long header is replaced",
+			header:      strings.Repeat("a", maxRequestIDLength+1),
+			generatesID: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var id string
+			handler := requestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				id = requestid.FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/computers", nil)
+			if tt.header != "" {
+				req.Header.Set(requestIDHeader, tt.header)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, id, rec.Header().Get(requestIDHeader))
+
+			if tt.generatesID {
+				assert.Len(t, id, 32)
+			} else {
+				assert.Equal(t, tt.expectedID, id)
+			}
+		})
+	}
+}
@@ -15,19 +15,106 @@ type Handler interface {
 	UpdateComputer(w http.ResponseWriter, r *http.Request)
 	GetComputersByEmployee(w http.ResponseWriter, r *http.Request)
 	DeleteComputer(w http.ResponseWriter, r *http.Request)
+	GetIPConflicts(w http.ResponseWriter, r *http.Request)
+	SearchComputers(w http.ResponseWriter, r *http.Request)
+	TransitionComputerStatus(w http.ResponseWriter, r *http.Request)
+	GetStatusTransitions(w http.ResponseWriter, r *http.Request)
+	AddNetworkInterface(w http.ResponseWriter, r *http.Request)
+	GetNetworkInterfaces(w http.ResponseWriter, r *http.Request)
+	GetNetworkInterfaceByID(w http.ResponseWriter, r *http.Request)
+	UpdateNetworkInterface(w http.ResponseWriter, r *http.Request)
+	DeleteNetworkInterface(w http.ResponseWriter, r *http.Request)
+	CheckOutComputer(w http.ResponseWriter, r *http.Request)
+	CheckInComputer(w http.ResponseWriter, r *http.Request)
+	GetAssignmentsByEmployee(w http.ResponseWriter, r *http.Request)
+	BatchUpdateComputers(w http.ResponseWriter, r *http.Request)
+	BatchDeleteComputers(w http.ResponseWriter, r *http.Request)
+}
+
+type SubnetHandler interface {
+	AddSubnet(w http.ResponseWriter, r *http.Request)
+	GetSubnetByID(w http.ResponseWriter, r *http.Request)
+	GetAllSubnets(w http.ResponseWriter, r *http.Request)
+	UpdateSubnet(w http.ResponseWriter, r *http.Request)
+	DeleteSubnet(w http.ResponseWriter, r *http.Request)
+	GetSubnetUtilization(w http.ResponseWriter, r *http.Request)
+	AllocateIPAddress(w http.ResponseWriter, r *http.Request)
+	ReleaseIPAddress(w http.ResponseWriter, r *http.Request)
+}
+
+type WarrantyHandler interface {
+	CheckWarranties(w http.ResponseWriter, r *http.Request)
+}
+
+type EventHandler interface {
+	StreamComputerEvents(w http.ResponseWriter, r *http.Request)
+}
+
+type WebhookHandler interface {
+	AddWebhookSubscription(w http.ResponseWriter, r *http.Request)
+	GetWebhookSubscriptionByID(w http.ResponseWriter, r *http.Request)
+	GetAllWebhookSubscriptions(w http.ResponseWriter, r *http.Request)
+	UpdateWebhookSubscription(w http.ResponseWriter, r *http.Request)
+	DeleteWebhookSubscription(w http.ResponseWriter, r *http.Request)
+	GetWebhookDeliveries(w http.ResponseWriter, r *http.Request)
+	TestWebhookSubscription(w http.ResponseWriter, r *http.Request)
+}
+
+type NotificationHandler interface {
+	PreviewNotificationTemplate(w http.ResponseWriter, r *http.Request)
 }
 
 // New creates and returns a new Gorilla Mux router configured with all
 // routes for the computer management service.
-func New(handler Handler) *mux.Router {
+func New(handler Handler, subnetHandler SubnetHandler, warrantyHandler WarrantyHandler, eventHandler EventHandler,
+	webhookHandler WebhookHandler, notificationHandler NotificationHandler) *mux.Router {
 	router := mux.NewRouter()
+	router.Use(requestID, readYourWrites)
 
 	router.HandleFunc("/computers", handler.AddComputer).Methods("POST")
+	// Registered before "/computers/{computerID}" which would otherwise match "conflicts" and "search" as an ID.
+	router.HandleFunc("/computers/conflicts", handler.GetIPConflicts).Methods("GET")
+	router.HandleFunc("/computers/search", handler.SearchComputers).Methods("GET")
+	router.HandleFunc("/computers:batchUpdate", handler.BatchUpdateComputers).Methods("POST")
+	router.HandleFunc("/computers:batchDelete", handler.BatchDeleteComputers).Methods("POST")
 	router.HandleFunc("/computers/{computerID}", handler.GetComputerByID).Methods("GET")
 	router.HandleFunc("/computers", handler.GetAllComputers).Methods("GET")
 	router.HandleFunc("/computers/{computerID}", handler.UpdateComputer).Methods("PUT")
 	router.HandleFunc("/employees/{employee}/computers", handler.GetComputersByEmployee).Methods("GET")
 	router.HandleFunc("/computers/{computerID}", handler.DeleteComputer).Methods("DELETE")
+	router.HandleFunc("/computers/{computerID}/transitions", handler.TransitionComputerStatus).Methods("POST")
+	router.HandleFunc("/computers/{computerID}/transitions", handler.GetStatusTransitions).Methods("GET")
+	router.HandleFunc("/computers/{computerID}/checkout", handler.CheckOutComputer).Methods("POST")
+	router.HandleFunc("/computers/{computerID}/checkin", handler.CheckInComputer).Methods("POST")
+	router.HandleFunc("/employees/{employee}/assignments", handler.GetAssignmentsByEmployee).Methods("GET")
+	router.HandleFunc("/computers/{computerID}/interfaces", handler.AddNetworkInterface).Methods("POST")
+	router.HandleFunc("/computers/{computerID}/interfaces", handler.GetNetworkInterfaces).Methods("GET")
+	router.HandleFunc("/computers/{computerID}/interfaces/{interfaceID}", handler.GetNetworkInterfaceByID).Methods("GET")
+	router.HandleFunc("/computers/{computerID}/interfaces/{interfaceID}", handler.UpdateNetworkInterface).Methods("PUT")
+	router.HandleFunc("/computers/{computerID}/interfaces/{interfaceID}", handler.DeleteNetworkInterface).Methods("DELETE")
+
+	router.HandleFunc("/subnets", subnetHandler.AddSubnet).Methods("POST")
+	router.HandleFunc("/subnets/{subnetID}", subnetHandler.GetSubnetByID).Methods("GET")
+	router.HandleFunc("/subnets", subnetHandler.GetAllSubnets).Methods("GET")
+	router.HandleFunc("/subnets/{subnetID}", subnetHandler.UpdateSubnet).Methods("PUT")
+	router.HandleFunc("/subnets/{subnetID}", subnetHandler.DeleteSubnet).Methods("DELETE")
+	router.HandleFunc("/subnets/{subnetID}/utilization", subnetHandler.GetSubnetUtilization).Methods("GET")
+	router.HandleFunc("/subnets/{subnetID}/allocate", subnetHandler.AllocateIPAddress).Methods("POST")
+	router.HandleFunc("/subnets/{subnetID}/allocations/{ipAddress}", subnetHandler.ReleaseIPAddress).Methods("DELETE")
+
+	router.HandleFunc("/jobs/warranty-check", warrantyHandler.CheckWarranties).Methods("POST")
+
+	router.HandleFunc("/events", eventHandler.StreamComputerEvents).Methods("GET")
+
+	router.HandleFunc("/webhooks", webhookHandler.AddWebhookSubscription).Methods("POST")
+	router.HandleFunc("/webhooks", webhookHandler.GetAllWebhookSubscriptions).Methods("GET")
+	router.HandleFunc("/webhooks/{webhookID}", webhookHandler.GetWebhookSubscriptionByID).Methods("GET")
+	router.HandleFunc("/webhooks/{webhookID}", webhookHandler.UpdateWebhookSubscription).Methods("PUT")
+	router.HandleFunc("/webhooks/{webhookID}", webhookHandler.DeleteWebhookSubscription).Methods("DELETE")
+	router.HandleFunc("/webhooks/{webhookID}/deliveries", webhookHandler.GetWebhookDeliveries).Methods("GET")
+	router.HandleFunc("/webhooks/{webhookID}/test", webhookHandler.TestWebhookSubscription).Methods("POST")
+
+	router.HandleFunc("/notifications/templates/{event}/preview", notificationHandler.PreviewNotificationTemplate).Methods("GET")
 
 	return router
 }
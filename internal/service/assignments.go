@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/model"
+	"uhuaha/computers-management/internal/notify"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/bdlm/log"
+)
+
+const (
+	// assignmentThreshold is the number of actively assigned computers from which on the system administrator is notified.
+	assignmentThreshold = 3
+	// defaultNotificationCooldown is the time within which an employee is not warned again about the threshold
+	// unless the number of their computers has increased.
+	defaultNotificationCooldown = 24 * time.Hour
+	// updateActor is recorded as the initiator of the check-outs and check-ins by which UpdateComputer and
+	// BatchUpdateComputers change employees, since their requests name no actor.
+	updateActor = "api"
+)
+
+// thresholdNotification is a notification about the 3-computer threshold that is sent once the transaction has
+// been committed. It either warns about an employee who reached the threshold or resolves the warning of an
+// employee who dropped back under it.
+type thresholdNotification struct {
+	count    model.AssignmentCount
+	resolved bool
+}
+
+// CheckOutComputer hands out an in stock computer to an employee. It starts a new assignment and changes the
+// computer's status to assigned, recording actor as the initiator of the transition. It returns an invalid
+// transition error if the computer cannot be assigned from its current status.
+func (s *ComputerMgmtService) CheckOutComputer(computerID int, employee, actor string) (model.Assignment, error) {
+	var (
+		assignmentDBO dbo.Assignment
+		notifications []thresholdNotification
+	)
+
+	err := s.withTx(func(tx *ComputerMgmtService) error {
+		computer, err := tx.GetComputer(computerID)
+		if err != nil {
+			return err
+		}
+
+		assignmentDBO, err = tx.checkOut(computer, employee, actor)
+		if err != nil {
+			return err
+		}
+
+		notifications, err = tx.evaluateAssignmentThreshold(employee)
+
+		return err
+	})
+	if err != nil {
+		return model.Assignment{}, fmt.Errorf("failed to check out computer with ID=%d: %w", computerID, err)
+	}
+
+	s.sendThresholdNotifications(notifications)
+
+	return convertAssignmentDBOToModel(assignmentDBO), nil
+}
+
+// CheckInComputer takes back an assigned computer. It ends its active assignment, removes the employee and changes
+// the computer's status to in stock, recording actor as the initiator of the transition. It returns a conflict
+// error if the computer is not assigned. If the employee drops back under the 3-computer threshold, the system
+// administrator is notified that the warning is resolved.
+func (s *ComputerMgmtService) CheckInComputer(computerID int, actor string) (model.Assignment, error) {
+	var (
+		assignmentDBO dbo.Assignment
+		notifications []thresholdNotification
+	)
+
+	err := s.withTx(func(tx *ComputerMgmtService) error {
+		computer, err := tx.GetComputer(computerID)
+		if err != nil {
+			return err
+		}
+
+		assignmentDBO, err = tx.checkIn(computer, actor)
+		if err != nil {
+			return err
+		}
+
+		if computer.EmployeeAbbreviation != nil {
+			notifications, err = tx.evaluateAssignmentThreshold(*computer.EmployeeAbbreviation)
+		}
+
+		return err
+	})
+	if err != nil {
+		return model.Assignment{}, fmt.Errorf("failed to check in computer with ID=%d: %w", computerID, err)
+	}
+
+	s.sendThresholdNotifications(notifications)
+
+	return convertAssignmentDBOToModel(assignmentDBO), nil
+}
+
+// checkOut assigns the computer to employee and starts a new assignment. It has to be called within withTx and
+// returns an invalid transition error if the computer cannot be assigned from its current status.
+func (s *ComputerMgmtService) checkOut(computer model.Computer, employee, actor string) (dbo.Assignment, error) {
+	if !canTransition(computer.Status, model.StatusAssigned) {
+		return dbo.Assignment{}, errs.NewInvalidTransition(
+			fmt.Sprintf("computer with ID=%d cannot be checked out while its status is %q", computer.ID, computer.Status),
+			statusNames(allowedStatuses(computer.Status)),
+		)
+	}
+
+	transition := model.StatusTransition{
+		ComputerID: computer.ID,
+		From:       computer.Status,
+		To:         model.StatusAssigned,
+		Reason:     fmt.Sprintf("Checked out to %s", employee),
+		Actor:      actor,
+	}
+
+	assignmentDBO, err := s.repository.CheckOutComputer(
+		convertAssignmentModelToDBO(model.Assignment{ComputerID: computer.ID, EmployeeAbbreviation: employee}),
+		convertStatusTransitionModelToDBO(transition),
+	)
+	if err != nil {
+		return dbo.Assignment{}, err
+	}
+
+	s.recordEvent(model.ComputerAssigned, computer.ID, &employee)
+
+	return assignmentDBO, nil
+}
+
+// checkIn takes the computer back from its employee and ends its active assignment. It has to be called within
+// withTx and returns a conflict error if the computer is not assigned.
+func (s *ComputerMgmtService) checkIn(computer model.Computer, actor string) (dbo.Assignment, error) {
+	if computer.Status != model.StatusAssigned {
+		return dbo.Assignment{}, errs.NewConflict(
+			fmt.Sprintf("computer with ID=%d cannot be checked in while its status is %q", computer.ID, computer.Status),
+		)
+	}
+
+	var employee string
+	if computer.EmployeeAbbreviation != nil {
+		employee = *computer.EmployeeAbbreviation
+	}
+
+	transition := model.StatusTransition{
+		ComputerID: computer.ID,
+		From:       computer.Status,
+		To:         model.StatusInStock,
+		Reason:     fmt.Sprintf("Checked in from %s", employee),
+		Actor:      actor,
+	}
+
+	assignmentDBO, err := s.repository.CheckInComputer(convertStatusTransitionModelToDBO(transition))
+	if err != nil {
+		return dbo.Assignment{}, err
+	}
+
+	s.recordEvent(model.ComputerUpdated, computer.ID, computer.EmployeeAbbreviation)
+
+	return assignmentDBO, nil
+}
+
+// reassignComputer changes the employee of the computer to employee by checking it in from its current employee and
+// checking it out to the new one, recording actor as the initiator of both transitions. It has to be called within
+// withTx and returns the previous and new employees whose 3-computer threshold has changed, or nil if the employee
+// is unchanged.
+func (s *ComputerMgmtService) reassignComputer(computer model.Computer, employee *string, actor string) ([]string, error) {
+	if sameEmployee(computer.EmployeeAbbreviation, employee) {
+		return nil, nil
+	}
+
+	var employees []string
+
+	if computer.Status == model.StatusAssigned {
+		if _, err := s.checkIn(computer, actor); err != nil {
+			return nil, err
+		}
+
+		if computer.EmployeeAbbreviation != nil {
+			employees = appendEmployee(employees, *computer.EmployeeAbbreviation)
+		}
+
+		computer.Status = model.StatusInStock
+		computer.EmployeeAbbreviation = nil
+	}
+
+	if employee != nil {
+		if _, err := s.checkOut(computer, *employee, actor); err != nil {
+			return nil, err
+		}
+
+		employees = appendEmployee(employees, *employee)
+	}
+
+	return employees, nil
+}
+
+// sameEmployee reports whether a and b denote the same employee or both no employee.
+func sameEmployee(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// GetAssignmentsByEmployee returns the current and past assignments of an employee ordered from newest to oldest.
+func (s *ComputerMgmtService) GetAssignmentsByEmployee(employee string) ([]model.Assignment, error) {
+	assignmentDBOs, err := s.repository.GetAssignmentsByEmployee(employee)
+	if err != nil {
+		return []model.Assignment{}, fmt.Errorf("failed to get assignments for employee %q: %w", employee, err)
+	}
+
+	assignments := make([]model.Assignment, len(assignmentDBOs))
+	for i, dbo := range assignmentDBOs {
+		assignments[i] = convertAssignmentDBOToModel(dbo)
+	}
+
+	return assignments, nil
+}
+
+// evaluateAssignmentThreshold evaluates the 3-computer threshold for each of the employees and returns the
+// notifications that are due. An employee who reached the threshold is warned unless they have already been warned
+// within the notification cool-down about at least as many computers. An employee who has been warned and dropped
+// back under the threshold gets the warning resolved. Past assignments are not taken into account.
+func (s *ComputerMgmtService) evaluateAssignmentThreshold(employees ...string) ([]thresholdNotification, error) {
+	var notifications []thresholdNotification
+
+	for _, employee := range employees {
+		count, err := s.repository.CountComputersByEmployee(employee)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count computers of employee %s: %w", employee, err)
+		}
+
+		resolved := count < assignmentThreshold
+
+		var due bool
+		if resolved {
+			due, err = s.repository.ResolveAssignmentWarning(employee)
+		} else {
+			due, err = s.repository.ClaimAssignmentWarning(employee, count, s.notificationCooldown)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate the warning of employee %s: %w", employee, err)
+		}
+
+		if !due {
+			continue
+		}
+
+		computers, err := s.repository.GetComputersByEmployee(context.Background(), employee)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get computers of employee %s: %w", employee, err)
+		}
+
+		names := make([]string, len(computers))
+		for i, computer := range computers {
+			names[i] = computer.Name
+		}
+
+		notifications = append(notifications, thresholdNotification{
+			count: model.AssignmentCount{
+				EmployeeAbbreviation: employee,
+				Count:                count,
+				Threshold:            assignmentThreshold,
+				ComputerNames:        names,
+			},
+			resolved: resolved,
+		})
+	}
+
+	return notifications, nil
+}
+
+// sendThresholdNotifications sends the notifications returned by evaluateAssignmentThreshold.
+func (s *ComputerMgmtService) sendThresholdNotifications(notifications []thresholdNotification) {
+	for _, notification := range notifications {
+		if notification.resolved {
+			go s.notifier.SendResolvedMessage(notification.count)
+		} else {
+			go s.sendWarning(notification.count)
+		}
+	}
+}
+
+// sendWarning warns about the employee and releases the claim of the warning if no channel has delivered it, so that
+// the next evaluation warns again. A warning delivered to some of the channels is kept, so that they are not warned
+// twice.
+func (s *ComputerMgmtService) sendWarning(count model.AssignmentCount) {
+	err := s.notifier.SendMessage(count)
+	if err == nil {
+		return
+	}
+
+	log.Errorf("failed to warn about the computers of employee %s: %v", count.EmployeeAbbreviation, err)
+
+	if !errors.Is(err, notify.ErrNotDelivered) {
+		return
+	}
+
+	if err := s.repository.ReleaseAssignmentWarning(count.EmployeeAbbreviation, count.Count); err != nil {
+		log.Errorf("failed to release the warning of employee %s: %v", count.EmployeeAbbreviation, err)
+	}
+}
@@ -0,0 +1,431 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/model"
+	"uhuaha/computers-management/internal/notify"
+	"uhuaha/computers-management/templates/notifications"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAssignmentRepository implements the parts of ComputerRepository used by the check-out and check-in workflow
+// and by status transitions.
+// Calling any other method panics.
+type fakeAssignmentRepository struct {
+	ComputerRepository
+
+	computer    dbo.Computer
+	assignments []dbo.Assignment
+	assignment  dbo.Assignment
+	transition  dbo.StatusTransition
+	warnings    fakeAssignmentWarnings
+	// released receives the employees whose warning has been released.
+	released chan string
+}
+
+func (r *fakeAssignmentRepository) GetComputer(computerID int) (dbo.Computer, error) {
+	if computerID != r.computer.ID {
+		return dbo.Computer{}, errs.NewNotFound("computer not found")
+	}
+
+	return r.computer, nil
+}
+
+func (r *fakeAssignmentRepository) CheckOutComputer(assignment dbo.Assignment, transition dbo.StatusTransition) (dbo.Assignment, error) {
+	r.assignment, r.transition = assignment, transition
+	return assignment, nil
+}
+
+func (r *fakeAssignmentRepository) CheckInComputer(transition dbo.StatusTransition) (dbo.Assignment, error) {
+	r.transition = transition
+	return r.assignment, nil
+}
+
+func (r *fakeAssignmentRepository) AddStatusTransition(transition dbo.StatusTransition) (dbo.StatusTransition, error) {
+	r.transition = transition
+	return transition, nil
+}
+
+func (r *fakeAssignmentRepository) WithTx(ctx context.Context, fn func(repo ComputerRepository) error) error {
+	return fn(r)
+}
+
+func (r *fakeAssignmentRepository) CountComputersByEmployee(employee string) (int, error) {
+	count := 0
+	for _, assignment := range r.assignments {
+		if !assignment.EndedAt.Valid {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *fakeAssignmentRepository) GetComputersByEmployee(ctx context.Context, employee string) ([]dbo.Computer, error) {
+	return []dbo.Computer{r.computer}, nil
+}
+
+func (r *fakeAssignmentRepository) ClaimAssignmentWarning(employee string, count int, cooldown time.Duration) (bool, error) {
+	return r.warnings.claim(employee, count, cooldown), nil
+}
+
+func (r *fakeAssignmentRepository) ResolveAssignmentWarning(employee string) (bool, error) {
+	return r.warnings.resolve(employee), nil
+}
+
+func (r *fakeAssignmentRepository) ReleaseAssignmentWarning(employee string, count int) error {
+	r.warnings.release(employee, count)
+	r.released <- employee
+
+	return nil
+}
+
+// fakeAssignmentWarnings keeps the warnings about the 3-computer threshold in memory like the assignment_warnings
+// table does. The current time is fixed to now.
+type fakeAssignmentWarnings struct {
+	warnings map[string]fakeAssignmentWarning
+	now      time.Time
+}
+
+type fakeAssignmentWarning struct {
+	count      int
+	notifiedAt time.Time
+}
+
+func (w *fakeAssignmentWarnings) claim(employee string, count int, cooldown time.Duration) bool {
+	if w.warnings == nil {
+		w.warnings = make(map[string]fakeAssignmentWarning)
+	}
+
+	warning, ok := w.warnings[employee]
+	if ok && warning.count >= count && w.now.Sub(warning.notifiedAt) < cooldown {
+		return false
+	}
+
+	w.warnings[employee] = fakeAssignmentWarning{count: count, notifiedAt: w.now}
+
+	return true
+}
+
+func (w *fakeAssignmentWarnings) release(employee string, count int) {
+	if w.warnings[employee].count == count {
+		delete(w.warnings, employee)
+	}
+}
+
+func (w *fakeAssignmentWarnings) resolve(employee string) bool {
+	_, ok := w.warnings[employee]
+	delete(w.warnings, employee)
+
+	return ok
+}
+
+type fakeMessageSender struct {
+	messages chan string
+	resolved chan string
+	// err is returned by SendMessage.
+	err error
+}
+
+func (n *fakeMessageSender) SendMessage(count model.AssignmentCount) error {
+	n.messages <- count.EmployeeAbbreviation
+	return n.err
+}
+
+func (n *fakeMessageSender) SendResolvedMessage(count model.AssignmentCount) {
+	if n.resolved != nil {
+		n.resolved <- count.EmployeeAbbreviation
+	}
+}
+
+func (n *fakeMessageSender) SendIPConflictMessage(ipAddress string, computers []model.Computer) {}
+
+func TestCheckOutComputer(t *testing.T) {
+	repo := &fakeAssignmentRepository{computer: dbo.Computer{ID: 1, Status: "in_stock"}}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{})
+
+	assignment, err := s.CheckOutComputer(1, "EMP", "ADM")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, assignment.ComputerID)
+	assert.Equal(t, "EMP", assignment.EmployeeAbbreviation)
+	assert.True(t, assignment.IsActive())
+	assert.Equal(t, dbo.StatusTransition{
+		ComputerID: 1,
+		FromStatus: "in_stock",
+		ToStatus:   "assigned",
+		Reason:     "Checked out to EMP",
+		Actor:      "ADM",
+	}, repo.transition)
+}
+
+func TestCheckOutComputerNotifiesOnActiveAssignmentsOnly(t *testing.T) {
+	ended := sql.NullTime{Time: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	tests := []struct {
+		name        string
+		assignments []dbo.Assignment
+		notified    bool
+	}{
+		{
+			name:        "three active assignments",
+			assignments: []dbo.Assignment{{ID: 1}, {ID: 2}, {ID: 3}},
+			notified:    true,
+		},
+		{
+			name:        "two active and one past assignment",
+			assignments: []dbo.Assignment{{ID: 1}, {ID: 2}, {ID: 3, EndedAt: ended}},
+			notified:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAssignmentRepository{
+				computer:    dbo.Computer{ID: 1, Status: "in_stock"},
+				assignments: tt.assignments,
+			}
+			notifier := &fakeMessageSender{messages: make(chan string, 1)}
+			s := NewComputerMgmtService(repo, notifier)
+
+			_, err := s.CheckOutComputer(1, "EMP", "ADM")
+			require.NoError(t, err)
+
+			select {
+			case employee := <-notifier.messages:
+				assert.True(t, tt.notified, "unexpected notification")
+				assert.Equal(t, "EMP", employee)
+			case <-time.After(50 * time.Millisecond):
+				assert.False(t, tt.notified, "missing notification")
+			}
+		})
+	}
+}
+
+func TestCheckOutComputerReleasesUndeliveredWarning(t *testing.T) {
+	repo := &fakeAssignmentRepository{
+		computer:    dbo.Computer{ID: 1, Status: "in_stock"},
+		assignments: []dbo.Assignment{{ID: 1}, {ID: 2}, {ID: 3}},
+		released:    make(chan string, 1),
+	}
+	notifier := &fakeMessageSender{messages: make(chan string, 2), err: errors.Join(notify.ErrNotDelivered, errors.New("notify service unavailable"))}
+	s := NewComputerMgmtService(repo, notifier)
+
+	_, err := s.CheckOutComputer(1, "EMP", "ADM")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"EMP"}, receiveMessages(notifier.messages, 1))
+
+	select {
+	case employee := <-repo.released:
+		assert.Equal(t, "EMP", employee)
+	case <-time.After(time.Second):
+		require.Fail(t, "the warning has not been released")
+	}
+
+	// The next evaluation warns again although the cool-down has not passed.
+	notifier.err = nil
+
+	_, err = s.CheckOutComputer(1, "EMP", "ADM")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"EMP"}, receiveMessages(notifier.messages, 1))
+}
+
+// fakeChannel records the notifications sent to it and fails with err.
+type fakeChannel struct {
+	sent chan notify.Notification
+	err  error
+}
+
+func (c *fakeChannel) Send(ctx context.Context, notification notify.Notification) error {
+	c.sent <- notification
+	return c.err
+}
+
+func TestCheckOutComputerKeepsPartiallyDeliveredWarning(t *testing.T) {
+	repo := &fakeAssignmentRepository{
+		computer:    dbo.Computer{ID: 1, Status: "in_stock"},
+		assignments: []dbo.Assignment{{ID: 1}, {ID: 2}, {ID: 3}},
+		released:    make(chan string, 1),
+	}
+
+	failing := &fakeChannel{sent: make(chan notify.Notification, 1), err: errors.New("connection refused")}
+	working := &fakeChannel{sent: make(chan notify.Notification, 1)}
+
+	fanOut, err := notify.NewFanOut([]notify.Target{
+		{Name: "smtp", Channel: failing},
+		{Name: "chat", Channel: working},
+	}, []string{"smtp", "chat"}, nil)
+	require.NoError(t, err)
+
+	templates, err := notify.LoadTemplates(notifications.FS)
+	require.NoError(t, err)
+
+	s := NewComputerMgmtService(repo, NewNotifier(fanOut, templates, "en"))
+
+	_, err = s.CheckOutComputer(1, "EMP", "ADM")
+	require.NoError(t, err)
+
+	for _, channel := range []*fakeChannel{failing, working} {
+		select {
+		case <-channel.sent:
+		case <-time.After(time.Second):
+			require.Fail(t, "the warning has not been sent to every channel")
+		}
+	}
+
+	// The chat channel has received the warning, so it must not be released and sent to it again.
+	select {
+	case employee := <-repo.released:
+		assert.Fail(t, "the warning has been released", employee)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestCheckOutComputerRejectsInvalidStatus(t *testing.T) {
+	repo := &fakeAssignmentRepository{computer: dbo.Computer{ID: 1, Status: "in_repair"}}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{})
+
+	_, err := s.CheckOutComputer(1, "EMP", "ADM")
+
+	var invalid *errs.InvalidTransitionError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, []string{"in_stock", "lost", "retired"}, invalid.Allowed)
+}
+
+func TestCheckInComputer(t *testing.T) {
+	employee := sql.NullString{String: "EMP", Valid: true}
+	repo := &fakeAssignmentRepository{computer: dbo.Computer{ID: 1, Status: "assigned", EmployeeAbbreviation: employee}}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{})
+
+	_, err := s.CheckInComputer(1, "ADM")
+	require.NoError(t, err)
+
+	assert.Equal(t, dbo.StatusTransition{
+		ComputerID: 1,
+		FromStatus: "assigned",
+		ToStatus:   "in_stock",
+		Reason:     "Checked in from EMP",
+		Actor:      "ADM",
+	}, repo.transition)
+}
+
+func TestCheckInComputerRejectsUnassignedComputer(t *testing.T) {
+	repo := &fakeAssignmentRepository{computer: dbo.Computer{ID: 1, Status: "in_stock"}}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{})
+
+	_, err := s.CheckInComputer(1, "ADM")
+
+	var conflict *errs.ConflictError
+	assert.ErrorAs(t, err, &conflict)
+}
+
+func TestCheckInComputerResolvesWarning(t *testing.T) {
+	employee := sql.NullString{String: "EMP", Valid: true}
+	repo := &fakeAssignmentRepository{
+		computer:    dbo.Computer{ID: 1, Status: "assigned", EmployeeAbbreviation: employee},
+		assignments: []dbo.Assignment{{ID: 1}, {ID: 2}},
+		warnings:    fakeAssignmentWarnings{warnings: map[string]fakeAssignmentWarning{"EMP": {count: 3}}},
+	}
+	notifier := &fakeMessageSender{resolved: make(chan string, 1)}
+	s := NewComputerMgmtService(repo, notifier)
+
+	_, err := s.CheckInComputer(1, "ADM")
+	require.NoError(t, err)
+
+	select {
+	case resolved := <-notifier.resolved:
+		assert.Equal(t, "EMP", resolved)
+	case <-time.After(time.Second):
+		assert.Fail(t, "missing resolved notification")
+	}
+
+	assert.Empty(t, repo.warnings.warnings)
+}
+
+func TestEvaluateAssignmentThreshold(t *testing.T) {
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	active := func(n int) []dbo.Assignment { return make([]dbo.Assignment, n) }
+	count := func(n int) model.AssignmentCount {
+		return model.AssignmentCount{EmployeeAbbreviation: "EMP", Count: n, Threshold: 3, ComputerNames: []string{"PC1"}}
+	}
+
+	tests := []struct {
+		name          string
+		assignments   []dbo.Assignment
+		warnings      map[string]fakeAssignmentWarning
+		notifications []thresholdNotification
+		remaining     map[string]fakeAssignmentWarning
+	}{
+		{
+			name:          "first warning",
+			assignments:   active(3),
+			notifications: []thresholdNotification{{count: count(3)}},
+			remaining:     map[string]fakeAssignmentWarning{"EMP": {count: 3, notifiedAt: now}},
+		},
+		{
+			name:        "same count within the cool-down is suppressed",
+			assignments: active(3),
+			warnings:    map[string]fakeAssignmentWarning{"EMP": {count: 3, notifiedAt: now.Add(-time.Hour)}},
+			remaining:   map[string]fakeAssignmentWarning{"EMP": {count: 3, notifiedAt: now.Add(-time.Hour)}},
+		},
+		{
+			name:        "lower count within the cool-down is suppressed",
+			assignments: active(3),
+			warnings:    map[string]fakeAssignmentWarning{"EMP": {count: 4, notifiedAt: now.Add(-time.Hour)}},
+			remaining:   map[string]fakeAssignmentWarning{"EMP": {count: 4, notifiedAt: now.Add(-time.Hour)}},
+		},
+		{
+			name:          "increased count within the cool-down",
+			assignments:   active(4),
+			warnings:      map[string]fakeAssignmentWarning{"EMP": {count: 3, notifiedAt: now.Add(-time.Hour)}},
+			notifications: []thresholdNotification{{count: count(4)}},
+			remaining:     map[string]fakeAssignmentWarning{"EMP": {count: 4, notifiedAt: now}},
+		},
+		{
+			name:          "same count after the cool-down",
+			assignments:   active(3),
+			warnings:      map[string]fakeAssignmentWarning{"EMP": {count: 3, notifiedAt: now.Add(-24 * time.Hour)}},
+			notifications: []thresholdNotification{{count: count(3)}},
+			remaining:     map[string]fakeAssignmentWarning{"EMP": {count: 3, notifiedAt: now}},
+		},
+		{
+			name:          "dropped under the threshold after a warning",
+			assignments:   active(2),
+			warnings:      map[string]fakeAssignmentWarning{"EMP": {count: 3, notifiedAt: now.Add(-time.Hour)}},
+			notifications: []thresholdNotification{{count: count(2), resolved: true}},
+			remaining:     map[string]fakeAssignmentWarning{},
+		},
+		{
+			name:        "under the threshold without a warning",
+			assignments: active(2),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAssignmentRepository{
+				computer:    dbo.Computer{ID: 1, Name: "PC1"},
+				assignments: tt.assignments,
+				warnings:    fakeAssignmentWarnings{warnings: tt.warnings, now: now},
+			}
+			s := NewComputerMgmtService(repo, &fakeMessageSender{}, WithNotificationCooldown(24*time.Hour))
+
+			notifications, err := s.evaluateAssignmentThreshold("EMP")
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.notifications, notifications)
+			assert.Equal(t, tt.remaining, repo.warnings.warnings)
+		})
+	}
+}
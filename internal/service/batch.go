@@ -0,0 +1,258 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/bdlm/log"
+)
+
+// BatchUpdateComputers sets the non-nil fields of changes on all selected computers within one transaction and
+// returns the updated computers. A new employee can only be set if every selected computer already belongs to them
+// or is in stock or assigned; it is set by checking each computer in from its previous employee and out to the new one.
+// With dryRun nothing is stored and the computers are returned as they would look after the update. Otherwise the
+// 3-computer threshold is evaluated once for every affected employee, i.e. the new and all previous ones.
+func (s *ComputerMgmtService) BatchUpdateComputers(selector model.ComputerSelector, changes model.ComputerChanges, dryRun bool) ([]model.Computer, error) {
+	var (
+		updated       []model.Computer
+		notifications []thresholdNotification
+	)
+
+	err := s.withTx(func(tx *ComputerMgmtService) error {
+		computers, err := tx.selectComputers(selector)
+		if err != nil {
+			return err
+		}
+
+		if changes.EmployeeAbbreviation != nil {
+			var unassignable []string
+			for _, computer := range computers {
+				if !isAssignable(computer, *changes.EmployeeAbbreviation) {
+					unassignable = append(unassignable, fmt.Sprintf("%d (%s)", computer.ID, computer.Status))
+				}
+			}
+
+			if len(unassignable) > 0 {
+				return errs.NewConflict(fmt.Sprintf("computers cannot be assigned to an employee: %s", strings.Join(unassignable, ", ")))
+			}
+		}
+
+		if dryRun {
+			for i := range computers {
+				applyComputerChanges(&computers[i], changes)
+			}
+
+			updated = computers
+
+			return nil
+		}
+
+		if len(computers) == 0 {
+			updated = computers
+			return nil
+		}
+
+		computerIDs := computerIDsOf(computers)
+		if err := tx.repository.UpdateComputers(computerIDs, convertComputerChangesModelToDBO(changes)); err != nil {
+			return err
+		}
+
+		var employees []string
+
+		for _, computer := range computers {
+			var reassigned []string
+			if changes.EmployeeAbbreviation != nil {
+				reassigned, err = tx.reassignComputer(computer, changes.EmployeeAbbreviation, updateActor)
+				if err != nil {
+					return err
+				}
+			}
+
+			// A reassigned computer has already been recorded by the check-in or check-out.
+			if len(reassigned) == 0 {
+				tx.recordEvent(model.ComputerUpdated, computer.ID, computer.EmployeeAbbreviation)
+			}
+
+			for _, employee := range reassigned {
+				employees = appendEmployee(employees, employee)
+			}
+		}
+
+		notifications, err = tx.evaluateAffectedEmployees("batch update", len(computers), employees)
+		if err != nil {
+			return err
+		}
+
+		updated, err = tx.selectComputers(model.ComputerSelector{IDs: computerIDs})
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update computers: %w", err)
+	}
+
+	s.sendThresholdNotifications(notifications)
+
+	return updated, nil
+}
+
+// BatchDeleteComputers removes all selected computers within one transaction and returns them. With dryRun nothing
+// is deleted. Otherwise the 3-computer threshold is evaluated once for every employee who lost a computer.
+func (s *ComputerMgmtService) BatchDeleteComputers(selector model.ComputerSelector, dryRun bool) ([]model.Computer, error) {
+	var (
+		computers     []model.Computer
+		notifications []thresholdNotification
+	)
+
+	err := s.withTx(func(tx *ComputerMgmtService) error {
+		var err error
+
+		computers, err = tx.selectComputers(selector)
+		if err != nil {
+			return err
+		}
+
+		if dryRun || len(computers) == 0 {
+			return nil
+		}
+
+		if err := tx.repository.DeleteComputers(computerIDsOf(computers)); err != nil {
+			return err
+		}
+
+		for _, computer := range computers {
+			tx.recordEvent(model.ComputerDeleted, computer.ID, computer.EmployeeAbbreviation)
+		}
+
+		notifications, err = tx.evaluateAffectedEmployees("batch delete", len(computers), affectedEmployees(computers))
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete computers: %w", err)
+	}
+
+	s.sendThresholdNotifications(notifications)
+
+	return computers, nil
+}
+
+// selectComputers returns the selected computers. It returns a not found error if some of the selected IDs
+// do not exist and a validation error if the filter matches more than model.MaxBatchSize computers.
+func (s *ComputerMgmtService) selectComputers(selector model.ComputerSelector) ([]model.Computer, error) {
+	selectorDBO := convertComputerSelectorModelToDBO(selector)
+	selectorDBO.Limit = model.MaxBatchSize + 1
+
+	computerDBOs, err := s.repository.GetComputersBySelector(selectorDBO)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(computerDBOs) > model.MaxBatchSize {
+		return nil, errs.NewValidation(fmt.Sprintf("the filter matches more than %d computers", model.MaxBatchSize))
+	}
+
+	computers := make([]model.Computer, len(computerDBOs))
+	for i, dbo := range computerDBOs {
+		computers[i] = convertComputerDBOToModel(dbo)
+	}
+
+	if len(selector.IDs) > 0 {
+		found := computerIDsOf(computers)
+
+		var missing []string
+		for _, id := range selector.IDs {
+			if !slices.Contains(found, id) {
+				missing = append(missing, fmt.Sprint(id))
+			}
+		}
+
+		if len(missing) > 0 {
+			return nil, errs.NewNotFound(fmt.Sprintf("computers not found: %s", strings.Join(missing, ", ")))
+		}
+	}
+
+	return computers, nil
+}
+
+// evaluateAffectedEmployees logs the batch operation and evaluates the 3-computer threshold once per employee.
+// It returns the notifications that are due so that they can be sent once the transaction is committed.
+func (s *ComputerMgmtService) evaluateAffectedEmployees(operation string, count int, employees []string) ([]thresholdNotification, error) {
+	for _, employee := range employees {
+		log.Infof("%s of %d computers affected employee %s", operation, count, employee)
+	}
+
+	return s.evaluateAssignmentThreshold(employees...)
+}
+
+// isAssignable reports whether the computer may be assigned to the employee, i.e. whether it already belongs to
+// them or is in stock or assigned.
+func isAssignable(computer model.Computer, employee string) bool {
+	if sameEmployee(computer.EmployeeAbbreviation, &employee) {
+		return true
+	}
+
+	return computer.Status == model.StatusInStock || computer.Status == model.StatusAssigned
+}
+
+// applyComputerChanges sets the non-nil fields of changes on the computer. A computer that gets a new employee is
+// shown as assigned.
+func applyComputerChanges(computer *model.Computer, changes model.ComputerChanges) {
+	if changes.EmployeeAbbreviation != nil {
+		computer.Status = model.StatusAssigned
+	}
+
+	fields := []struct {
+		value *string
+		dest  **string
+	}{
+		{changes.EmployeeAbbreviation, &computer.EmployeeAbbreviation},
+		{changes.Description, &computer.Description},
+		{changes.Vendor, &computer.Vendor},
+		{changes.Model, &computer.Model},
+		{changes.OperatingSystem, &computer.OperatingSystem},
+		{changes.Location, &computer.Location},
+	}
+
+	for _, field := range fields {
+		if field.value != nil {
+			value := *field.value
+			*field.dest = &value
+		}
+	}
+}
+
+func computerIDsOf(computers []model.Computer) []int {
+	ids := make([]int, len(computers))
+	for i, computer := range computers {
+		ids[i] = computer.ID
+	}
+
+	return ids
+}
+
+// affectedEmployees returns the distinct employees of the given computers in alphabetical order.
+func affectedEmployees(computers []model.Computer) []string {
+	var employees []string
+	for _, computer := range computers {
+		if computer.EmployeeAbbreviation != nil {
+			employees = appendEmployee(employees, *computer.EmployeeAbbreviation)
+		}
+	}
+
+	return employees
+}
+
+// appendEmployee inserts employee into the sorted list unless it is already contained.
+func appendEmployee(employees []string, employee string) []string {
+	i, found := slices.BinarySearch(employees, employee)
+	if found {
+		return employees
+	}
+
+	return slices.Insert(employees, i, employee)
+}
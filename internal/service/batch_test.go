@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBatchRepository implements the parts of ComputerRepository used by batch operations.
+// Calling any other method panics.
+type fakeBatchRepository struct {
+	ComputerRepository
+
+	computers   []dbo.Computer
+	updatedIDs  []int
+	changes     dbo.ComputerChanges
+	deletedIDs  []int
+	events      []dbo.ComputerEvent
+	transitions []dbo.StatusTransition
+	warnings    fakeAssignmentWarnings
+}
+
+func (r *fakeBatchRepository) GetComputersBySelector(selector dbo.ComputerSelector) ([]dbo.Computer, error) {
+	var selected []dbo.Computer
+	for _, computer := range r.computers {
+		if selector.Filter != nil || slices.Contains(selector.IDs, computer.ID) {
+			selected = append(selected, computer)
+		}
+	}
+
+	if selector.Filter != nil && selector.Limit > 0 && len(selected) > selector.Limit {
+		selected = selected[:selector.Limit]
+	}
+
+	return selected, nil
+}
+
+func (r *fakeBatchRepository) UpdateComputers(computerIDs []int, changes dbo.ComputerChanges) error {
+	r.updatedIDs, r.changes = computerIDs, changes
+	return nil
+}
+
+func (r *fakeBatchRepository) CheckOutComputer(assignment dbo.Assignment, transition dbo.StatusTransition) (dbo.Assignment, error) {
+	r.transition(transition, sql.NullString{String: assignment.EmployeeAbbreviation, Valid: true})
+	return assignment, nil
+}
+
+func (r *fakeBatchRepository) CheckInComputer(transition dbo.StatusTransition) (dbo.Assignment, error) {
+	r.transition(transition, sql.NullString{})
+	return dbo.Assignment{}, nil
+}
+
+// transition records the transition and applies it and the employee to the computer.
+func (r *fakeBatchRepository) transition(transition dbo.StatusTransition, employee sql.NullString) {
+	r.transitions = append(r.transitions, transition)
+
+	for i := range r.computers {
+		if r.computers[i].ID == transition.ComputerID {
+			r.computers[i].Status = transition.ToStatus
+			r.computers[i].EmployeeAbbreviation = employee
+		}
+	}
+}
+
+func (r *fakeBatchRepository) DeleteComputers(computerIDs []int) error {
+	r.deletedIDs = computerIDs
+	return nil
+}
+
+func (r *fakeBatchRepository) AddComputerEvents(events []dbo.ComputerEvent) ([]dbo.ComputerEvent, error) {
+	for i := range events {
+		events[i].ID = int64(len(r.events) + 1)
+		r.events = append(r.events, events[i])
+	}
+
+	return events, nil
+}
+
+func (r *fakeBatchRepository) WithTx(ctx context.Context, fn func(repo ComputerRepository) error) error {
+	return fn(r)
+}
+
+// CountComputersByEmployee reports 3 computers for every employee so that each evaluation notifies.
+func (r *fakeBatchRepository) CountComputersByEmployee(employee string) (int, error) {
+	return 3, nil
+}
+
+func (r *fakeBatchRepository) GetComputersByEmployee(ctx context.Context, employee string) ([]dbo.Computer, error) {
+	var computers []dbo.Computer
+	for _, computer := range r.computers {
+		if computer.EmployeeAbbreviation.String == employee {
+			computers = append(computers, computer)
+		}
+	}
+
+	return computers, nil
+}
+
+func (r *fakeBatchRepository) ClaimAssignmentWarning(employee string, count int, cooldown time.Duration) (bool, error) {
+	return r.warnings.claim(employee, count, cooldown), nil
+}
+
+func (r *fakeBatchRepository) ResolveAssignmentWarning(employee string) (bool, error) {
+	return r.warnings.resolve(employee), nil
+}
+
+func batchComputers() []dbo.Computer {
+	employee := func(e string) sql.NullString { return sql.NullString{String: e, Valid: true} }
+
+	return []dbo.Computer{
+		{ID: 1, Name: "PC1", Status: "assigned", EmployeeAbbreviation: employee("ABC")},
+		{ID: 2, Name: "PC2", Status: "assigned", EmployeeAbbreviation: employee("ABC")},
+		{ID: 3, Name: "PC3", Status: "in_stock"},
+		{ID: 4, Name: "PC4", Status: "in_repair", EmployeeAbbreviation: employee("XYZ")},
+	}
+}
+
+func receiveMessages(messages chan string, n int) []string {
+	var received []string
+
+	for range n {
+		select {
+		case employee := <-messages:
+			received = append(received, employee)
+		case <-time.After(time.Second):
+			return received
+		}
+	}
+
+	return received
+}
+
+func TestBatchUpdateComputers(t *testing.T) {
+	repo := &fakeBatchRepository{computers: batchComputers()}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{})
+
+	location := "Hamburg"
+	computers, err := s.BatchUpdateComputers(model.ComputerSelector{IDs: []int{1, 2, 3}}, model.ComputerChanges{Location: &location}, false)
+	require.NoError(t, err)
+
+	assert.Len(t, computers, 3)
+	assert.Equal(t, []int{1, 2, 3}, repo.updatedIDs)
+	assert.Equal(t, sql.NullString{String: "Hamburg", Valid: true}, repo.changes.Location)
+}
+
+func TestBatchUpdateComputersReassignsEmployee(t *testing.T) {
+	repo := &fakeBatchRepository{computers: batchComputers()}
+	notifier := &fakeMessageSender{messages: make(chan string, 10)}
+	s := NewComputerMgmtService(repo, notifier)
+
+	newEmployee := "NEW"
+	computers, err := s.BatchUpdateComputers(model.ComputerSelector{IDs: []int{1, 2, 3}}, model.ComputerChanges{EmployeeAbbreviation: &newEmployee}, false)
+	require.NoError(t, err)
+
+	for _, computer := range computers {
+		assert.Equal(t, &newEmployee, computer.EmployeeAbbreviation)
+		assert.Equal(t, model.StatusAssigned, computer.Status)
+	}
+
+	// PC1 and PC2 are checked in from ABC and out to NEW, PC3 is checked out to NEW.
+	var reasons []string
+	for _, transition := range repo.transitions {
+		assert.Equal(t, "api", transition.Actor)
+		reasons = append(reasons, fmt.Sprintf("%d: %s", transition.ComputerID, transition.Reason))
+	}
+
+	assert.Equal(t, []string{
+		"1: Checked in from ABC", "1: Checked out to NEW",
+		"2: Checked in from ABC", "2: Checked out to NEW",
+		"3: Checked out to NEW",
+	}, reasons)
+
+	// One evaluation for the previous owner of PC1 and PC2 and one for the new owner.
+	received := receiveMessages(notifier.messages, 2)
+	assert.ElementsMatch(t, []string{"ABC", "NEW"}, received)
+}
+
+func TestBatchUpdateComputersRejectsUnassignableComputers(t *testing.T) {
+	repo := &fakeBatchRepository{computers: batchComputers()}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{})
+
+	newEmployee := "NEW"
+	_, err := s.BatchUpdateComputers(model.ComputerSelector{IDs: []int{3, 4}}, model.ComputerChanges{EmployeeAbbreviation: &newEmployee}, false)
+
+	var conflict *errs.ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Contains(t, conflict.Error(), "4 (in_repair)")
+	assert.Nil(t, repo.updatedIDs)
+	assert.Empty(t, repo.transitions)
+}
+
+func TestBatchUpdateComputersDryRun(t *testing.T) {
+	repo := &fakeBatchRepository{computers: batchComputers()}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{})
+
+	location := "Hamburg"
+	computers, err := s.BatchUpdateComputers(model.ComputerSelector{IDs: []int{3}}, model.ComputerChanges{Location: &location}, true)
+	require.NoError(t, err)
+
+	require.Len(t, computers, 1)
+	assert.Equal(t, &location, computers[0].Location)
+	assert.Nil(t, repo.updatedIDs, "dry run must not update")
+}
+
+func TestBatchUpdateComputersRejectsUnknownIDs(t *testing.T) {
+	repo := &fakeBatchRepository{computers: batchComputers()}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{})
+
+	location := "Hamburg"
+	_, err := s.BatchUpdateComputers(model.ComputerSelector{IDs: []int{1, 8, 9}}, model.ComputerChanges{Location: &location}, false)
+
+	var nf *errs.NotFoundError
+	require.ErrorAs(t, err, &nf)
+	assert.Equal(t, "computers not found: 8, 9", nf.Error())
+	assert.Nil(t, repo.updatedIDs)
+}
+
+func TestBatchDeleteComputersRejectsTooManyComputers(t *testing.T) {
+	computers := make([]dbo.Computer, model.MaxBatchSize+1)
+	for i := range computers {
+		computers[i] = dbo.Computer{ID: i + 1, Name: fmt.Sprintf("PC%d", i+1), Status: "in_stock"}
+	}
+
+	repo := &fakeBatchRepository{computers: computers}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{})
+
+	location := "Berlin"
+	_, err := s.BatchDeleteComputers(model.ComputerSelector{Filter: &model.ComputerFilter{Location: &location}}, false)
+
+	var validation *errs.ValidationError
+	require.ErrorAs(t, err, &validation)
+	assert.Equal(t, "the filter matches more than 1000 computers", validation.Error())
+	assert.Nil(t, repo.deletedIDs)
+}
+
+func TestBatchDeleteComputers(t *testing.T) {
+	repo := &fakeBatchRepository{computers: batchComputers()}
+	notifier := &fakeMessageSender{messages: make(chan string, 10)}
+	s := NewComputerMgmtService(repo, notifier)
+
+	vendor := "Dell"
+	computers, err := s.BatchDeleteComputers(model.ComputerSelector{Filter: &model.ComputerFilter{Vendor: &vendor}}, false)
+	require.NoError(t, err)
+
+	assert.Len(t, computers, 4)
+	assert.Equal(t, []int{1, 2, 3, 4}, repo.deletedIDs)
+
+	received := receiveMessages(notifier.messages, 2)
+	assert.ElementsMatch(t, []string{"ABC", "XYZ"}, received)
+}
+
+func TestBatchDeleteComputersDryRun(t *testing.T) {
+	repo := &fakeBatchRepository{computers: batchComputers()}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{})
+
+	computers, err := s.BatchDeleteComputers(model.ComputerSelector{IDs: []int{1}}, true)
+	require.NoError(t, err)
+
+	assert.Len(t, computers, 1)
+	assert.Nil(t, repo.deletedIDs, "dry run must not delete")
+}
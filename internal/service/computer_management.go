@@ -3,62 +3,231 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"time"
+	"uhuaha/computers-management/internal/consistency"
 	"uhuaha/computers-management/internal/db/postgres/dbo"
 	"uhuaha/computers-management/internal/model"
 
-	"github.com/bdlm/log"
+	errs "uhuaha/computers-management/internal/errors"
 )
 
+// ComputerRepository stores computers together with their network interfaces, status history and assignments.
 type ComputerRepository interface {
+	// WithTx runs fn with a repository whose methods all use the same transaction. The transaction is committed
+	// if fn returns nil and rolled back otherwise.
+	WithTx(ctx context.Context, fn func(repo ComputerRepository) error) error
+	// WithSnapshot runs fn with a repository whose reads all see the same snapshot of the database, taken on the
+	// replica unless ctx has to read its own writes.
+	WithSnapshot(ctx context.Context, fn func(repo ComputerRepository) error) error
 	AddComputer(computer dbo.Computer) (int, error)
 	GetComputer(computerID int) (dbo.Computer, error)
-	GetAllComputers() ([]dbo.Computer, error)
+	GetAllComputers(ctx context.Context, filter dbo.ComputerFilter) ([]dbo.Computer, error)
 	UpdateComputer(computerID int, data dbo.Computer) error
-	GetComputersByEmployee(employee string) ([]dbo.Computer, error)
+	GetComputersByEmployee(ctx context.Context, employee string) ([]dbo.Computer, error)
+	GetComputersVersion(ctx context.Context) (dbo.ComputersVersion, error)
+	ComputerExists(ctx context.Context, computerID int) (bool, error)
+	CountComputersByEmployee(employee string) (int, error)
 	DeleteComputer(computerID int) error
+	// LockIPAddress serializes transactions checking and storing the same IP address until the end of the
+	// transaction of WithTx.
+	LockIPAddress(ipAddress string) error
+	GetComputersByIPAddress(ipAddress string) ([]dbo.Computer, error)
+	GetComputersWithDuplicateIPAddress() ([]dbo.IPAddressUsage, error)
+	// LockSubnet serializes transactions allocating addresses of the subnet until the end of the transaction of
+	// WithTx.
+	LockSubnet(subnetID int) error
+	GetSubnet(subnetID int) (dbo.Subnet, error)
+	GetUsedIPAddresses(subnetID int) ([]string, error)
+	GetAllocatedIPAddresses(subnetID int) ([]string, error)
+	SearchComputers(ctx context.Context, query string) ([]dbo.ComputerSearchResult, error)
+	AddStatusTransition(transition dbo.StatusTransition) (dbo.StatusTransition, error)
+	GetStatusTransitions(computerID int) ([]dbo.StatusTransition, error)
+	GetNetworkInterfaces(computerID int) ([]dbo.NetworkInterface, error)
+	GetNetworkInterface(computerID, interfaceID int) (dbo.NetworkInterface, error)
+	AddNetworkInterface(iface dbo.NetworkInterface) (int, error)
+	UpdateNetworkInterface(computerID, interfaceID int, data dbo.NetworkInterface) error
+	DeleteNetworkInterface(computerID, interfaceID int) error
+	CheckOutComputer(assignment dbo.Assignment, transition dbo.StatusTransition) (dbo.Assignment, error)
+	CheckInComputer(transition dbo.StatusTransition) (dbo.Assignment, error)
+	GetAssignmentsByEmployee(employee string) ([]dbo.Assignment, error)
+	GetComputersBySelector(selector dbo.ComputerSelector) ([]dbo.Computer, error)
+	UpdateComputers(computerIDs []int, changes dbo.ComputerChanges) error
+	DeleteComputers(computerIDs []int) error
+	AddComputerEvents(events []dbo.ComputerEvent) ([]dbo.ComputerEvent, error)
+	ClaimAssignmentWarning(employee string, count int, cooldown time.Duration) (bool, error)
+	ResolveAssignmentWarning(employee string) (bool, error)
+	// ReleaseAssignmentWarning reverts the claim of a warning about count computers, so that the next evaluation
+	// warns the employee again.
+	ReleaseAssignmentWarning(employee string, count int) error
 }
 
 type MessageSender interface {
-	SendMessage(employeeAbbreviation string)
+	// SendMessage warns about an employee who reached the 3-computer threshold. It returns an error if the warning
+	// could not be delivered to all channels, which wraps notify.ErrNotDelivered if no channel has delivered it.
+	SendMessage(count model.AssignmentCount) error
+	SendResolvedMessage(count model.AssignmentCount)
+	// SendIPConflictMessage warns that the computers use the same IP address.
+	SendIPConflictMessage(ipAddress string, computers []model.Computer)
 }
 
 type ComputerMgmtService struct {
-	repository ComputerRepository
-	notifier   MessageSender
+	repository       ComputerRepository
+	notifier         MessageSender
+	ipConflictPolicy IPConflictPolicy
+	publishers       []EventPublisher
+	// notificationCooldown is the time within which an employee is not warned again about the 3-computer
+	// threshold unless the number of their computers has increased.
+	notificationCooldown time.Duration
+
+	// pendingEvents collects the events recorded within withTx. It is nil outside of withTx and if no publisher
+	// is configured.
+	pendingEvents *[]model.ComputerEvent
+}
+
+// Option configures optional behaviour of the ComputerMgmtService.
+type Option func(*ComputerMgmtService)
+
+// WithNotificationCooldown sets the time within which an employee is not warned again about the 3-computer
+// threshold unless the number of their computers has increased.
+func WithNotificationCooldown(cooldown time.Duration) Option {
+	return func(s *ComputerMgmtService) {
+		s.notificationCooldown = cooldown
+	}
 }
 
-func NewComputerMgmtService(repo ComputerRepository, notifier MessageSender) *ComputerMgmtService {
-	return &ComputerMgmtService{
-		repository: repo,
-		notifier:   notifier,
+func NewComputerMgmtService(repo ComputerRepository, notifier MessageSender, opts ...Option) *ComputerMgmtService {
+	s := &ComputerMgmtService{
+		repository:           repo,
+		notifier:             notifier,
+		ipConflictPolicy:     DefaultIPConflictPolicy,
+		notificationCooldown: defaultNotificationCooldown,
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
 }
 
-// AddComputer stores a new computer and returns its generated ID. If there are 3 or more computers assigned to the same employee,
-// it sends a notification to the system administrator.
+// AddComputer stores a new computer and returns its generated ID. Its initial status is assigned if it has an employee
+// and in stock otherwise. If there are 3 or more computers actively assigned to the same employee, it sends a
+// notification to the system administrator unless it has already been sent within the cool-down. The same applies if
+// the computer's IP address is already in use, unless the IP conflict policy rejects the computer. If the IP address is
+// model.AutoIPAddress, the next free address of the computer's subnet is allocated.
 func (s *ComputerMgmtService) AddComputer(computer model.Computer) (int, error) {
-	computerDBO := convertComputerModelToDBO(computer)
+	if computer.IPAddress == model.AutoIPAddress && computer.SubnetID == nil {
+		return 0, errs.NewValidation("automatic IP address allocation requires a subnet")
+	}
+
+	var (
+		computerID    int
+		conflicting   []model.Computer
+		notifications []thresholdNotification
+	)
 
-	computerID, err := s.repository.AddComputer(computerDBO)
+	err := s.withTx(func(tx *ComputerMgmtService) error {
+		var err error
+
+		if computer.IPAddress == model.AutoIPAddress {
+			computer.IPAddress, err = tx.allocateIPAddress(*computer.SubnetID)
+			if err != nil {
+				return err
+			}
+		}
+
+		conflicting, err = tx.checkIPConflict(computer.IPAddress, 0)
+		if err != nil {
+			return err
+		}
+
+		computer.Status = initialStatus(computer)
+		computerDBO := convertComputerModelToDBO(computer)
+
+		computerID, err = tx.repository.AddComputer(computerDBO)
+		if err != nil {
+			return err
+		}
+
+		tx.recordEvent(model.ComputerCreated, computerID, computer.EmployeeAbbreviation)
+
+		if computer.EmployeeAbbreviation != nil {
+			notifications, err = tx.evaluateAssignmentThreshold(*computer.EmployeeAbbreviation)
+		}
+
+		return err
+	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to add a computer: %w", err)
 	}
 
-	// Notify system administrator if there are 3 or more computers assigned to the given employee.
-	if computer.EmployeeAbbreviation != nil {
-		computers, err := s.GetComputersByEmployee(*computer.EmployeeAbbreviation)
-		if err != nil {
-			log.Error("failed to get computers: " + err.Error())
-			return 0, fmt.Errorf("failed to get computers for employee %q: %w", *computer.EmployeeAbbreviation, err)
+	if len(conflicting) > 0 {
+		computer.ID = computerID
+		go s.notifier.SendIPConflictMessage(computer.IPAddress, append(conflicting, computer))
+	}
+
+	s.sendThresholdNotifications(notifications)
+
+	return computerID, nil
+}
+
+// allocateIPAddress returns the lowest host address of a subnet that is neither reserved, used by a computer nor
+// allocated. It has to be called within withTx: the subnet stays locked until the transaction ends, so that
+// concurrent transactions cannot obtain the same address. The address is not reserved separately since the
+// caller uses it within the same transaction.
+func (s *ComputerMgmtService) allocateIPAddress(subnetID int) (string, error) {
+	if err := s.repository.LockSubnet(subnetID); err != nil {
+		return "", fmt.Errorf("failed to lock subnet with ID=%d: %w", subnetID, err)
+	}
+
+	plan, err := loadAddressPlan(s.repository, subnetID)
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate an IP address in subnet with ID=%d: %w", subnetID, err)
+	}
+
+	ipAddress, ok := plan.nextFree()
+	if !ok {
+		return "", errs.NewConflict(fmt.Sprintf("subnet with ID=%d has no free IP address", subnetID))
+	}
+
+	return ipAddress, nil
+}
+
+// withTx runs fn with a copy of the service whose repository executes all queries within one transaction, so that
+// the steps of fn are stored atomically. Notifications are only sent once withTx has returned successfully. The
+// events recorded by fn are stored within the transaction and published after it has been committed.
+func (s *ComputerMgmtService) withTx(fn func(tx *ComputerMgmtService) error) error {
+	var events []model.ComputerEvent
+
+	// The handlers do not pass a context yet.
+	err := s.repository.WithTx(context.Background(), func(repo ComputerRepository) error {
+		tx := *s
+		tx.repository = repo
+
+		if len(s.publishers) > 0 {
+			events = nil
+			tx.pendingEvents = &events
+		}
+
+		if err := fn(&tx); err != nil {
+			return err
 		}
 
-		if len(computers) >= 3 {
-			go s.notifier.SendMessage(*computer.EmployeeAbbreviation)
+		return tx.storeEvents()
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(events) > 0 {
+		for _, publisher := range s.publishers {
+			publisher.Publish(events...)
 		}
 	}
 
-	return computerID, nil
+	return nil
 }
 
 // GetComputer retrieves a computer by its ID.
@@ -73,9 +242,9 @@ func (s *ComputerMgmtService) GetComputer(computerID int) (model.Computer, error
 	return computer, nil
 }
 
-// GetAllComputers returns a list of all computers in the system.
-func (s *ComputerMgmtService) GetAllComputers() ([]model.Computer, error) {
-	computerDBOs, err := s.repository.GetAllComputers()
+// GetAllComputers returns a list of all computers in the system that match the given filter.
+func (s *ComputerMgmtService) GetAllComputers(ctx context.Context, filter model.ComputerFilter) ([]model.Computer, error) {
+	computerDBOs, err := s.repository.GetAllComputers(ctx, dbo.ComputerFilter(filter))
 	if err != nil {
 		return []model.Computer{}, fmt.Errorf("failed to get all computers: %w", err)
 	}
@@ -88,35 +257,173 @@ func (s *ComputerMgmtService) GetAllComputers() ([]model.Computer, error) {
 	return computers, nil
 }
 
-// UpdateComputer updates the data of an existing computer identified by its ID.
+// UpdateComputer updates the data of an existing computer identified by its ID. The status is only changed along with
+// the employee: a new employee ends the current assignment by checking the computer in and starts a new one by checking
+// it out, which requires the computer to be in stock or assigned. The 3-computer threshold is evaluated for the
+// previous and the new employee.
 func (s *ComputerMgmtService) UpdateComputer(computerID int, data model.Computer) error {
-	data.ID = computerID
-	computerDBO := convertComputerModelToDBO(data)
+	var (
+		conflicting   []model.Computer
+		notifications []thresholdNotification
+	)
+
+	err := s.withTx(func(tx *ComputerMgmtService) error {
+		current, err := tx.GetComputer(computerID)
+		if err != nil {
+			return err
+		}
+
+		employees, err := tx.reassignComputer(current, data.EmployeeAbbreviation, updateActor)
+		if err != nil {
+			return err
+		}
+
+		conflicting, err = tx.checkIPConflict(data.IPAddress, computerID)
+		if err != nil {
+			return err
+		}
 
-	err := s.repository.UpdateComputer(computerID, computerDBO)
+		data.ID = computerID
+		computerDBO := convertComputerModelToDBO(data)
+
+		if err := tx.repository.UpdateComputer(computerID, computerDBO); err != nil {
+			return err
+		}
+
+		// A reassigned computer has already been recorded by the check-in or check-out.
+		if len(employees) == 0 {
+			tx.recordEvent(model.ComputerUpdated, computerID, data.EmployeeAbbreviation)
+		}
+
+		notifications, err = tx.evaluateAssignmentThreshold(employees...)
+
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to update the computer with ID=%d: %w", computerID, err)
 	}
 
-	// Notify system administrator if there are 3 or more computers assigned to the given employee.
-	if data.EmployeeAbbreviation != nil {
-		computers, err := s.GetComputersByEmployee(*data.EmployeeAbbreviation)
+	if len(conflicting) > 0 {
+		go s.notifier.SendIPConflictMessage(data.IPAddress, append(conflicting, data))
+	}
+
+	s.sendThresholdNotifications(notifications)
+
+	return nil
+}
+
+// GetComputersVersion returns the current version of all computers. It changes whenever the representation of
+// any computer may have changed.
+func (s *ComputerMgmtService) GetComputersVersion(ctx context.Context) (model.ComputersVersion, error) {
+	version, err := s.repository.GetComputersVersion(ctx)
+	if err != nil {
+		return model.ComputersVersion{}, fmt.Errorf("failed to get computers version: %w", err)
+	}
+
+	return model.ComputersVersion(version), nil
+}
+
+// GetComputerVersion returns the current version of all computers for the representation of a single computer. Like
+// GetVersionedComputer, it reads from the primary. It returns a not found error if the computer does not exist, so
+// that a conditional request for it is not answered with 304 Not Modified.
+func (s *ComputerMgmtService) GetComputerVersion(ctx context.Context, computerID int) (model.ComputersVersion, error) {
+	var version model.ComputersVersion
+
+	err := s.withSnapshot(consistency.WithReadYourWrites(ctx), func(snapshot *ComputerMgmtService) error {
+		exists, err := snapshot.repository.ComputerExists(ctx, computerID)
 		if err != nil {
-			log.Error("failed to get computers: " + err.Error())
-			return fmt.Errorf("failed to get computers for employee %q: %w", *data.EmployeeAbbreviation, err)
+			return fmt.Errorf("failed to check whether the computer with ID=%d exists: %w", computerID, err)
 		}
 
-		if len(computers) >= 3 {
-			go s.notifier.SendMessage(*data.EmployeeAbbreviation)
+		if !exists {
+			return errs.NewNotFound("computer not found")
 		}
-	}
 
-	return nil
+		version, err = snapshot.GetComputersVersion(ctx)
+
+		return err
+	})
+
+	return version, err
+}
+
+// GetVersionedComputer retrieves a computer by its ID together with the current version of all computers. Both are
+// read from the same snapshot of the primary database, so that the version describes the returned computer.
+func (s *ComputerMgmtService) GetVersionedComputer(ctx context.Context, computerID int) (model.Computer, model.ComputersVersion, error) {
+	var (
+		computer model.Computer
+		version  model.ComputersVersion
+	)
+
+	// A single computer is read from the primary, like GetComputer does.
+	err := s.withSnapshot(consistency.WithReadYourWrites(ctx), func(snapshot *ComputerMgmtService) (err error) {
+		if version, err = snapshot.GetComputersVersion(ctx); err != nil {
+			return err
+		}
+
+		computer, err = snapshot.GetComputer(computerID)
+
+		return err
+	})
+
+	return computer, version, err
+}
+
+// GetVersionedComputers returns the computers matching the filter together with the current version of all
+// computers. Both are read from the same snapshot of the database.
+func (s *ComputerMgmtService) GetVersionedComputers(ctx context.Context, filter model.ComputerFilter) ([]model.Computer, model.ComputersVersion, error) {
+	var (
+		computers []model.Computer
+		version   model.ComputersVersion
+	)
+
+	err := s.withSnapshot(ctx, func(snapshot *ComputerMgmtService) (err error) {
+		if version, err = snapshot.GetComputersVersion(ctx); err != nil {
+			return err
+		}
+
+		computers, err = snapshot.GetAllComputers(ctx, filter)
+
+		return err
+	})
+
+	return computers, version, err
+}
+
+// GetVersionedComputersByEmployee returns the computers assigned to the employee together with the current version
+// of all computers. Both are read from the same snapshot of the database.
+func (s *ComputerMgmtService) GetVersionedComputersByEmployee(ctx context.Context, employee string) ([]model.Computer, model.ComputersVersion, error) {
+	var (
+		computers []model.Computer
+		version   model.ComputersVersion
+	)
+
+	err := s.withSnapshot(ctx, func(snapshot *ComputerMgmtService) (err error) {
+		if version, err = snapshot.GetComputersVersion(ctx); err != nil {
+			return err
+		}
+
+		computers, err = snapshot.GetComputersByEmployee(ctx, employee)
+
+		return err
+	})
+
+	return computers, version, err
+}
+
+// withSnapshot runs fn with a copy of the service whose repository reads from one snapshot of the database.
+func (s *ComputerMgmtService) withSnapshot(ctx context.Context, fn func(snapshot *ComputerMgmtService) error) error {
+	return s.repository.WithSnapshot(ctx, func(repo ComputerRepository) error {
+		snapshot := *s
+		snapshot.repository = repo
+
+		return fn(&snapshot)
+	})
 }
 
 // GetComputersByEmployee retrieves all computers assigned to the specified employee.
-func (s *ComputerMgmtService) GetComputersByEmployee(employee string) ([]model.Computer, error) {
-	computerDBOs, err := s.repository.GetComputersByEmployee(employee)
+func (s *ComputerMgmtService) GetComputersByEmployee(ctx context.Context, employee string) ([]model.Computer, error) {
+	computerDBOs, err := s.repository.GetComputersByEmployee(ctx, employee)
 	if err != nil {
 		return []model.Computer{}, fmt.Errorf("failed to get computers for employee %q: %w", employee, err)
 	}
@@ -129,12 +436,35 @@ func (s *ComputerMgmtService) GetComputersByEmployee(employee string) ([]model.C
 	return computers, nil
 }
 
-// DeleteComputer removes a computer by its ID.
+// DeleteComputer removes a computer by its ID. If its employee drops back under the 3-computer threshold, the
+// system administrator is notified that the warning is resolved.
 func (s *ComputerMgmtService) DeleteComputer(computerID int) error {
-	err := s.repository.DeleteComputer(computerID)
+	var notifications []thresholdNotification
+
+	err := s.withTx(func(tx *ComputerMgmtService) error {
+		// The computer is loaded before it is deleted to report and evaluate its last employee.
+		computer, err := tx.GetComputer(computerID)
+		if err != nil {
+			return err
+		}
+
+		tx.recordEvent(model.ComputerDeleted, computerID, computer.EmployeeAbbreviation)
+
+		if err := tx.repository.DeleteComputer(computerID); err != nil {
+			return err
+		}
+
+		if computer.EmployeeAbbreviation != nil {
+			notifications, err = tx.evaluateAssignmentThreshold(*computer.EmployeeAbbreviation)
+		}
+
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to delete computer with ID=%d: %w", computerID, err)
 	}
 
+	s.sendThresholdNotifications(notifications)
+
 	return nil
 }
@@ -0,0 +1,410 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/consistency"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTxRepository stages the computers and events added within WithTx and only stores them if the transaction
+// is committed. Calling any method that is not needed to add or update a computer panics.
+type fakeTxRepository struct {
+	ComputerRepository
+
+	computers    []dbo.Computer
+	staged       []dbo.Computer
+	events       []dbo.ComputerEvent
+	stagedEvents []dbo.ComputerEvent
+	countErr     error
+	warnings     fakeAssignmentWarnings
+	// lockedIPAddresses are the IP addresses locked by LockIPAddress.
+	lockedIPAddresses []string
+	// subnet is returned by GetSubnet, its addresses used by computers by GetUsedIPAddresses.
+	subnet          dbo.Subnet
+	usedIPAddresses []string
+	lockedSubnets   []int
+	// transitions are recorded by CheckOutComputer and CheckInComputer, updated by UpdateComputer.
+	transitions []dbo.StatusTransition
+	updated     []dbo.Computer
+	committed   bool
+	rolledBack  bool
+}
+
+func (r *fakeTxRepository) WithTx(ctx context.Context, fn func(repo ComputerRepository) error) error {
+	r.staged = nil
+	r.stagedEvents = nil
+
+	if err := fn(r); err != nil {
+		r.staged = nil
+		r.stagedEvents = nil
+		r.rolledBack = true
+
+		return err
+	}
+
+	r.computers = append(r.computers, r.staged...)
+	r.events = append(r.events, r.stagedEvents...)
+	r.committed = true
+
+	return nil
+}
+
+func (r *fakeTxRepository) LockIPAddress(ipAddress string) error {
+	r.lockedIPAddresses = append(r.lockedIPAddresses, ipAddress)
+	return nil
+}
+
+func (r *fakeTxRepository) LockSubnet(subnetID int) error {
+	r.lockedSubnets = append(r.lockedSubnets, subnetID)
+	return nil
+}
+
+func (r *fakeTxRepository) GetSubnet(subnetID int) (dbo.Subnet, error) {
+	return r.subnet, nil
+}
+
+func (r *fakeTxRepository) GetUsedIPAddresses(subnetID int) ([]string, error) {
+	return r.usedIPAddresses, nil
+}
+
+func (r *fakeTxRepository) GetAllocatedIPAddresses(subnetID int) ([]string, error) {
+	return nil, nil
+}
+
+func (r *fakeTxRepository) GetComputersByIPAddress(ipAddress string) ([]dbo.Computer, error) {
+	return nil, nil
+}
+
+func (r *fakeTxRepository) AddComputer(computer dbo.Computer) (int, error) {
+	computer.ID = len(r.computers) + len(r.staged) + 1
+	r.staged = append(r.staged, computer)
+
+	return computer.ID, nil
+}
+
+func (r *fakeTxRepository) GetComputer(computerID int) (dbo.Computer, error) {
+	for _, computer := range r.computers {
+		if computer.ID == computerID {
+			return computer, nil
+		}
+	}
+
+	return dbo.Computer{}, errs.NewNotFound("computer not found")
+}
+
+func (r *fakeTxRepository) UpdateComputer(computerID int, data dbo.Computer) error {
+	r.updated = append(r.updated, data)
+	return nil
+}
+
+func (r *fakeTxRepository) CheckOutComputer(assignment dbo.Assignment, transition dbo.StatusTransition) (dbo.Assignment, error) {
+	r.transitions = append(r.transitions, transition)
+	return assignment, nil
+}
+
+func (r *fakeTxRepository) CheckInComputer(transition dbo.StatusTransition) (dbo.Assignment, error) {
+	r.transitions = append(r.transitions, transition)
+	return dbo.Assignment{}, nil
+}
+
+func (r *fakeTxRepository) AddComputerEvents(events []dbo.ComputerEvent) ([]dbo.ComputerEvent, error) {
+	for i := range events {
+		events[i].ID = int64(len(r.events) + len(r.stagedEvents) + 1)
+		r.stagedEvents = append(r.stagedEvents, events[i])
+	}
+
+	return events, nil
+}
+
+func (r *fakeTxRepository) CountComputersByEmployee(employee string) (int, error) {
+	if r.countErr != nil {
+		return 0, r.countErr
+	}
+
+	return 3, nil
+}
+
+func (r *fakeTxRepository) GetComputersByEmployee(ctx context.Context, employee string) ([]dbo.Computer, error) {
+	return r.staged, nil
+}
+
+func (r *fakeTxRepository) ClaimAssignmentWarning(employee string, count int, cooldown time.Duration) (bool, error) {
+	return r.warnings.claim(employee, count, cooldown), nil
+}
+
+func (r *fakeTxRepository) ResolveAssignmentWarning(employee string) (bool, error) {
+	return r.warnings.resolve(employee), nil
+}
+
+func TestAddComputerTransaction(t *testing.T) {
+	employee := "EMP"
+
+	tests := []struct {
+		name     string
+		countErr error
+		wantErr  bool
+	}{
+		{
+			name: "committed",
+		},
+		{
+			name:     "rolled back if the threshold check fails",
+			countErr: errors.New("connection lost"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTxRepository{countErr: tt.countErr}
+			notifier := &fakeMessageSender{messages: make(chan string, 1)}
+			s := NewComputerMgmtService(repo, notifier)
+
+			_, err := s.AddComputer(model.Computer{
+				Name:                 "PC1",
+				IPAddress:            "10.0.0.1",
+				MACAddress:           "00:00:00:00:00:01",
+				EmployeeAbbreviation: &employee,
+			})
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, tt.countErr)
+				assert.True(t, repo.rolledBack)
+				assert.Empty(t, repo.computers)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, repo.committed)
+				assert.Len(t, repo.computers, 1)
+				assert.Equal(t, []string{"10.0.0.1"}, repo.lockedIPAddresses)
+			}
+
+			select {
+			case <-notifier.messages:
+				assert.False(t, tt.wantErr, "notification sent for a rolled back computer")
+			case <-time.After(50 * time.Millisecond):
+				assert.True(t, tt.wantErr, "missing notification")
+			}
+		})
+	}
+}
+
+func TestAddComputerAllocatesIPAddress(t *testing.T) {
+	subnetID := 1
+	repo := &fakeTxRepository{
+		subnet:          dbo.Subnet{ID: subnetID, CIDR: "10.0.0.0/29"},
+		usedIPAddresses: []string{"10.0.0.1"},
+	}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{messages: make(chan string, 1)})
+
+	_, err := s.AddComputer(model.Computer{
+		Name:       "PC1",
+		IPAddress:  model.AutoIPAddress,
+		MACAddress: "00:00:00:00:00:01",
+		SubnetID:   &subnetID,
+	})
+	require.NoError(t, err)
+
+	// The address is allocated within the transaction storing the computer.
+	assert.Equal(t, []int{subnetID}, repo.lockedSubnets)
+	require.Len(t, repo.computers, 1)
+	assert.Equal(t, "10.0.0.2", repo.computers[0].IPAddress)
+}
+
+func TestAddComputerErrorTypes(t *testing.T) {
+	employee := "EMP"
+
+	tests := []struct {
+		name     string
+		computer model.Computer
+		countErr error
+		target   any
+	}{
+		{
+			name:     "automatic IP address without subnet",
+			computer: model.Computer{Name: "PC1", IPAddress: model.AutoIPAddress, MACAddress: "00:00:00:00:00:01"},
+			target:   new(*errs.ValidationError),
+		},
+		{
+			name:     "unavailable repository",
+			computer: model.Computer{Name: "PC1", IPAddress: "10.0.0.1", MACAddress: "00:00:00:00:00:01", EmployeeAbbreviation: &employee},
+			countErr: errs.NewUnavailable("the database is unavailable", errors.New("connection refused")),
+			target:   new(*errs.UnavailableError),
+		},
+		{
+			name:     "timed out repository",
+			computer: model.Computer{Name: "PC1", IPAddress: "10.0.0.1", MACAddress: "00:00:00:00:00:01", EmployeeAbbreviation: &employee},
+			countErr: errs.NewTimeout("the database has not responded in time", context.DeadlineExceeded),
+			target:   new(*errs.TimeoutError),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTxRepository{countErr: tt.countErr}
+			s := NewComputerMgmtService(repo, &fakeMessageSender{messages: make(chan string, 1)})
+
+			_, err := s.AddComputer(tt.computer)
+
+			require.ErrorAs(t, err, tt.target)
+			assert.Empty(t, repo.computers)
+		})
+	}
+}
+
+func TestUpdateComputerReassignsEmployee(t *testing.T) {
+	employee := "EMP"
+	other := "NEW"
+
+	checkIn := dbo.StatusTransition{ComputerID: 1, FromStatus: "assigned", ToStatus: "in_stock", Reason: "Checked in from EMP", Actor: "api"}
+	checkOut := dbo.StatusTransition{ComputerID: 1, FromStatus: "in_stock", ToStatus: "assigned", Reason: "Checked out to NEW", Actor: "api"}
+
+	tests := []struct {
+		name            string
+		computer        dbo.Computer
+		employee        *string
+		wantTransitions []dbo.StatusTransition
+		wantNotified    []string
+	}{
+		{
+			name:            "another employee",
+			computer:        dbo.Computer{ID: 1, Name: "PC1", EmployeeAbbreviation: stringToNullString(&employee), Status: "assigned"},
+			employee:        &other,
+			wantTransitions: []dbo.StatusTransition{checkIn, checkOut},
+			wantNotified:    []string{"EMP", "NEW"},
+		},
+		{
+			name:            "no employee",
+			computer:        dbo.Computer{ID: 1, Name: "PC1", EmployeeAbbreviation: stringToNullString(&employee), Status: "assigned"},
+			employee:        nil,
+			wantTransitions: []dbo.StatusTransition{checkIn},
+			wantNotified:    []string{"EMP"},
+		},
+		{
+			name:            "computer in stock",
+			computer:        dbo.Computer{ID: 1, Name: "PC1", Status: "in_stock"},
+			employee:        &other,
+			wantTransitions: []dbo.StatusTransition{checkOut},
+			wantNotified:    []string{"NEW"},
+		},
+		{
+			name:     "same employee",
+			computer: dbo.Computer{ID: 1, Name: "PC1", EmployeeAbbreviation: stringToNullString(&employee), Status: "assigned"},
+			employee: &employee,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTxRepository{computers: []dbo.Computer{tt.computer}}
+			notifier := &fakeMessageSender{messages: make(chan string, 2)}
+			s := NewComputerMgmtService(repo, notifier)
+
+			err := s.UpdateComputer(1, model.Computer{Name: "PC1", IPAddress: "10.0.0.1", MACAddress: "00:00:00:00:00:01", EmployeeAbbreviation: tt.employee})
+			require.NoError(t, err)
+
+			assert.True(t, repo.committed)
+			assert.Len(t, repo.updated, 1)
+			assert.Equal(t, tt.wantTransitions, repo.transitions)
+			assert.ElementsMatch(t, tt.wantNotified, receiveMessages(notifier.messages, len(tt.wantNotified)))
+		})
+	}
+}
+
+func TestUpdateComputerRejectsUnassignableComputer(t *testing.T) {
+	repo := &fakeTxRepository{computers: []dbo.Computer{{ID: 1, Name: "PC1", Status: "in_repair"}}}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{})
+
+	employee := "NEW"
+	err := s.UpdateComputer(1, model.Computer{Name: "PC1", IPAddress: "10.0.0.1", MACAddress: "00:00:00:00:00:01", EmployeeAbbreviation: &employee})
+
+	var invalid *errs.InvalidTransitionError
+	require.ErrorAs(t, err, &invalid)
+	assert.True(t, repo.rolledBack)
+	assert.Empty(t, repo.updated)
+}
+
+// fakeSnapshotRepository serves the version and the computers only from the repository passed to fn by
+// WithSnapshot. Reading them outside of the snapshot panics.
+type fakeSnapshotRepository struct {
+	ComputerRepository
+
+	snapshot       *fakeSnapshot
+	readYourWrites bool
+}
+
+func (r *fakeSnapshotRepository) WithSnapshot(ctx context.Context, fn func(repo ComputerRepository) error) error {
+	r.readYourWrites = consistency.ReadYourWrites(ctx)
+	return fn(r.snapshot)
+}
+
+type fakeSnapshot struct {
+	ComputerRepository
+
+	version   dbo.ComputersVersion
+	computers []dbo.Computer
+}
+
+func (r *fakeSnapshot) GetComputersVersion(ctx context.Context) (dbo.ComputersVersion, error) {
+	return r.version, nil
+}
+
+func (r *fakeSnapshot) GetComputer(computerID int) (dbo.Computer, error) {
+	return r.computers[0], nil
+}
+
+func (r *fakeSnapshot) ComputerExists(ctx context.Context, computerID int) (bool, error) {
+	return slices.ContainsFunc(r.computers, func(c dbo.Computer) bool { return c.ID == computerID }), nil
+}
+
+func (r *fakeSnapshot) GetAllComputers(ctx context.Context, filter dbo.ComputerFilter) ([]dbo.Computer, error) {
+	return r.computers, nil
+}
+
+func TestGetVersionedComputersReadsOneSnapshot(t *testing.T) {
+	repo := &fakeSnapshotRepository{snapshot: &fakeSnapshot{
+		version:   dbo.ComputersVersion{Version: 7},
+		computers: []dbo.Computer{{ID: 1, Name: "PC1"}},
+	}}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{})
+
+	computers, version, err := s.GetVersionedComputers(context.Background(), model.ComputerFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, model.ComputersVersion{Version: 7}, version)
+	require.Len(t, computers, 1)
+	assert.False(t, repo.readYourWrites)
+
+	// A single computer is read from the primary.
+	computer, version, err := s.GetVersionedComputer(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, model.ComputersVersion{Version: 7}, version)
+	assert.Equal(t, 1, computer.ID)
+	assert.True(t, repo.readYourWrites)
+}
+
+func TestGetComputerVersionRequiresExistingComputer(t *testing.T) {
+	repo := &fakeSnapshotRepository{snapshot: &fakeSnapshot{
+		version:   dbo.ComputersVersion{Version: 7},
+		computers: []dbo.Computer{{ID: 1, Name: "PC1"}},
+	}}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{})
+
+	version, err := s.GetComputerVersion(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, model.ComputersVersion{Version: 7}, version)
+	assert.True(t, repo.readYourWrites)
+
+	_, err = s.GetComputerVersion(context.Background(), 2)
+
+	var notFound *errs.NotFoundError
+	require.ErrorAs(t, err, &notFound)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"database/sql"
+	"time"
 	"uhuaha/computers-management/internal/db/postgres/dbo"
 	"uhuaha/computers-management/internal/model"
 )
@@ -14,6 +15,15 @@ func convertComputerModelToDBO(c model.Computer) dbo.Computer {
 		MACAddress:           c.MACAddress,
 		EmployeeAbbreviation: stringToNullString(c.EmployeeAbbreviation),
 		Description:          stringToNullString(c.Description),
+		SubnetID:             intToNullInt64(c.SubnetID),
+		Status:               string(c.Status),
+		SerialNumber:         stringToNullString(c.SerialNumber),
+		PurchaseDate:         timeToNullTime(c.PurchaseDate),
+		WarrantyEnd:          timeToNullTime(c.WarrantyEnd),
+		Vendor:               stringToNullString(c.Vendor),
+		Model:                stringToNullString(c.Model),
+		OperatingSystem:      stringToNullString(c.OperatingSystem),
+		Location:             stringToNullString(c.Location),
 	}
 }
 
@@ -33,6 +43,15 @@ func convertComputerDBOToModel(dbo dbo.Computer) model.Computer {
 		MACAddress:           dbo.MACAddress,
 		EmployeeAbbreviation: nullStringToPointer(dbo.EmployeeAbbreviation),
 		Description:          nullStringToPointer(dbo.Description),
+		SubnetID:             nullInt64ToPointer(dbo.SubnetID),
+		Status:               model.ComputerStatus(dbo.Status),
+		SerialNumber:         nullStringToPointer(dbo.SerialNumber),
+		PurchaseDate:         nullTimeToPointer(dbo.PurchaseDate),
+		WarrantyEnd:          nullTimeToPointer(dbo.WarrantyEnd),
+		Vendor:               nullStringToPointer(dbo.Vendor),
+		Model:                nullStringToPointer(dbo.Model),
+		OperatingSystem:      nullStringToPointer(dbo.OperatingSystem),
+		Location:             nullStringToPointer(dbo.Location),
 	}
 }
 
@@ -43,3 +62,256 @@ func nullStringToPointer(ns sql.NullString) *string {
 
 	return nil
 }
+
+func intToNullInt64(i *int) sql.NullInt64 {
+	if i != nil {
+		return sql.NullInt64{Int64: int64(*i), Valid: true}
+	}
+
+	return sql.NullInt64{Valid: false}
+}
+
+func nullInt64ToPointer(ni sql.NullInt64) *int {
+	if ni.Valid {
+		i := int(ni.Int64)
+		return &i
+	}
+
+	return nil
+}
+
+func timeToNullTime(t *time.Time) sql.NullTime {
+	if t != nil {
+		return sql.NullTime{Time: *t, Valid: true}
+	}
+
+	return sql.NullTime{Valid: false}
+}
+
+func nullTimeToPointer(nt sql.NullTime) *time.Time {
+	if nt.Valid {
+		return &nt.Time
+	}
+
+	return nil
+}
+
+func convertStatusTransitionModelToDBO(t model.StatusTransition) dbo.StatusTransition {
+	return dbo.StatusTransition{
+		ID:         t.ID,
+		ComputerID: t.ComputerID,
+		FromStatus: string(t.From),
+		ToStatus:   string(t.To),
+		Reason:     t.Reason,
+		Actor:      t.Actor,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+func convertStatusTransitionDBOToModel(t dbo.StatusTransition) model.StatusTransition {
+	return model.StatusTransition{
+		ID:         t.ID,
+		ComputerID: t.ComputerID,
+		From:       model.ComputerStatus(t.FromStatus),
+		To:         model.ComputerStatus(t.ToStatus),
+		Reason:     t.Reason,
+		Actor:      t.Actor,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+func convertComputerSelectorModelToDBO(s model.ComputerSelector) dbo.ComputerSelector {
+	selector := dbo.ComputerSelector{IDs: s.IDs}
+	if s.Filter != nil {
+		filter := dbo.ComputerFilter(*s.Filter)
+		selector.Filter = &filter
+	}
+
+	return selector
+}
+
+func convertComputerChangesModelToDBO(c model.ComputerChanges) dbo.ComputerChanges {
+	return dbo.ComputerChanges{
+		Description:     stringToNullString(c.Description),
+		Vendor:          stringToNullString(c.Vendor),
+		Model:           stringToNullString(c.Model),
+		OperatingSystem: stringToNullString(c.OperatingSystem),
+		Location:        stringToNullString(c.Location),
+	}
+}
+
+func convertAssignmentModelToDBO(a model.Assignment) dbo.Assignment {
+	return dbo.Assignment{
+		ID:                   a.ID,
+		ComputerID:           a.ComputerID,
+		ComputerName:         a.ComputerName,
+		EmployeeAbbreviation: a.EmployeeAbbreviation,
+		StartedAt:            a.StartedAt,
+		EndedAt:              timeToNullTime(a.EndedAt),
+	}
+}
+
+func convertAssignmentDBOToModel(a dbo.Assignment) model.Assignment {
+	return model.Assignment{
+		ID:                   a.ID,
+		ComputerID:           a.ComputerID,
+		ComputerName:         a.ComputerName,
+		EmployeeAbbreviation: a.EmployeeAbbreviation,
+		StartedAt:            a.StartedAt,
+		EndedAt:              nullTimeToPointer(a.EndedAt),
+	}
+}
+
+func convertComputerEventModelToDBO(e model.ComputerEvent) dbo.ComputerEvent {
+	return dbo.ComputerEvent{
+		ID:                   e.ID,
+		Type:                 string(e.Type),
+		ComputerID:           e.ComputerID,
+		EmployeeAbbreviation: stringToNullString(e.EmployeeAbbreviation),
+		OccurredAt:           e.OccurredAt,
+	}
+}
+
+func convertComputerEventDBOToModel(e dbo.ComputerEvent) model.ComputerEvent {
+	return model.ComputerEvent{
+		ID:                   e.ID,
+		Type:                 model.ComputerEventType(e.Type),
+		ComputerID:           e.ComputerID,
+		EmployeeAbbreviation: nullStringToPointer(e.EmployeeAbbreviation),
+		OccurredAt:           e.OccurredAt,
+	}
+}
+
+func convertWebhookSubscriptionModelToDBO(s model.WebhookSubscription) dbo.WebhookSubscription {
+	eventTypes := make([]string, len(s.EventTypes))
+	for i, t := range s.EventTypes {
+		eventTypes[i] = string(t)
+	}
+
+	return dbo.WebhookSubscription{
+		ID:         s.ID,
+		URL:        s.URL,
+		EventTypes: eventTypes,
+		Secret:     s.Secret,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+func convertWebhookSubscriptionDBOToModel(s dbo.WebhookSubscription) model.WebhookSubscription {
+	eventTypes := make([]model.ComputerEventType, len(s.EventTypes))
+	for i, t := range s.EventTypes {
+		eventTypes[i] = model.ComputerEventType(t)
+	}
+
+	return model.WebhookSubscription{
+		ID:         s.ID,
+		URL:        s.URL,
+		EventTypes: eventTypes,
+		Secret:     s.Secret,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+func convertWebhookDeliveryModelToDBO(d model.WebhookDelivery) dbo.WebhookDelivery {
+	delivery := dbo.WebhookDelivery{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventType:      string(d.EventType),
+		Attempt:        d.Attempt,
+		StatusCode:     intToNullInt64(d.StatusCode),
+		Error:          stringToNullString(d.Error),
+		Succeeded:      d.Succeeded,
+		DurationMS:     int(d.Duration.Milliseconds()),
+		DeliveredAt:    d.DeliveredAt,
+	}
+
+	if d.EventID != nil {
+		delivery.EventID = sql.NullInt64{Int64: *d.EventID, Valid: true}
+	}
+
+	return delivery
+}
+
+func convertWebhookDeliveryDBOToModel(d dbo.WebhookDelivery) model.WebhookDelivery {
+	delivery := model.WebhookDelivery{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventType:      model.ComputerEventType(d.EventType),
+		Attempt:        d.Attempt,
+		StatusCode:     nullInt64ToPointer(d.StatusCode),
+		Error:          nullStringToPointer(d.Error),
+		Succeeded:      d.Succeeded,
+		Duration:       time.Duration(d.DurationMS) * time.Millisecond,
+		DeliveredAt:    d.DeliveredAt,
+	}
+
+	if d.EventID.Valid {
+		delivery.EventID = &d.EventID.Int64
+	}
+
+	return delivery
+}
+
+func convertNetworkInterfaceModelToDBO(i model.NetworkInterface) dbo.NetworkInterface {
+	return dbo.NetworkInterface{
+		ID:         i.ID,
+		ComputerID: i.ComputerID,
+		Name:       i.Name,
+		MACAddress: i.MACAddress,
+		IPAddress:  stringToNullString(i.IPAddress),
+		Type:       string(i.Type),
+		IsPrimary:  i.IsPrimary,
+	}
+}
+
+func convertNetworkInterfaceDBOToModel(i dbo.NetworkInterface) model.NetworkInterface {
+	return model.NetworkInterface{
+		ID:         i.ID,
+		ComputerID: i.ComputerID,
+		Name:       i.Name,
+		MACAddress: i.MACAddress,
+		IPAddress:  nullStringToPointer(i.IPAddress),
+		Type:       model.NetworkInterfaceType(i.Type),
+		IsPrimary:  i.IsPrimary,
+	}
+}
+
+func convertSubnetModelToDBO(s model.Subnet) dbo.Subnet {
+	ranges := make([]dbo.IPRange, len(s.ReservedRanges))
+	for i, r := range s.ReservedRanges {
+		ranges[i] = dbo.IPRange{
+			Start:       r.Start,
+			End:         r.End,
+			Description: stringToNullString(r.Description),
+		}
+	}
+
+	return dbo.Subnet{
+		ID:             s.ID,
+		Name:           s.Name,
+		CIDR:           s.CIDR,
+		Gateway:        stringToNullString(s.Gateway),
+		VLAN:           intToNullInt64(s.VLAN),
+		ReservedRanges: ranges,
+	}
+}
+
+func convertSubnetDBOToModel(s dbo.Subnet) model.Subnet {
+	ranges := make([]model.IPRange, len(s.ReservedRanges))
+	for i, r := range s.ReservedRanges {
+		ranges[i] = model.IPRange{
+			Start:       r.Start,
+			End:         r.End,
+			Description: nullStringToPointer(r.Description),
+		}
+	}
+
+	return model.Subnet{
+		ID:             s.ID,
+		Name:           s.Name,
+		CIDR:           s.CIDR,
+		Gateway:        nullStringToPointer(s.Gateway),
+		VLAN:           nullInt64ToPointer(s.VLAN),
+		ReservedRanges: ranges,
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertComputerModelToDBORoundTrip(t *testing.T) {
+	purchaseDate := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	warrantyEnd := time.Date(2027, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	toPointer := func(s string) *string { return &s }
+	subnetID := 4
+
+	tests := []struct {
+		name     string
+		computer model.Computer
+	}{
+		{
+			name: "required fields only",
+			computer: model.Computer{
+				ID:         1,
+				Name:       "TestPC",
+				IPAddress:  "192.168.0.1",
+				MACAddress: "AA:BB:CC:DD:EE:FF",
+			},
+		},
+		{
+			name: "all fields",
+			computer: model.Computer{
+				ID:                   2,
+				Name:                 "LabPC",
+				IPAddress:            "10.0.0.3",
+				MACAddress:           "11:22:33:44:55:77",
+				EmployeeAbbreviation: toPointer("STR"),
+				Description:          toPointer("Lab machine"),
+				SubnetID:             &subnetID,
+				SerialNumber:         toPointer("SN-123"),
+				PurchaseDate:         &purchaseDate,
+				WarrantyEnd:          &warrantyEnd,
+				Vendor:               toPointer("Lenovo"),
+				Model:                toPointer("ThinkCentre M70q"),
+				OperatingSystem:      toPointer("Ubuntu 24.04"),
+				Location:             toPointer("Berlin"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.computer, convertComputerDBOToModel(convertComputerModelToDBO(tt.computer)))
+		})
+	}
+}
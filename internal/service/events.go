@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/events"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/bdlm/log"
+)
+
+// EventPublisher delivers the events of committed changes, e.g. to the clients streaming them.
+type EventPublisher interface {
+	Publish(events ...model.ComputerEvent)
+}
+
+// WithEventPublisher enables recording an event for every change of a computer. The events are stored within the
+// transaction of the change and passed to publisher once it has been committed. The option can be given more than
+// once to publish the events to several publishers. The events of concurrent changes can reach publisher in any
+// order; an EventRelay publishes them in the order of their IDs.
+func WithEventPublisher(publisher EventPublisher) Option {
+	return func(s *ComputerMgmtService) {
+		s.publishers = append(s.publishers, publisher)
+	}
+}
+
+// recordEvent adds an event to the ones stored at the end of the current transaction. It does nothing if no
+// publisher is configured.
+func (s *ComputerMgmtService) recordEvent(eventType model.ComputerEventType, computerID int, employee *string) {
+	if s.pendingEvents == nil {
+		return
+	}
+
+	*s.pendingEvents = append(*s.pendingEvents, model.ComputerEvent{
+		Type:                 eventType,
+		ComputerID:           computerID,
+		EmployeeAbbreviation: employee,
+	})
+}
+
+// recordComputerEvent adds an event reporting the change of a computer whose employee is not known to the caller.
+// The computer is only loaded if events are recorded.
+func (s *ComputerMgmtService) recordComputerEvent(eventType model.ComputerEventType, computerID int) error {
+	if s.pendingEvents == nil {
+		return nil
+	}
+
+	computer, err := s.GetComputer(computerID)
+	if err != nil {
+		return err
+	}
+
+	s.recordEvent(eventType, computerID, computer.EmployeeAbbreviation)
+
+	return nil
+}
+
+// storeEvents stores the recorded events and replaces them with the stored ones, which carry their IDs.
+func (s *ComputerMgmtService) storeEvents() error {
+	if s.pendingEvents == nil || len(*s.pendingEvents) == 0 {
+		return nil
+	}
+
+	eventDBOs := make([]dbo.ComputerEvent, len(*s.pendingEvents))
+	for i, event := range *s.pendingEvents {
+		eventDBOs[i] = convertComputerEventModelToDBO(event)
+	}
+
+	stored, err := s.repository.AddComputerEvents(eventDBOs)
+	if err != nil {
+		return fmt.Errorf("failed to record computer events: %w", err)
+	}
+
+	for i, dbo := range stored {
+		(*s.pendingEvents)[i] = convertComputerEventDBOToModel(dbo)
+	}
+
+	return nil
+}
+
+// eventRelayPageSize is the maximum number of events the relay reads from the event log at once.
+const eventRelayPageSize = 100
+
+// EventRelay publishes the recorded events in the order of their IDs. The services pass the events of their
+// committed changes to Publish concurrently, so two changes can reach it in a different order than their IDs. The
+// relay therefore only takes Publish as a hint and reads the new events from the event log, whose IDs follow the
+// order of the commits.
+type EventRelay struct {
+	repository EventRepository
+	publishers []EventPublisher
+	wake       chan struct{}
+	// lastID is the ID of the last published event. It is only accessed by Run.
+	lastID int64
+}
+
+// NewEventRelay returns a relay publishing the events recorded from now on to the publishers.
+func NewEventRelay(ctx context.Context, repo EventRepository, publishers ...EventPublisher) (*EventRelay, error) {
+	lastID, err := repo.GetLastComputerEventID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the last computer event ID: %w", err)
+	}
+
+	return &EventRelay{
+		repository: repo,
+		publishers: publishers,
+		wake:       make(chan struct{}, 1),
+		lastID:     lastID,
+	}, nil
+}
+
+// Publish wakes the relay to publish the events recorded since its last run. It does not block.
+func (r *EventRelay) Publish(...model.ComputerEvent) {
+	select {
+	case r.wake <- struct{}{}:
+	default: // a run is already pending and will read the events
+	}
+}
+
+// Run publishes the new events whenever the relay is woken and at least once per interval, which repeats a failed
+// read of the event log, until ctx is canceled.
+func (r *EventRelay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		case <-ticker.C:
+		}
+
+		if err := r.publishNewEvents(ctx); err != nil {
+			log.Error("publishing computer events failed: " + err.Error())
+		}
+	}
+}
+
+// publishNewEvents publishes the events recorded after the last published one in the order of their IDs.
+func (r *EventRelay) publishNewEvents(ctx context.Context) error {
+	for {
+		eventDBOs, err := r.repository.GetComputerEventsSince(ctx, r.lastID, eventRelayPageSize)
+		if err != nil {
+			return fmt.Errorf("failed to get computer events after ID=%d: %w", r.lastID, err)
+		}
+
+		if len(eventDBOs) == 0 {
+			return nil
+		}
+
+		events := make([]model.ComputerEvent, len(eventDBOs))
+		for i, dbo := range eventDBOs {
+			events[i] = convertComputerEventDBOToModel(dbo)
+		}
+
+		for _, publisher := range r.publishers {
+			publisher.Publish(events...)
+		}
+
+		r.lastID = events[len(events)-1].ID
+
+		if len(eventDBOs) < eventRelayPageSize {
+			return nil
+		}
+	}
+}
+
+type EventRepository interface {
+	GetComputerEventsSince(ctx context.Context, afterID int64, limit int) ([]dbo.ComputerEvent, error)
+	GetLastComputerEventID(ctx context.Context) (int64, error)
+	DeleteComputerEventsBefore(before time.Time) (int, error)
+}
+
+// EventMgmtService provides the computer events to the clients streaming them: the live ones from the bus and the
+// recorded ones from the event log.
+type EventMgmtService struct {
+	repository EventRepository
+	bus        *events.Bus
+	// retention is the time after which a recorded event is deleted. Zero keeps the events forever.
+	retention time.Duration
+	now       func() time.Time
+}
+
+func NewEventMgmtService(repo EventRepository, bus *events.Bus, retention time.Duration) *EventMgmtService {
+	return &EventMgmtService{
+		repository: repo,
+		bus:        bus,
+		retention:  retention,
+		now:        time.Now,
+	}
+}
+
+// SubscribeToComputerEvents returns a channel receiving the events published from now on and a function that ends
+// the subscription. The channel is closed if the subscriber falls too far behind or the subscription is ended.
+func (s *EventMgmtService) SubscribeToComputerEvents() (<-chan model.ComputerEvent, func()) {
+	sub := s.bus.Subscribe()
+
+	return sub.Events(), sub.Close
+}
+
+// GetComputerEventsSince returns at most limit recorded events following the event with the ID afterID.
+func (s *EventMgmtService) GetComputerEventsSince(ctx context.Context, afterID int64, limit int) ([]model.ComputerEvent, error) {
+	eventDBOs, err := s.repository.GetComputerEventsSince(ctx, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get computer events after ID=%d: %w", afterID, err)
+	}
+
+	events := make([]model.ComputerEvent, len(eventDBOs))
+	for i, dbo := range eventDBOs {
+		events[i] = convertComputerEventDBOToModel(dbo)
+	}
+
+	return events, nil
+}
+
+// DeleteExpiredComputerEvents deletes all recorded events older than the retention and returns their number. A
+// client resuming a stream after them only receives the events that are still recorded.
+func (s *EventMgmtService) DeleteExpiredComputerEvents() (int, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+
+	deleted, err := s.repository.DeleteComputerEventsBefore(s.now().Add(-s.retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired computer events: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// RunEventCleanup deletes expired events immediately and then once per interval until ctx is canceled.
+func (s *EventMgmtService) RunEventCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := s.DeleteExpiredComputerEvents()
+		if err != nil {
+			log.Error("scheduled deletion of expired computer events failed: " + err.Error())
+		} else if deleted > 0 {
+			log.Infof("deleted %d expired computer event(s)", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
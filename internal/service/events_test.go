@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEventPublisher records the published events.
+type fakeEventPublisher struct {
+	mu     sync.Mutex
+	events []model.ComputerEvent
+}
+
+func (p *fakeEventPublisher) Publish(events ...model.ComputerEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, events...)
+}
+
+func TestAddComputerPublishesEventAfterCommit(t *testing.T) {
+	employee := "EMP"
+
+	tests := []struct {
+		name     string
+		countErr error
+		wantErr  bool
+	}{
+		{
+			name: "committed",
+		},
+		{
+			name:     "rolled back if the threshold check fails",
+			countErr: errors.New("connection lost"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTxRepository{countErr: tt.countErr}
+			publisher := &fakeEventPublisher{}
+			s := NewComputerMgmtService(repo, &fakeMessageSender{messages: make(chan string, 1)}, WithEventPublisher(publisher))
+
+			computerID, err := s.AddComputer(model.Computer{
+				Name:                 "PC1",
+				IPAddress:            "10.0.0.1",
+				MACAddress:           "00:00:00:00:00:01",
+				EmployeeAbbreviation: &employee,
+			})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Empty(t, repo.events)
+				assert.Empty(t, publisher.events)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, repo.events, 1)
+			require.Len(t, publisher.events, 1)
+
+			event := publisher.events[0]
+			assert.Equal(t, int64(1), event.ID)
+			assert.Equal(t, model.ComputerCreated, event.Type)
+			assert.Equal(t, computerID, event.ComputerID)
+			assert.Equal(t, &employee, event.EmployeeAbbreviation)
+		})
+	}
+}
+
+func TestBatchUpdateComputersPublishesEvents(t *testing.T) {
+	repo := &fakeBatchRepository{computers: batchComputers()}
+	publisher := &fakeEventPublisher{}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{messages: make(chan string, 10)}, WithEventPublisher(publisher))
+
+	employee := "ABC"
+	_, err := s.BatchUpdateComputers(model.ComputerSelector{IDs: []int{1, 3}}, model.ComputerChanges{EmployeeAbbreviation: &employee}, false)
+	require.NoError(t, err)
+
+	// PC1 already belongs to the employee, PC3 is checked out to them.
+	require.Len(t, publisher.events, 2)
+	assert.Equal(t, model.ComputerUpdated, publisher.events[0].Type)
+	assert.Equal(t, 1, publisher.events[0].ComputerID)
+	assert.Equal(t, model.ComputerAssigned, publisher.events[1].Type)
+	assert.Equal(t, 3, publisher.events[1].ComputerID)
+	assert.Equal(t, &employee, publisher.events[1].EmployeeAbbreviation)
+}
+
+func TestBatchUpdateComputersDryRunPublishesNoEvents(t *testing.T) {
+	repo := &fakeBatchRepository{computers: batchComputers()}
+	publisher := &fakeEventPublisher{}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{}, WithEventPublisher(publisher))
+
+	location := "Hamburg"
+	_, err := s.BatchUpdateComputers(model.ComputerSelector{IDs: []int{3}}, model.ComputerChanges{Location: &location}, true)
+	require.NoError(t, err)
+
+	assert.Empty(t, repo.events)
+	assert.Empty(t, publisher.events)
+}
+
+// fakeEventRepository serves the events of its log and records the time before which events have been deleted.
+// Calling any other method panics.
+type fakeEventRepository struct {
+	EventRepository
+
+	log           []dbo.ComputerEvent
+	deletedBefore []time.Time
+}
+
+func (r *fakeEventRepository) GetComputerEventsSince(_ context.Context, afterID int64, limit int) ([]dbo.ComputerEvent, error) {
+	var events []dbo.ComputerEvent
+
+	for _, event := range r.log {
+		if event.ID > afterID && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func (r *fakeEventRepository) GetLastComputerEventID(context.Context) (int64, error) {
+	if len(r.log) == 0 {
+		return 0, nil
+	}
+
+	return r.log[len(r.log)-1].ID, nil
+}
+
+func (r *fakeEventRepository) DeleteComputerEventsBefore(before time.Time) (int, error) {
+	r.deletedBefore = append(r.deletedBefore, before)
+	return 3, nil
+}
+
+func TestDeleteExpiredComputerEvents(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("deletes events older than the retention", func(t *testing.T) {
+		repo := &fakeEventRepository{}
+		s := NewEventMgmtService(repo, nil, 24*time.Hour)
+		s.now = func() time.Time { return now }
+
+		deleted, err := s.DeleteExpiredComputerEvents()
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, deleted)
+		assert.Equal(t, []time.Time{now.Add(-24 * time.Hour)}, repo.deletedBefore)
+	})
+
+	t.Run("keeps events without retention", func(t *testing.T) {
+		repo := &fakeEventRepository{}
+		s := NewEventMgmtService(repo, nil, 0)
+
+		deleted, err := s.DeleteExpiredComputerEvents()
+		require.NoError(t, err)
+
+		assert.Zero(t, deleted)
+		assert.Empty(t, repo.deletedBefore)
+	})
+}
+
+// publishedIDs returns the IDs of the events published so far.
+func (p *fakeEventPublisher) publishedIDs() []int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids := make([]int64, len(p.events))
+	for i, event := range p.events {
+		ids[i] = event.ID
+	}
+
+	return ids
+}
+
+func TestEventRelayPublishesEventsInOrderOfTheirIDs(t *testing.T) {
+	repo := &fakeEventRepository{log: []dbo.ComputerEvent{{ID: 1, Type: string(model.ComputerCreated)}}}
+	publisher := &fakeEventPublisher{}
+
+	relay, err := NewEventRelay(context.Background(), repo, publisher)
+	require.NoError(t, err)
+
+	// Events 2 to 150 are committed, but the service committing event 150 publishes it first.
+	var want []int64
+	for id := int64(2); id <= 150; id++ {
+		repo.log = append(repo.log, dbo.ComputerEvent{ID: id, Type: string(model.ComputerUpdated)})
+		want = append(want, id)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go relay.Run(ctx, time.Hour)
+
+	relay.Publish(model.ComputerEvent{ID: 150, Type: model.ComputerUpdated})
+
+	require.Eventually(t, func() bool { return len(publisher.publishedIDs()) == len(want) }, time.Second, time.Millisecond)
+	assert.Equal(t, want, publisher.publishedIDs())
+}
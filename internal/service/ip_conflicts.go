@@ -0,0 +1,130 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"uhuaha/computers-management/internal/errors"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/bdlm/log"
+)
+
+// IPConflictMode defines how the service reacts to a computer using an IP address that is already in use.
+type IPConflictMode string
+
+const (
+	// IPConflictModeOff disables conflict detection on add and update.
+	IPConflictModeOff IPConflictMode = "off"
+	// IPConflictModeWarn accepts the computer but notifies the system administrator about the conflict.
+	IPConflictModeWarn IPConflictMode = "warn"
+	// IPConflictModeEnforce rejects the computer with a conflict error.
+	IPConflictModeEnforce IPConflictMode = "enforce"
+)
+
+// IPConflictPolicy configures the detection of duplicate IP addresses.
+type IPConflictPolicy struct {
+	Mode IPConflictMode
+	// Scopes restricts conflict detection to IP addresses within the given networks.
+	// An empty list means that all IP addresses share a single global scope.
+	Scopes []*net.IPNet
+}
+
+// DefaultIPConflictPolicy warns about duplicate IP addresses across all networks.
+var DefaultIPConflictPolicy = IPConflictPolicy{Mode: IPConflictModeWarn}
+
+// inScope reports whether the given IP address is subject to conflict detection.
+func (p IPConflictPolicy) inScope(ipAddress string) bool {
+	if len(p.Scopes) == 0 {
+		return true
+	}
+
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return false
+	}
+
+	for _, scope := range p.Scopes {
+		if scope.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithIPConflictPolicy sets the policy applied to duplicate IP addresses.
+func WithIPConflictPolicy(policy IPConflictPolicy) Option {
+	return func(s *ComputerMgmtService) {
+		s.ipConflictPolicy = policy
+	}
+}
+
+// GetIPConflicts returns all IP addresses within the configured scopes that are used by the network interfaces
+// of more than one computer.
+func (s *ComputerMgmtService) GetIPConflicts() ([]model.IPConflict, error) {
+	usages, err := s.repository.GetComputersWithDuplicateIPAddress()
+	if err != nil {
+		return []model.IPConflict{}, fmt.Errorf("failed to get computers with duplicate IP addresses: %w", err)
+	}
+
+	// The repository returns the usages ordered by IP address, so consecutive usages form a conflict.
+	conflicts := make([]model.IPConflict, 0)
+
+	for _, usage := range usages {
+		if !s.ipConflictPolicy.inScope(usage.IPAddress) {
+			continue
+		}
+
+		computer := convertComputerDBOToModel(usage.Computer)
+
+		if n := len(conflicts); n > 0 && conflicts[n-1].IPAddress == usage.IPAddress {
+			conflicts[n-1].Computers = append(conflicts[n-1].Computers, computer)
+			continue
+		}
+
+		conflicts = append(conflicts, model.IPConflict{
+			IPAddress: usage.IPAddress,
+			Computers: []model.Computer{computer},
+		})
+	}
+
+	return conflicts, nil
+}
+
+// checkIPConflict looks for other computers than the one with the given ID using the given IP address.
+// It returns a conflict error if the policy enforces unique IP addresses, otherwise it returns the other computers
+// so that the caller can notify the system administrator about them once the change is stored.
+// It has to be called within withTx: the address stays locked until the transaction ends, so that concurrent
+// changes using the same address cannot both pass the check.
+func (s *ComputerMgmtService) checkIPConflict(ipAddress string, computerID int) ([]model.Computer, error) {
+	if s.ipConflictPolicy.Mode == IPConflictModeOff || !s.ipConflictPolicy.inScope(ipAddress) {
+		return nil, nil
+	}
+
+	if err := s.repository.LockIPAddress(ipAddress); err != nil {
+		return nil, fmt.Errorf("failed to lock IP address %s: %w", ipAddress, err)
+	}
+
+	computerDBOs, err := s.repository.GetComputersByIPAddress(ipAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get computers with IP address %s: %w", ipAddress, err)
+	}
+
+	var conflicting []model.Computer
+
+	for _, computerDBO := range computerDBOs {
+		if computerDBO.ID == computerID {
+			continue
+		}
+
+		if s.ipConflictPolicy.Mode == IPConflictModeEnforce {
+			return nil, errors.NewConflict(fmt.Sprintf("IP address %s is already used by computer with ID=%d", ipAddress, computerDBO.ID))
+		}
+
+		log.Warnf("IP address %s is already used by computer with ID=%d", ipAddress, computerDBO.ID)
+
+		conflicting = append(conflicting, convertComputerDBOToModel(computerDBO))
+	}
+
+	return conflicting, nil
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/model"
+)
+
+// ipv4Range is an inclusive range of IPv4 addresses in their numeric representation.
+// uint64 is used so that iterating up to the last address cannot overflow.
+type ipv4Range struct {
+	start uint64
+	end   uint64
+}
+
+func (r ipv4Range) size() int {
+	return int(r.end - r.start + 1)
+}
+
+// addressPlan categorizes the host addresses of a subnet.
+type addressPlan struct {
+	hosts ipv4Range
+	// reserved holds the sorted and merged reserved ranges (including the gateway) clipped to hosts.
+	reserved  []ipv4Range
+	used      map[uint64]bool
+	allocated map[uint64]bool
+}
+
+// newAddressPlan builds the address plan of the given subnet from the addresses used by computers
+// and the addresses that have been allocated.
+func newAddressPlan(subnet dbo.Subnet, used, allocated []string) (addressPlan, error) {
+	_, network, err := net.ParseCIDR(subnet.CIDR)
+	if err != nil {
+		return addressPlan{}, fmt.Errorf("failed to parse CIDR %q: %w", subnet.CIDR, err)
+	}
+
+	hosts, err := hostRange(network)
+	if err != nil {
+		return addressPlan{}, err
+	}
+
+	plan := addressPlan{
+		hosts:     hosts,
+		used:      make(map[uint64]bool),
+		allocated: make(map[uint64]bool),
+	}
+
+	var reserved []ipv4Range
+
+	if subnet.Gateway.Valid {
+		gateway, err := parseIPv4(subnet.Gateway.String)
+		if err != nil {
+			return addressPlan{}, err
+		}
+
+		reserved = append(reserved, ipv4Range{start: gateway, end: gateway})
+	}
+
+	for _, ipRange := range subnet.ReservedRanges {
+		start, err := parseIPv4(ipRange.Start)
+		if err != nil {
+			return addressPlan{}, err
+		}
+
+		end, err := parseIPv4(ipRange.End)
+		if err != nil {
+			return addressPlan{}, err
+		}
+
+		reserved = append(reserved, ipv4Range{start: start, end: end})
+	}
+
+	plan.reserved = mergeRanges(reserved, hosts)
+
+	for _, ipAddress := range used {
+		if ip, err := parseIPv4(ipAddress); err == nil && plan.isHost(ip) {
+			plan.used[ip] = true
+		}
+	}
+
+	for _, ipAddress := range allocated {
+		if ip, err := parseIPv4(ipAddress); err == nil && plan.isHost(ip) && !plan.used[ip] {
+			plan.allocated[ip] = true
+		}
+	}
+
+	return plan, nil
+}
+
+func (p addressPlan) isHost(ip uint64) bool {
+	return ip >= p.hosts.start && ip <= p.hosts.end
+}
+
+// reservedRange returns the reserved range containing the given address, if any.
+func (p addressPlan) reservedRange(ip uint64) (ipv4Range, bool) {
+	i := sort.Search(len(p.reserved), func(i int) bool { return p.reserved[i].end >= ip })
+	if i < len(p.reserved) && p.reserved[i].start <= ip {
+		return p.reserved[i], true
+	}
+
+	return ipv4Range{}, false
+}
+
+// utilization counts every host address in exactly one category. Addresses used by computers
+// or allocated take precedence over reserved ones.
+func (p addressPlan) utilization(subnetID int) model.SubnetUtilization {
+	reserved := 0
+	for _, r := range p.reserved {
+		reserved += r.size()
+	}
+
+	for ip := range p.used {
+		if _, ok := p.reservedRange(ip); ok {
+			reserved--
+		}
+	}
+
+	for ip := range p.allocated {
+		if _, ok := p.reservedRange(ip); ok {
+			reserved--
+		}
+	}
+
+	total := p.hosts.size()
+
+	return model.SubnetUtilization{
+		SubnetID:  subnetID,
+		Total:     total,
+		Used:      len(p.used),
+		Allocated: len(p.allocated),
+		Reserved:  reserved,
+		Free:      total - len(p.used) - len(p.allocated) - reserved,
+	}
+}
+
+// nextFree returns the lowest host address that is neither reserved, used nor allocated.
+func (p addressPlan) nextFree() (string, bool) {
+	for ip := p.hosts.start; ip <= p.hosts.end; {
+		if r, ok := p.reservedRange(ip); ok {
+			ip = r.end + 1
+			continue
+		}
+
+		if !p.used[ip] && !p.allocated[ip] {
+			return formatIPv4(ip), true
+		}
+
+		ip++
+	}
+
+	return "", false
+}
+
+// hostRange returns the assignable addresses of an IPv4 network. The network and broadcast
+// addresses are excluded except for /31 and /32 networks which have none.
+func hostRange(network *net.IPNet) (ipv4Range, error) {
+	ones, bits := network.Mask.Size()
+	if bits != 32 {
+		return ipv4Range{}, fmt.Errorf("network %s is not an IPv4 network", network)
+	}
+
+	start := uint64(binary.BigEndian.Uint32(network.IP.To4()))
+	end := start + 1<<(32-ones) - 1
+
+	if ones >= 31 {
+		return ipv4Range{start: start, end: end}, nil
+	}
+
+	return ipv4Range{start: start + 1, end: end - 1}, nil
+}
+
+// mergeRanges sorts the ranges, merges overlapping and adjacent ones and clips them to bounds.
+func mergeRanges(ranges []ipv4Range, bounds ipv4Range) []ipv4Range {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].start < ranges[j].start })
+
+	var merged []ipv4Range
+
+	for _, r := range ranges {
+		r.start = max(r.start, bounds.start)
+		r.end = min(r.end, bounds.end)
+
+		if r.start > r.end {
+			continue
+		}
+
+		if n := len(merged); n > 0 && r.start <= merged[n-1].end+1 {
+			merged[n-1].end = max(merged[n-1].end, r.end)
+			continue
+		}
+
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+func parseIPv4(s string) (uint64, error) {
+	ip := net.ParseIP(s).To4()
+	if ip == nil {
+		return 0, fmt.Errorf("%q is not an IPv4 address", s)
+	}
+
+	return uint64(binary.BigEndian.Uint32(ip)), nil
+}
+
+func formatIPv4(ip uint64) string {
+	b := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(b, uint32(ip))
+
+	return b.String()
+}
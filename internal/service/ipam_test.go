@@ -0,0 +1,98 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressPlan(t *testing.T) {
+	tests := []struct {
+		name                string
+		subnet              dbo.Subnet
+		used                []string
+		allocated           []string
+		expectedNextFree    string
+		expectedUtilization model.SubnetUtilization
+	}{
+		{
+			name:             "empty subnet starts after the network address",
+			subnet:           dbo.Subnet{CIDR: "10.0.0.0/29"},
+			expectedNextFree: "10.0.0.1",
+			expectedUtilization: model.SubnetUtilization{
+				Total: 6, Free: 6,
+			},
+		},
+		{
+			name: "gateway, reserved ranges, used and allocated addresses are skipped",
+			subnet: dbo.Subnet{
+				CIDR:    "10.0.0.0/28",
+				Gateway: sql.NullString{String: "10.0.0.1", Valid: true},
+				ReservedRanges: []dbo.IPRange{
+					{Start: "10.0.0.2", End: "10.0.0.4"},
+					{Start: "10.0.0.4", End: "10.0.0.5"},
+				},
+			},
+			used:             []string{"10.0.0.6", "10.0.0.3"},
+			allocated:        []string{"10.0.0.7", "10.0.0.6"},
+			expectedNextFree: "10.0.0.8",
+			expectedUtilization: model.SubnetUtilization{
+				Total: 14, Used: 2, Allocated: 1, Reserved: 4, Free: 7,
+			},
+		},
+		{
+			name:             "/31 networks use both addresses",
+			subnet:           dbo.Subnet{CIDR: "10.0.0.0/31"},
+			used:             []string{"10.0.0.0"},
+			expectedNextFree: "10.0.0.1",
+			expectedUtilization: model.SubnetUtilization{
+				Total: 2, Used: 1, Free: 1,
+			},
+		},
+		{
+			name: "addresses outside of the subnet are ignored",
+			subnet: dbo.Subnet{
+				CIDR:           "10.0.0.0/30",
+				ReservedRanges: []dbo.IPRange{{Start: "10.0.0.0", End: "10.0.0.1"}},
+			},
+			used:             []string{"10.0.1.1", "10.0.0.3"},
+			expectedNextFree: "10.0.0.2",
+			expectedUtilization: model.SubnetUtilization{
+				Total: 2, Reserved: 1, Free: 1,
+			},
+		},
+		{
+			name: "exhausted subnet has no free address",
+			subnet: dbo.Subnet{
+				CIDR:           "192.168.1.0/30",
+				ReservedRanges: []dbo.IPRange{{Start: "192.168.1.1", End: "192.168.1.1"}},
+			},
+			used: []string{"192.168.1.2"},
+			expectedUtilization: model.SubnetUtilization{
+				Total: 2, Used: 1, Reserved: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := newAddressPlan(tt.subnet, tt.used, tt.allocated)
+			require.NoError(t, err)
+
+			nextFree, ok := plan.nextFree()
+			assert.Equal(t, tt.expectedNextFree != "", ok)
+			assert.Equal(t, tt.expectedNextFree, nextFree)
+
+			assert.Equal(t, tt.expectedUtilization, plan.utilization(0))
+		})
+	}
+}
+
+func TestAddressPlanRejectsIPv6(t *testing.T) {
+	_, err := newAddressPlan(dbo.Subnet{CIDR: "2001:db8::/64"}, nil, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"fmt"
+	"uhuaha/computers-management/internal/model"
+)
+
+// GetNetworkInterfaces returns all network interfaces of a computer with the primary interface first.
+func (s *ComputerMgmtService) GetNetworkInterfaces(computerID int) ([]model.NetworkInterface, error) {
+	if _, err := s.GetComputer(computerID); err != nil {
+		return nil, fmt.Errorf("failed to get network interfaces of computer with ID=%d: %w", computerID, err)
+	}
+
+	interfaceDBOs, err := s.repository.GetNetworkInterfaces(computerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network interfaces of computer with ID=%d: %w", computerID, err)
+	}
+
+	interfaces := make([]model.NetworkInterface, len(interfaceDBOs))
+	for i, dbo := range interfaceDBOs {
+		interfaces[i] = convertNetworkInterfaceDBOToModel(dbo)
+	}
+
+	return interfaces, nil
+}
+
+// GetNetworkInterface retrieves a network interface of a computer by its ID.
+func (s *ComputerMgmtService) GetNetworkInterface(computerID, interfaceID int) (model.NetworkInterface, error) {
+	interfaceDBO, err := s.repository.GetNetworkInterface(computerID, interfaceID)
+	if err != nil {
+		return model.NetworkInterface{}, fmt.Errorf("failed to get network interface with ID=%d of computer with ID=%d: %w", interfaceID, computerID, err)
+	}
+
+	return convertNetworkInterfaceDBOToModel(interfaceDBO), nil
+}
+
+// AddNetworkInterface adds a network interface to a computer and returns its generated ID. A primary interface
+// replaces the computer's current primary interface. The IP address is subject to the IP conflict policy.
+func (s *ComputerMgmtService) AddNetworkInterface(computerID int, iface model.NetworkInterface) (int, error) {
+	var (
+		interfaceID int
+		conflicting []model.Computer
+	)
+
+	err := s.withTx(func(tx *ComputerMgmtService) error {
+		var err error
+
+		conflicting, err = tx.checkInterfaceIPConflict(iface, computerID)
+		if err != nil {
+			return err
+		}
+
+		iface.ComputerID = computerID
+
+		interfaceID, err = tx.repository.AddNetworkInterface(convertNetworkInterfaceModelToDBO(iface))
+		if err != nil {
+			return err
+		}
+
+		return tx.recordComputerEvent(model.ComputerUpdated, computerID)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to add a network interface to computer with ID=%d: %w", computerID, err)
+	}
+
+	if len(conflicting) > 0 {
+		go s.notifier.SendIPConflictMessage(*iface.IPAddress, conflicting)
+	}
+
+	return interfaceID, nil
+}
+
+// UpdateNetworkInterface updates a network interface of a computer. The IP address is subject to the IP conflict policy.
+func (s *ComputerMgmtService) UpdateNetworkInterface(computerID, interfaceID int, data model.NetworkInterface) error {
+	var conflicting []model.Computer
+
+	err := s.withTx(func(tx *ComputerMgmtService) error {
+		var err error
+
+		conflicting, err = tx.checkInterfaceIPConflict(data, computerID)
+		if err != nil {
+			return err
+		}
+
+		data.ID = interfaceID
+		data.ComputerID = computerID
+
+		if err := tx.repository.UpdateNetworkInterface(computerID, interfaceID, convertNetworkInterfaceModelToDBO(data)); err != nil {
+			return err
+		}
+
+		return tx.recordComputerEvent(model.ComputerUpdated, computerID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update network interface with ID=%d of computer with ID=%d: %w", interfaceID, computerID, err)
+	}
+
+	if len(conflicting) > 0 {
+		go s.notifier.SendIPConflictMessage(*data.IPAddress, conflicting)
+	}
+
+	return nil
+}
+
+// DeleteNetworkInterface removes a network interface of a computer. The primary interface cannot be removed.
+func (s *ComputerMgmtService) DeleteNetworkInterface(computerID, interfaceID int) error {
+	err := s.withTx(func(tx *ComputerMgmtService) error {
+		if err := tx.repository.DeleteNetworkInterface(computerID, interfaceID); err != nil {
+			return err
+		}
+
+		return tx.recordComputerEvent(model.ComputerUpdated, computerID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete network interface with ID=%d of computer with ID=%d: %w", interfaceID, computerID, err)
+	}
+
+	return nil
+}
+
+// checkInterfaceIPConflict checks the IP address of the interface like checkIPConflict. If the address is already used
+// by other computers, it returns them together with the computer of the interface.
+func (s *ComputerMgmtService) checkInterfaceIPConflict(iface model.NetworkInterface, computerID int) ([]model.Computer, error) {
+	if iface.IPAddress == nil {
+		return nil, nil
+	}
+
+	conflicting, err := s.checkIPConflict(*iface.IPAddress, computerID)
+	if err != nil || len(conflicting) == 0 {
+		return nil, err
+	}
+
+	computer, err := s.GetComputer(computerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(conflicting, computer), nil
+}
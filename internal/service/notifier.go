@@ -1,62 +1,137 @@
 package service
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-	"net/http"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"uhuaha/computers-management/internal/model"
+	"uhuaha/computers-management/internal/notify"
+
+	errs "uhuaha/computers-management/internal/errors"
 
 	"github.com/bdlm/log"
 )
 
-type NotificationPayload struct {
-	Level                string `json:"level"`
-	EmployeeAbbreviation string `json:"employeeAbbreviation"`
-	Message              string `json:"message"`
+// notificationTimeout limits the time the channels have to deliver a notification.
+const notificationTimeout = 30 * time.Second
+
+// NotificationSender delivers a notification to the configured channels, e.g. a notify.FanOut.
+type NotificationSender interface {
+	Send(ctx context.Context, notification notify.Notification) error
 }
 
+// Notifier renders the notifications with the templates of its locale and sends them.
 type Notifier struct {
-	connection string
+	sender    NotificationSender
+	templates *notify.Templates
+	locale    string
 }
 
-func NewNotifier(connection string) *Notifier {
+func NewNotifier(sender NotificationSender, templates *notify.Templates, locale string) *Notifier {
 	return &Notifier{
-		connection: connection,
+		sender:    sender,
+		templates: templates,
+		locale:    locale,
 	}
 }
 
-// SendMessage sends a warning message if an employee has 3 or more computers assigned to them.
-func (n *Notifier) SendMessage(employeeAbbreviation string) {
-	payload := NotificationPayload{
-		Level:                "warning",
-		EmployeeAbbreviation: employeeAbbreviation,
-		Message:              "There are 3 or more computers assigned to the same employee.",
+// SendMessage sends a warning message if an employee has 3 or more computers assigned to them. It returns an error
+// if the message could not be delivered to all channels, which wraps notify.ErrNotDelivered if no channel has
+// delivered it.
+func (n *Notifier) SendMessage(count model.AssignmentCount) error {
+	return n.send("warning", notify.EventAssignmentThreshold, assignmentTemplateData(count))
+}
+
+// SendResolvedMessage sends a message if an employee who had 3 or more computers assigned to them has fewer again.
+func (n *Notifier) SendResolvedMessage(count model.AssignmentCount) {
+	if err := n.send("resolved", notify.EventAssignmentResolved, assignmentTemplateData(count)); err != nil {
+		log.Error("failed to send message: " + err.Error())
 	}
+}
 
-	notification, err := json.Marshal(payload)
-	if err != nil {
-		log.Error("failed to send message: failed to marshal payload: " + err.Error())
-		return
+// SendIPConflictMessage sends a warning message if an IP address is used by more than one computer. The message names
+// the computers using the address.
+func (n *Notifier) SendIPConflictMessage(ipAddress string, computers []model.Computer) {
+	names := make([]string, len(computers))
+	for i, computer := range computers {
+		names[i] = computer.Name
 	}
 
-	notifyURL := n.connection + "/api/notify"
-	resp, err := http.Post(notifyURL, "application/json", bytes.NewReader(notification))
-	if err != nil {
-		log.Error("failed to send message: failed to send POST request to /api/notify: " + err.Error())
-		return
+	if err := n.send("warning", notify.EventIPConflict, notify.TemplateData{IPAddress: ipAddress, Computers: names}); err != nil {
+		log.Error("failed to send message: " + err.Error())
+	}
+}
+
+// SendWarrantyExpiryMessage sends a warranty message if a computer's warranty is about to end. It returns an error
+// if the message could not be delivered to all channels.
+func (n *Notifier) SendWarrantyExpiryMessage(computer model.Computer) error {
+	if computer.WarrantyEnd == nil {
+		return nil
 	}
 
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			log.Error("failed to close response body: " + err.Error())
-		}
-	}()
+	data := notify.TemplateData{
+		ComputerID:  computer.ID,
+		Computer:    computer.Name,
+		WarrantyEnd: computer.WarrantyEnd.Format(time.DateOnly),
+	}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	if computer.SerialNumber != nil {
+		data.SerialNumber = *computer.SerialNumber
+	}
+
+	if computer.EmployeeAbbreviation != nil {
+		data.Employee = *computer.EmployeeAbbreviation
+	}
+
+	return n.send("warranty", notify.EventWarrantyExpiry, data)
+}
+
+// PreviewNotificationTemplate renders the template of the event type with sample data. An empty locale selects the
+// locale of the notifier. It returns a not found error if there is no template for the event type.
+func (n *Notifier) PreviewNotificationTemplate(event, locale string) (model.NotificationPreview, error) {
+	if locale == "" {
+		locale = n.locale
+	}
+
+	rendered, err := n.templates.Render(event, locale, notify.SampleData(event))
+	if errors.Is(err, notify.ErrTemplateNotFound) {
+		return model.NotificationPreview{}, errs.NewNotFound(fmt.Sprintf("notification template %q not found", event))
+	} else if err != nil {
+		return model.NotificationPreview{}, fmt.Errorf("failed to render notification template %q: %w", event, err)
+	}
+
+	return model.NotificationPreview{
+		Event:   event,
+		Locale:  rendered.Locale,
+		Subject: rendered.Subject,
+		Body:    rendered.Body,
+	}, nil
+}
+
+func assignmentTemplateData(count model.AssignmentCount) notify.TemplateData {
+	return notify.TemplateData{
+		Employee:  count.EmployeeAbbreviation,
+		Count:     count.Count,
+		Threshold: count.Threshold,
+		Computers: count.ComputerNames,
+	}
+}
+
+// send renders the template of the event type and sends the notification with the given level.
+func (n *Notifier) send(level, event string, data notify.TemplateData) error {
+	rendered, err := n.templates.Render(event, n.locale, data)
 	if err != nil {
-		log.Error("failed to read response body: " + err.Error())
-		return
+		return fmt.Errorf("%w: %w", notify.ErrNotDelivered, err)
 	}
 
-	log.Infof("/api/notify responded with status %d: %s", resp.StatusCode, string(bodyBytes))
+	ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+	defer cancel()
+
+	return n.sender.Send(ctx, notify.Notification{
+		Level:                level,
+		EmployeeAbbreviation: data.Employee,
+		Subject:              rendered.Subject,
+		Message:              rendered.Body,
+	})
 }
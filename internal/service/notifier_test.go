@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"uhuaha/computers-management/internal/model"
+	"uhuaha/computers-management/internal/notify"
+	"uhuaha/computers-management/templates/notifications"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNotificationSender struct {
+	sent []notify.Notification
+}
+
+func (s *fakeNotificationSender) Send(ctx context.Context, notification notify.Notification) error {
+	s.sent = append(s.sent, notification)
+	return nil
+}
+
+func newTestNotifier(t *testing.T, locale string) (*Notifier, *fakeNotificationSender) {
+	templates, err := notify.LoadTemplates(notifications.FS)
+	require.NoError(t, err)
+
+	sender := &fakeNotificationSender{}
+
+	return NewNotifier(sender, templates, locale), sender
+}
+
+func TestNotifierSendMessage(t *testing.T) {
+	notifier, sender := newTestNotifier(t, "en")
+
+	notifier.SendMessage(model.AssignmentCount{
+		EmployeeAbbreviation: "EMP",
+		Count:                3,
+		Threshold:            3,
+		ComputerNames:        []string{"PC1", "PC2", "PC3"},
+	})
+
+	expected := []notify.Notification{{
+		Level:                "warning",
+		EmployeeAbbreviation: "EMP",
+		Subject:              "EMP has 3 computers assigned",
+		Message:              "There are 3 computers assigned to the employee EMP, which reaches the threshold of 3: PC1, PC2, PC3.",
+	}}
+	assert.Equal(t, expected, sender.sent)
+}
+
+func TestNotifierSendIPConflictMessage(t *testing.T) {
+	notifier, sender := newTestNotifier(t, "en")
+
+	notifier.SendIPConflictMessage("10.0.0.1", []model.Computer{{ID: 1, Name: "PC1"}, {ID: 2, Name: "PC2"}})
+
+	expected := []notify.Notification{{
+		Level:   "warning",
+		Subject: "IP address 10.0.0.1 is used more than once",
+		Message: "The IP address 10.0.0.1 is used by more than one computer: PC1, PC2.",
+	}}
+	assert.Equal(t, expected, sender.sent)
+}
+
+func TestNotifierPreviewNotificationTemplate(t *testing.T) {
+	notifier, _ := newTestNotifier(t, "de")
+
+	preview, err := notifier.PreviewNotificationTemplate(notify.EventAssignmentThreshold, "")
+	require.NoError(t, err)
+	assert.Equal(t, "de", preview.Locale)
+	assert.Equal(t, notify.EventAssignmentThreshold, preview.Event)
+	assert.NotEmpty(t, preview.Subject)
+	assert.NotEmpty(t, preview.Body)
+
+	// A locale without templates falls back to the default locale.
+	preview, err = notifier.PreviewNotificationTemplate(notify.EventIPConflict, "fr")
+	require.NoError(t, err)
+	assert.Equal(t, notify.DefaultLocale, preview.Locale)
+
+	_, err = notifier.PreviewNotificationTemplate("unknown", "en")
+	var nf *errs.NotFoundError
+	assert.ErrorAs(t, err, &nf)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"uhuaha/computers-management/internal/model"
+)
+
+// SearchComputers returns the computers matching the given query ordered by descending relevance.
+func (s *ComputerMgmtService) SearchComputers(ctx context.Context, query string) ([]model.ComputerSearchResult, error) {
+	resultDBOs, err := s.repository.SearchComputers(ctx, query)
+	if err != nil {
+		return []model.ComputerSearchResult{}, fmt.Errorf("failed to search computers for %q: %w", query, err)
+	}
+
+	pattern := highlightPattern(query)
+
+	results := make([]model.ComputerSearchResult, len(resultDBOs))
+	for i, resultDBO := range resultDBOs {
+		computer := convertComputerDBOToModel(resultDBO.Computer)
+
+		results[i] = model.ComputerSearchResult{
+			Computer:   computer,
+			Rank:       resultDBO.Rank,
+			Highlights: highlight(computer, resultDBO.MACAddresses, pattern),
+		}
+	}
+
+	return results, nil
+}
+
+// highlightPattern builds a case-insensitive pattern matching any whitespace-separated term of the query.
+// Longer terms are preferred so that a term contained in another one doesn't split its highlight.
+func highlightPattern(query string) *regexp.Regexp {
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	sort.Slice(terms, func(i, j int) bool { return len(terms[i]) > len(terms[j]) })
+
+	for i, term := range terms {
+		terms[i] = regexp.QuoteMeta(term)
+	}
+
+	return regexp.MustCompile("(?i)" + strings.Join(terms, "|"))
+}
+
+// highlight marks the occurrences of the pattern in the searchable fields of a computer. The values are
+// HTML-escaped, so that only the marks are markup. The MAC address is the first of the given MAC addresses of the
+// computer's interfaces that matches, the primary one first. Fields without an occurrence are omitted.
+func highlight(computer model.Computer, macAddresses []string, pattern *regexp.Regexp) map[string]string {
+	highlights := make(map[string]string)
+	if pattern == nil {
+		return highlights
+	}
+
+	fields := map[string]*string{
+		"name":                  &computer.Name,
+		"description":           computer.Description,
+		"employee_abbreviation": computer.EmployeeAbbreviation,
+	}
+
+	if len(macAddresses) == 0 {
+		macAddresses = []string{computer.MACAddress}
+	}
+
+	for _, macAddress := range macAddresses {
+		if pattern.MatchString(macAddress) {
+			fields["mac_address"] = &macAddress
+			break
+		}
+	}
+
+	for field, value := range fields {
+		if value != nil && pattern.MatchString(*value) {
+			highlights[field] = markMatches(*value, pattern)
+		}
+	}
+
+	return highlights
+}
+
+// markMatches wraps the occurrences of the pattern in value with <mark> tags and escapes the text in and between
+// them.
+func markMatches(value string, pattern *regexp.Regexp) string {
+	var b strings.Builder
+
+	last := 0
+	for _, match := range pattern.FindAllStringIndex(value, -1) {
+		b.WriteString(html.EscapeString(value[last:match[0]]))
+		b.WriteString("<mark>" + html.EscapeString(value[match[0]:match[1]]) + "</mark>")
+		last = match[1]
+	}
+
+	b.WriteString(html.EscapeString(value[last:]))
+
+	return b.String()
+}
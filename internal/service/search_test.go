@@ -0,0 +1,92 @@
+package service
+
+import (
+	"testing"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlight(t *testing.T) {
+	description := "Development machine for the dev team"
+	employee := "DEV"
+
+	computer := model.Computer{
+		Name:                 "DevPC-01",
+		MACAddress:           "AA:BB:CC:DD:EE:01",
+		Description:          &description,
+		EmployeeAbbreviation: &employee,
+	}
+
+	tests := []struct {
+		name         string
+		computer     *model.Computer
+		macAddresses []string
+		query        string
+		expected     map[string]string
+	}{
+		{
+			name:  "case-insensitive matches in all fields",
+			query: "dev",
+			expected: map[string]string{
+				"name":                  "<mark>Dev</mark>PC-01",
+				"description":           "<mark>Dev</mark>elopment machine for the <mark>dev</mark> team",
+				"employee_abbreviation": "<mark>DEV</mark>",
+			},
+		},
+		{
+			name:  "multiple terms prefer the longer match",
+			query: "team machine te",
+			expected: map[string]string{
+				"description": "Development <mark>machine</mark> for the dev <mark>team</mark>",
+			},
+		},
+		{
+			name:  "regular expression characters are matched literally",
+			query: "ee:01",
+			expected: map[string]string{
+				"mac_address": "AA:BB:CC:DD:<mark>EE:01</mark>",
+			},
+		},
+		{
+			name:         "MAC address of a secondary interface",
+			macAddresses: []string{"AA:BB:CC:DD:EE:01", "11:22:33:44:55:66"},
+			query:        "44:55",
+			expected: map[string]string{
+				"mac_address": "11:22:33:<mark>44:55</mark>:66",
+			},
+		},
+		{
+			name:         "primary MAC address is preferred",
+			macAddresses: []string{"AA:BB:CC:DD:EE:01", "AA:BB:CC:00:00:02"},
+			query:        "aa:bb",
+			expected: map[string]string{
+				"mac_address": "<mark>AA:BB</mark>:CC:DD:EE:01",
+			},
+		},
+		{
+			name:     "markup in values is escaped",
+			computer: &model.Computer{Name: "<script>alert(1)</script> & more", MACAddress: "AA:BB:CC:DD:EE:01"},
+			query:    "script amp",
+			expected: map[string]string{
+				"name": "&lt;<mark>script</mark>&gt;alert(1)&lt;/<mark>script</mark>&gt; &amp; more",
+			},
+		},
+		{
+			name:     "empty query",
+			query:    "  ",
+			expected: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := computer
+			if tt.computer != nil {
+				c = *tt.computer
+			}
+
+			assert.Equal(t, tt.expected, highlight(c, tt.macAddresses, highlightPattern(tt.query)))
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"fmt"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+)
+
+// statusTransitions maps each status to the statuses a computer may change to from it.
+// Only computers in stock can be assigned and retired is terminal.
+var statusTransitions = map[model.ComputerStatus][]model.ComputerStatus{
+	model.StatusInStock:  {model.StatusAssigned, model.StatusInRepair, model.StatusLost, model.StatusRetired},
+	model.StatusAssigned: {model.StatusInStock, model.StatusInRepair, model.StatusLost},
+	model.StatusInRepair: {model.StatusInStock, model.StatusLost, model.StatusRetired},
+	model.StatusLost:     {model.StatusInStock, model.StatusRetired},
+	model.StatusRetired:  {},
+}
+
+// allowedStatuses returns the statuses a computer with the given status may change to.
+func allowedStatuses(from model.ComputerStatus) []model.ComputerStatus {
+	return statusTransitions[from]
+}
+
+// transitionStatuses returns the statuses a computer with the given status may change to via
+// TransitionComputerStatus, i.e. all allowed statuses except assigned, which requires a check-out.
+func transitionStatuses(from model.ComputerStatus) []model.ComputerStatus {
+	var statuses []model.ComputerStatus
+
+	for _, status := range allowedStatuses(from) {
+		if status != model.StatusAssigned {
+			statuses = append(statuses, status)
+		}
+	}
+
+	return statuses
+}
+
+// statusNames converts statuses to their names.
+func statusNames(statuses []model.ComputerStatus) []string {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+
+	return names
+}
+
+func canTransition(from, to model.ComputerStatus) bool {
+	for _, status := range allowedStatuses(from) {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// initialStatus returns the status of a newly added computer.
+func initialStatus(computer model.Computer) model.ComputerStatus {
+	if computer.EmployeeAbbreviation != nil {
+		return model.StatusAssigned
+	}
+
+	return model.StatusInStock
+}
+
+// TransitionComputerStatus changes the status of a computer to transition.To and records the reason and actor.
+// It returns an invalid transition error listing the allowed statuses if the state machine forbids the change.
+// Computers are only assigned by checking them out, so a transition to assigned is rejected with a validation error.
+// A computer leaving the status assigned is taken back from its employee: its active assignment ends and its
+// employee is removed. If the employee drops back under the 3-computer threshold, the system administrator is
+// notified that the warning is resolved.
+func (s *ComputerMgmtService) TransitionComputerStatus(computerID int, transition model.StatusTransition) (model.StatusTransition, error) {
+	if transition.To == model.StatusAssigned {
+		return model.StatusTransition{}, errs.NewValidation("a computer can only be assigned by checking it out")
+	}
+
+	var (
+		transitionDBO dbo.StatusTransition
+		notifications []thresholdNotification
+	)
+
+	err := s.withTx(func(tx *ComputerMgmtService) error {
+		computer, err := tx.GetComputer(computerID)
+		if err != nil {
+			return err
+		}
+
+		if !canTransition(computer.Status, transition.To) {
+			return errs.NewInvalidTransition(
+				fmt.Sprintf("computer with ID=%d cannot change its status from %q to %q", computerID, computer.Status, transition.To),
+				statusNames(transitionStatuses(computer.Status)),
+			)
+		}
+
+		transition.ComputerID = computerID
+		transition.From = computer.Status
+
+		transitionDBO, err = tx.repository.AddStatusTransition(convertStatusTransitionModelToDBO(transition))
+		if err != nil {
+			return err
+		}
+
+		tx.recordEvent(model.ComputerUpdated, computerID, computer.EmployeeAbbreviation)
+
+		if computer.Status == model.StatusAssigned && computer.EmployeeAbbreviation != nil {
+			notifications, err = tx.evaluateAssignmentThreshold(*computer.EmployeeAbbreviation)
+		}
+
+		return err
+	})
+	if err != nil {
+		return model.StatusTransition{}, fmt.Errorf("failed to change status of computer with ID=%d: %w", computerID, err)
+	}
+
+	s.sendThresholdNotifications(notifications)
+
+	return convertStatusTransitionDBOToModel(transitionDBO), nil
+}
+
+// GetStatusTransitions returns the status history of a computer ordered from oldest to newest.
+func (s *ComputerMgmtService) GetStatusTransitions(computerID int) ([]model.StatusTransition, error) {
+	if _, err := s.GetComputer(computerID); err != nil {
+		return nil, fmt.Errorf("failed to get status transitions of computer with ID=%d: %w", computerID, err)
+	}
+
+	transitionDBOs, err := s.repository.GetStatusTransitions(computerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status transitions of computer with ID=%d: %w", computerID, err)
+	}
+
+	transitions := make([]model.StatusTransition, len(transitionDBOs))
+	for i, dbo := range transitionDBOs {
+		transitions[i] = convertStatusTransitionDBOToModel(dbo)
+	}
+
+	return transitions, nil
+}
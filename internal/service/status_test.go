@@ -0,0 +1,99 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from     model.ComputerStatus
+		to       model.ComputerStatus
+		expected bool
+	}{
+		{from: model.StatusInStock, to: model.StatusAssigned, expected: true},
+		{from: model.StatusInRepair, to: model.StatusAssigned, expected: false},
+		{from: model.StatusLost, to: model.StatusAssigned, expected: false},
+		{from: model.StatusAssigned, to: model.StatusInRepair, expected: true},
+		{from: model.StatusAssigned, to: model.StatusRetired, expected: false},
+		{from: model.StatusInRepair, to: model.StatusRetired, expected: true},
+		{from: model.StatusLost, to: model.StatusInStock, expected: true},
+		{from: model.StatusRetired, to: model.StatusInStock, expected: false},
+		{from: model.StatusInStock, to: model.StatusInStock, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.expected, canTransition(tt.from, tt.to))
+		})
+	}
+}
+
+func TestStatusTransitionsCoverAllStatuses(t *testing.T) {
+	for _, status := range []model.ComputerStatus{
+		model.StatusInStock, model.StatusAssigned, model.StatusInRepair, model.StatusLost, model.StatusRetired,
+	} {
+		next, ok := statusTransitions[status]
+		assert.True(t, ok, "missing transitions for %q", status)
+
+		for _, to := range next {
+			assert.True(t, to.IsValid())
+			assert.NotEqual(t, status, to)
+		}
+	}
+
+	assert.Empty(t, statusTransitions[model.StatusRetired], "retired must be terminal")
+}
+
+func TestTransitionComputerStatusRejectsAssigned(t *testing.T) {
+	repo := &fakeAssignmentRepository{computer: dbo.Computer{ID: 1, Status: "in_stock"}}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{})
+
+	_, err := s.TransitionComputerStatus(1, model.StatusTransition{To: model.StatusAssigned, Reason: "Hand out", Actor: "ADM"})
+
+	var validation *errs.ValidationError
+	require.ErrorAs(t, err, &validation)
+	assert.Empty(t, repo.transition)
+}
+
+func TestTransitionComputerStatusAllowedStatusesExcludeAssigned(t *testing.T) {
+	repo := &fakeAssignmentRepository{computer: dbo.Computer{ID: 1, Status: "in_stock"}}
+	s := NewComputerMgmtService(repo, &fakeMessageSender{})
+
+	_, err := s.TransitionComputerStatus(1, model.StatusTransition{To: model.StatusInStock, Reason: "Stock", Actor: "ADM"})
+
+	var invalid *errs.InvalidTransitionError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, []string{"in_repair", "lost", "retired"}, invalid.Allowed)
+}
+
+func TestTransitionComputerStatusFromAssignedResolvesWarning(t *testing.T) {
+	employee := sql.NullString{String: "EMP", Valid: true}
+	repo := &fakeAssignmentRepository{
+		computer:    dbo.Computer{ID: 1, Status: "assigned", EmployeeAbbreviation: employee},
+		assignments: []dbo.Assignment{{ID: 1}, {ID: 2}},
+		warnings:    fakeAssignmentWarnings{warnings: map[string]fakeAssignmentWarning{"EMP": {count: 3}}},
+	}
+	notifier := &fakeMessageSender{resolved: make(chan string, 1)}
+	s := NewComputerMgmtService(repo, notifier)
+
+	_, err := s.TransitionComputerStatus(1, model.StatusTransition{To: model.StatusInRepair, Reason: "Broken display", Actor: "ADM"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "assigned", repo.transition.FromStatus)
+
+	select {
+	case resolved := <-notifier.resolved:
+		assert.Equal(t, "EMP", resolved)
+	case <-time.After(time.Second):
+		assert.Fail(t, "missing resolved notification")
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/bdlm/log"
+)
+
+// maxAllocationAttempts limits how often allocating an address is retried when a concurrent
+// allocation reserved the same address first.
+const maxAllocationAttempts = 5
+
+type SubnetRepository interface {
+	AddSubnet(subnet dbo.Subnet) (int, error)
+	GetSubnet(subnetID int) (dbo.Subnet, error)
+	GetAllSubnets() ([]dbo.Subnet, error)
+	UpdateSubnet(subnetID int, data dbo.Subnet) error
+	DeleteSubnet(subnetID int) error
+	GetUsedIPAddresses(subnetID int) ([]string, error)
+	GetAllocatedIPAddresses(subnetID int) ([]string, error)
+	AddIPAllocation(subnetID int, ipAddress string) error
+	DeleteIPAllocation(subnetID int, ipAddress string) error
+	DeleteIPAllocationsBefore(before time.Time) (int, error)
+}
+
+type SubnetMgmtService struct {
+	repository SubnetRepository
+	// allocationTTL is the time after which an allocation that has not been used is released. Zero keeps
+	// allocations until they are used or released.
+	allocationTTL time.Duration
+	now           func() time.Time
+}
+
+func NewSubnetMgmtService(repo SubnetRepository, allocationTTL time.Duration) *SubnetMgmtService {
+	return &SubnetMgmtService{
+		repository:    repo,
+		allocationTTL: allocationTTL,
+		now:           time.Now,
+	}
+}
+
+// AddSubnet stores a new subnet and returns its generated ID.
+func (s *SubnetMgmtService) AddSubnet(subnet model.Subnet) (int, error) {
+	subnetID, err := s.repository.AddSubnet(convertSubnetModelToDBO(subnet))
+	if err != nil {
+		return 0, fmt.Errorf("failed to add a subnet: %w", err)
+	}
+
+	return subnetID, nil
+}
+
+// GetSubnet retrieves a subnet by its ID.
+func (s *SubnetMgmtService) GetSubnet(subnetID int) (model.Subnet, error) {
+	subnetDBO, err := s.repository.GetSubnet(subnetID)
+	if err != nil {
+		return model.Subnet{}, fmt.Errorf("failed to get subnet with ID=%d: %w", subnetID, err)
+	}
+
+	return convertSubnetDBOToModel(subnetDBO), nil
+}
+
+// GetAllSubnets returns a list of all subnets.
+func (s *SubnetMgmtService) GetAllSubnets() ([]model.Subnet, error) {
+	subnetDBOs, err := s.repository.GetAllSubnets()
+	if err != nil {
+		return []model.Subnet{}, fmt.Errorf("failed to get all subnets: %w", err)
+	}
+
+	subnets := make([]model.Subnet, len(subnetDBOs))
+	for i, dbo := range subnetDBOs {
+		subnets[i] = convertSubnetDBOToModel(dbo)
+	}
+
+	return subnets, nil
+}
+
+// UpdateSubnet updates the data of an existing subnet identified by its ID.
+func (s *SubnetMgmtService) UpdateSubnet(subnetID int, data model.Subnet) error {
+	data.ID = subnetID
+
+	if err := s.repository.UpdateSubnet(subnetID, convertSubnetModelToDBO(data)); err != nil {
+		return fmt.Errorf("failed to update the subnet with ID=%d: %w", subnetID, err)
+	}
+
+	return nil
+}
+
+// DeleteSubnet removes a subnet by its ID.
+func (s *SubnetMgmtService) DeleteSubnet(subnetID int) error {
+	if err := s.repository.DeleteSubnet(subnetID); err != nil {
+		return fmt.Errorf("failed to delete subnet with ID=%d: %w", subnetID, err)
+	}
+
+	return nil
+}
+
+// GetSubnetUtilization returns how many host addresses of a subnet are used, allocated, reserved and free.
+func (s *SubnetMgmtService) GetSubnetUtilization(subnetID int) (model.SubnetUtilization, error) {
+	plan, err := s.getAddressPlan(subnetID)
+	if err != nil {
+		return model.SubnetUtilization{}, fmt.Errorf("failed to get utilization of subnet with ID=%d: %w", subnetID, err)
+	}
+
+	return plan.utilization(subnetID), nil
+}
+
+// AllocateIPAddress reserves and returns the lowest host address of a subnet that is neither reserved, used by a
+// computer nor allocated before. The allocation is released once a network interface uses the address, by
+// ReleaseIPAddress or, if it has not been used in time, by ReleaseExpiredIPAllocations. It returns a conflict error if
+// the subnet is exhausted or if all attempts have collided with concurrent allocations.
+func (s *SubnetMgmtService) AllocateIPAddress(subnetID int) (string, error) {
+	for range maxAllocationAttempts {
+		plan, err := s.getAddressPlan(subnetID)
+		if err != nil {
+			return "", fmt.Errorf("failed to allocate an IP address in subnet with ID=%d: %w", subnetID, err)
+		}
+
+		ipAddress, ok := plan.nextFree()
+		if !ok {
+			return "", errs.NewConflict(fmt.Sprintf("subnet with ID=%d has no free IP address", subnetID))
+		}
+
+		err = s.repository.AddIPAllocation(subnetID, ipAddress)
+
+		var conflict *errs.ConflictError
+		if errors.As(err, &conflict) {
+			// The address has been allocated concurrently, so try the next one.
+			continue
+		} else if err != nil {
+			return "", fmt.Errorf("failed to allocate IP address %s in subnet with ID=%d: %w", ipAddress, subnetID, err)
+		}
+
+		return ipAddress, nil
+	}
+
+	return "", errs.NewConflict(fmt.Sprintf("no IP address could be allocated in subnet with ID=%d after %d attempts due to concurrent allocations, please retry", subnetID, maxAllocationAttempts))
+}
+
+// ReleaseIPAddress releases the allocation of an IP address within a subnet, so that it can be allocated again.
+// It returns a not found error if the address is not allocated within the subnet.
+func (s *SubnetMgmtService) ReleaseIPAddress(subnetID int, ipAddress string) error {
+	if err := s.repository.DeleteIPAllocation(subnetID, ipAddress); err != nil {
+		return fmt.Errorf("failed to release IP address %s in subnet with ID=%d: %w", ipAddress, subnetID, err)
+	}
+
+	return nil
+}
+
+// ReleaseExpiredIPAllocations releases all allocations that have not been used within the allocation TTL and
+// returns their number. Nothing is released if the TTL is zero.
+func (s *SubnetMgmtService) ReleaseExpiredIPAllocations() (int, error) {
+	if s.allocationTTL <= 0 {
+		return 0, nil
+	}
+
+	released, err := s.repository.DeleteIPAllocationsBefore(s.now().Add(-s.allocationTTL))
+	if err != nil {
+		return 0, fmt.Errorf("failed to release expired IP allocations: %w", err)
+	}
+
+	return released, nil
+}
+
+// RunAllocationExpiry releases expired allocations immediately and then once per interval until ctx is canceled.
+func (s *SubnetMgmtService) RunAllocationExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		released, err := s.ReleaseExpiredIPAllocations()
+		if err != nil {
+			log.Error("scheduled release of expired IP allocations failed: " + err.Error())
+		} else if released > 0 {
+			log.Infof("released %d expired IP allocation(s)", released)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *SubnetMgmtService) getAddressPlan(subnetID int) (addressPlan, error) {
+	return loadAddressPlan(s.repository, subnetID)
+}
+
+// addressPlanRepository provides the data of the address plan of a subnet.
+type addressPlanRepository interface {
+	GetSubnet(subnetID int) (dbo.Subnet, error)
+	GetUsedIPAddresses(subnetID int) ([]string, error)
+	GetAllocatedIPAddresses(subnetID int) ([]string, error)
+}
+
+func loadAddressPlan(repo addressPlanRepository, subnetID int) (addressPlan, error) {
+	subnet, err := repo.GetSubnet(subnetID)
+	if err != nil {
+		return addressPlan{}, err
+	}
+
+	used, err := repo.GetUsedIPAddresses(subnetID)
+	if err != nil {
+		return addressPlan{}, err
+	}
+
+	allocated, err := repo.GetAllocatedIPAddresses(subnetID)
+	if err != nil {
+		return addressPlan{}, err
+	}
+
+	return newAddressPlan(subnet, used, allocated)
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAllocationRepository records the time before which allocations have been released. Calling any other
+// method panics.
+type fakeAllocationRepository struct {
+	SubnetRepository
+
+	releasedBefore []time.Time
+}
+
+func (r *fakeAllocationRepository) DeleteIPAllocationsBefore(before time.Time) (int, error) {
+	r.releasedBefore = append(r.releasedBefore, before)
+	return 2, nil
+}
+
+func TestReleaseExpiredIPAllocations(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("releases allocations older than the TTL", func(t *testing.T) {
+		repo := &fakeAllocationRepository{}
+		s := NewSubnetMgmtService(repo, time.Hour)
+		s.now = func() time.Time { return now }
+
+		released, err := s.ReleaseExpiredIPAllocations()
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, released)
+		assert.Equal(t, []time.Time{now.Add(-time.Hour)}, repo.releasedBefore)
+	})
+
+	t.Run("keeps allocations without TTL", func(t *testing.T) {
+		repo := &fakeAllocationRepository{}
+		s := NewSubnetMgmtService(repo, 0)
+
+		released, err := s.ReleaseExpiredIPAllocations()
+		require.NoError(t, err)
+
+		assert.Zero(t, released)
+		assert.Empty(t, repo.releasedBefore)
+	})
+}
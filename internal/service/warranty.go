@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/bdlm/log"
+)
+
+type WarrantyRepository interface {
+	ClaimExpiringWarranties(from, to time.Time) ([]dbo.Computer, error)
+	// ReleaseWarrantyClaim reverts the claim of a computer's warranty end, so that the next check reports it again.
+	ReleaseWarrantyClaim(computerID int, warrantyEnd time.Time) error
+}
+
+// WarrantyNotifier reports a computer whose warranty is about to end. It returns an error if the report could not
+// be delivered.
+type WarrantyNotifier interface {
+	SendWarrantyExpiryMessage(computer model.Computer) error
+}
+
+type WarrantyMgmtService struct {
+	repository WarrantyRepository
+	notifier   WarrantyNotifier
+	// windowDays is the number of days ahead within which an ending warranty is reported.
+	windowDays int
+	now        func() time.Time
+}
+
+func NewWarrantyMgmtService(repo WarrantyRepository, notifier WarrantyNotifier, windowDays int) *WarrantyMgmtService {
+	return &WarrantyMgmtService{
+		repository: repo,
+		notifier:   notifier,
+		windowDays: windowDays,
+		now:        time.Now,
+	}
+}
+
+// CheckWarranties claims every computer whose warranty ends within the configured window starting today and
+// returns these computers. The notifications are sent in the background, so that a slow channel does not delay
+// the caller. A computer is reported only once per warranty end date; if its notification fails, the claim is
+// released and the next check reports it again.
+func (s *WarrantyMgmtService) CheckWarranties() ([]model.Computer, error) {
+	year, month, day := s.now().Date()
+	from := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, s.windowDays)
+
+	computerDBOs, err := s.repository.ClaimExpiringWarranties(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check warranties: %w", err)
+	}
+
+	computers := make([]model.Computer, len(computerDBOs))
+	for i, dbo := range computerDBOs {
+		computers[i] = convertComputerDBOToModel(dbo)
+	}
+
+	if len(computers) > 0 {
+		go s.sendWarrantyNotifications(computers)
+	}
+
+	return computers, nil
+}
+
+// sendWarrantyNotifications reports the claimed computers one after another and releases the claims of the
+// computers whose report failed.
+func (s *WarrantyMgmtService) sendWarrantyNotifications(computers []model.Computer) {
+	for _, computer := range computers {
+		err := s.notifier.SendWarrantyExpiryMessage(computer)
+		if err == nil {
+			continue
+		}
+
+		log.Errorf("failed to report the warranty end of computer with ID=%d: %v", computer.ID, err)
+
+		if err := s.repository.ReleaseWarrantyClaim(computer.ID, *computer.WarrantyEnd); err != nil {
+			log.Errorf("failed to release the warranty claim of computer with ID=%d: %v", computer.ID, err)
+		}
+	}
+}
+
+// RunScheduler checks the warranties right away and then once per interval until ctx is cancelled.
+func (s *WarrantyMgmtService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		computers, err := s.CheckWarranties()
+		if err != nil {
+			log.Error("scheduled warranty check failed: " + err.Error())
+		} else {
+			log.Infof("scheduled warranty check reported %d computer(s)", len(computers))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
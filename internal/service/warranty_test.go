@@ -0,0 +1,97 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWarrantyRepository struct {
+	from, to  time.Time
+	computers []dbo.Computer
+	err       error
+	released  chan int
+}
+
+func (r *fakeWarrantyRepository) ClaimExpiringWarranties(from, to time.Time) ([]dbo.Computer, error) {
+	r.from, r.to = from, to
+	return r.computers, r.err
+}
+
+func (r *fakeWarrantyRepository) ReleaseWarrantyClaim(computerID int, _ time.Time) error {
+	r.released <- computerID
+	return nil
+}
+
+type fakeWarrantyNotifier struct {
+	notified chan model.Computer
+	err      error
+}
+
+func newFakeWarrantyNotifier(err error) *fakeWarrantyNotifier {
+	return &fakeWarrantyNotifier{notified: make(chan model.Computer, 10), err: err}
+}
+
+func (n *fakeWarrantyNotifier) SendWarrantyExpiryMessage(computer model.Computer) error {
+	n.notified <- computer
+	return n.err
+}
+
+func TestCheckWarranties(t *testing.T) {
+	warrantyEnd := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	repo := &fakeWarrantyRepository{
+		computers: []dbo.Computer{
+			{ID: 1, Name: "TestPC-01", WarrantyEnd: sql.NullTime{Time: warrantyEnd, Valid: true}},
+		},
+	}
+	notifier := newFakeWarrantyNotifier(nil)
+
+	s := NewWarrantyMgmtService(repo, notifier, 30)
+	s.now = func() time.Time { return time.Date(2025, 3, 1, 23, 30, 0, 0, time.FixedZone("CET", 3600)) }
+
+	computers, err := s.CheckWarranties()
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), repo.from)
+	assert.Equal(t, time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), repo.to)
+
+	expected := []model.Computer{{ID: 1, Name: "TestPC-01", WarrantyEnd: &warrantyEnd}}
+	assert.Equal(t, expected, computers)
+	assert.Equal(t, expected[0], <-notifier.notified)
+}
+
+func TestCheckWarrantiesReleasesFailedClaims(t *testing.T) {
+	warrantyEnd := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	repo := &fakeWarrantyRepository{
+		computers: []dbo.Computer{
+			{ID: 1, Name: "TestPC-01", WarrantyEnd: sql.NullTime{Time: warrantyEnd, Valid: true}},
+		},
+		released: make(chan int, 1),
+	}
+	notifier := newFakeWarrantyNotifier(fmt.Errorf("channel unavailable"))
+
+	computers, err := NewWarrantyMgmtService(repo, notifier, 30).CheckWarranties()
+	require.NoError(t, err)
+
+	assert.Len(t, computers, 1)
+	assert.Equal(t, 1, (<-notifier.notified).ID)
+	assert.Equal(t, 1, <-repo.released)
+}
+
+func TestCheckWarrantiesRepositoryError(t *testing.T) {
+	repo := &fakeWarrantyRepository{err: fmt.Errorf("db failure")}
+	notifier := newFakeWarrantyNotifier(nil)
+
+	_, err := NewWarrantyMgmtService(repo, notifier, 30).CheckWarranties()
+
+	assert.Error(t, err)
+	assert.Empty(t, notifier.notified)
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/model"
+
+	"github.com/bdlm/log"
+)
+
+const (
+	// SignatureHeader carries the signature of a webhook payload in the format "t=<unix time>,v1=<hex HMAC>".
+	SignatureHeader = "X-Signature"
+	// EventTypeHeader carries the type of the event of a webhook payload.
+	EventTypeHeader = "X-Event-Type"
+
+	// maxWebhookAttempts limits how often the delivery of an event to a subscription is attempted.
+	maxWebhookAttempts = 5
+	// webhookRetryBackoff is the delay before the second attempt. It doubles with every further attempt.
+	webhookRetryBackoff = time.Second
+	// webhookTimeout limits the time a subscriber has to answer a delivery.
+	webhookTimeout = 10 * time.Second
+	// webhookQueueSize is the number of events buffered until they are dispatched to the subscriptions.
+	webhookQueueSize = 1024
+	// webhookDeliveryLogLimit is the number of most recent delivery attempts returned per subscription.
+	webhookDeliveryLogLimit = 100
+)
+
+type WebhookRepository interface {
+	AddWebhookSubscription(subscription dbo.WebhookSubscription) (int, error)
+	GetWebhookSubscription(subscriptionID int) (dbo.WebhookSubscription, error)
+	GetAllWebhookSubscriptions(ctx context.Context) ([]dbo.WebhookSubscription, error)
+	UpdateWebhookSubscription(subscriptionID int, data dbo.WebhookSubscription) error
+	DeleteWebhookSubscription(subscriptionID int) error
+	AddWebhookDelivery(delivery dbo.WebhookDelivery) (dbo.WebhookDelivery, error)
+	GetWebhookDeliveries(subscriptionID, limit int) ([]dbo.WebhookDelivery, error)
+}
+
+// WebhookPayload is the body posted to a webhook subscription. ID and ComputerID are omitted for test events.
+type WebhookPayload struct {
+	ID                   int64     `json:"id,omitempty"`
+	Type                 string    `json:"type"`
+	ComputerID           int       `json:"computer_id,omitempty"`
+	EmployeeAbbreviation *string   `json:"employee_abbreviation,omitempty"`
+	OccurredAt           time.Time `json:"occurred_at"`
+}
+
+// WebhookMgmtService manages the webhook subscriptions and delivers the published computer events to them. The
+// events are queued by Publish and delivered by Run, so that a slow subscriber does not delay any request.
+type WebhookMgmtService struct {
+	repository WebhookRepository
+	client     *http.Client
+	queue      chan model.ComputerEvent
+	// retryBackoff is the delay before the second attempt of a delivery.
+	retryBackoff time.Duration
+	now          func() time.Time
+}
+
+func NewWebhookMgmtService(repo WebhookRepository) *WebhookMgmtService {
+	return &WebhookMgmtService{
+		repository:   repo,
+		client:       &http.Client{Timeout: webhookTimeout},
+		queue:        make(chan model.ComputerEvent, webhookQueueSize),
+		retryBackoff: webhookRetryBackoff,
+		now:          time.Now,
+	}
+}
+
+// AddWebhookSubscription stores a new webhook subscription and returns it with its generated ID. A secret is
+// generated if none is given.
+func (s *WebhookMgmtService) AddWebhookSubscription(subscription model.WebhookSubscription) (model.WebhookSubscription, error) {
+	if subscription.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return model.WebhookSubscription{}, fmt.Errorf("failed to add a webhook subscription: %w", err)
+		}
+
+		subscription.Secret = secret
+	}
+
+	subscriptionID, err := s.repository.AddWebhookSubscription(convertWebhookSubscriptionModelToDBO(subscription))
+	if err != nil {
+		return model.WebhookSubscription{}, fmt.Errorf("failed to add a webhook subscription: %w", err)
+	}
+
+	subscription.ID = subscriptionID
+
+	return subscription, nil
+}
+
+// GetWebhookSubscription retrieves a webhook subscription by its ID.
+func (s *WebhookMgmtService) GetWebhookSubscription(subscriptionID int) (model.WebhookSubscription, error) {
+	subscriptionDBO, err := s.repository.GetWebhookSubscription(subscriptionID)
+	if err != nil {
+		return model.WebhookSubscription{}, fmt.Errorf("failed to get webhook subscription with ID=%d: %w", subscriptionID, err)
+	}
+
+	return convertWebhookSubscriptionDBOToModel(subscriptionDBO), nil
+}
+
+// GetAllWebhookSubscriptions returns a list of all webhook subscriptions.
+func (s *WebhookMgmtService) GetAllWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	subscriptionDBOs, err := s.repository.GetAllWebhookSubscriptions(ctx)
+	if err != nil {
+		return []model.WebhookSubscription{}, fmt.Errorf("failed to get all webhook subscriptions: %w", err)
+	}
+
+	subscriptions := make([]model.WebhookSubscription, len(subscriptionDBOs))
+	for i, dbo := range subscriptionDBOs {
+		subscriptions[i] = convertWebhookSubscriptionDBOToModel(dbo)
+	}
+
+	return subscriptions, nil
+}
+
+// UpdateWebhookSubscription updates the URL, event types and secret of a webhook subscription. The secret is kept
+// if none is given.
+func (s *WebhookMgmtService) UpdateWebhookSubscription(subscriptionID int, data model.WebhookSubscription) error {
+	if data.Secret == "" {
+		current, err := s.GetWebhookSubscription(subscriptionID)
+		if err != nil {
+			return err
+		}
+
+		data.Secret = current.Secret
+	}
+
+	data.ID = subscriptionID
+
+	if err := s.repository.UpdateWebhookSubscription(subscriptionID, convertWebhookSubscriptionModelToDBO(data)); err != nil {
+		return fmt.Errorf("failed to update the webhook subscription with ID=%d: %w", subscriptionID, err)
+	}
+
+	return nil
+}
+
+// DeleteWebhookSubscription removes a webhook subscription together with its delivery log.
+func (s *WebhookMgmtService) DeleteWebhookSubscription(subscriptionID int) error {
+	if err := s.repository.DeleteWebhookSubscription(subscriptionID); err != nil {
+		return fmt.Errorf("failed to delete webhook subscription with ID=%d: %w", subscriptionID, err)
+	}
+
+	return nil
+}
+
+// GetWebhookDeliveries returns the most recent delivery attempts of a webhook subscription ordered from newest
+// to oldest.
+func (s *WebhookMgmtService) GetWebhookDeliveries(subscriptionID int) ([]model.WebhookDelivery, error) {
+	if _, err := s.GetWebhookSubscription(subscriptionID); err != nil {
+		return nil, fmt.Errorf("failed to get deliveries of webhook subscription with ID=%d: %w", subscriptionID, err)
+	}
+
+	deliveryDBOs, err := s.repository.GetWebhookDeliveries(subscriptionID, webhookDeliveryLogLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deliveries of webhook subscription with ID=%d: %w", subscriptionID, err)
+	}
+
+	deliveries := make([]model.WebhookDelivery, len(deliveryDBOs))
+	for i, dbo := range deliveryDBOs {
+		deliveries[i] = convertWebhookDeliveryDBOToModel(dbo)
+	}
+
+	return deliveries, nil
+}
+
+// TestWebhookSubscription sends a test event to a webhook subscription regardless of its event types and returns
+// the recorded delivery. The delivery is attempted once.
+func (s *WebhookMgmtService) TestWebhookSubscription(ctx context.Context, subscriptionID int) (model.WebhookDelivery, error) {
+	subscription, err := s.GetWebhookSubscription(subscriptionID)
+	if err != nil {
+		return model.WebhookDelivery{}, fmt.Errorf("failed to test webhook subscription with ID=%d: %w", subscriptionID, err)
+	}
+
+	event := model.ComputerEvent{Type: model.WebhookTest, OccurredAt: s.now().UTC()}
+
+	delivery, err := s.attemptDelivery(ctx, subscription, event, 1)
+	if err != nil {
+		return model.WebhookDelivery{}, fmt.Errorf("failed to test webhook subscription with ID=%d: %w", subscriptionID, err)
+	}
+
+	return delivery, nil
+}
+
+// Publish queues the events for delivery. Events that do not fit into the queue are dropped and logged since
+// publishing must not block the committed change.
+func (s *WebhookMgmtService) Publish(events ...model.ComputerEvent) {
+	for _, event := range events {
+		select {
+		case s.queue <- event:
+		default:
+			log.Errorf("failed to queue computer event with ID=%d for webhooks: the queue is full", event.ID)
+		}
+	}
+}
+
+// Run delivers the queued events to the subscriptions of their type until ctx is canceled. Each delivery runs
+// concurrently and is retried with exponential backoff, so that the events may arrive out of order.
+func (s *WebhookMgmtService) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-s.queue:
+			subscriptions, err := s.GetAllWebhookSubscriptions(ctx)
+			if err != nil {
+				log.Errorf("failed to dispatch computer event with ID=%d: %v", event.ID, err)
+				continue
+			}
+
+			for _, subscription := range subscriptions {
+				if subscription.Subscribes(event.Type) {
+					go s.deliver(ctx, subscription, event)
+				}
+			}
+		}
+	}
+}
+
+// deliver attempts to deliver the event to the subscription until it succeeds, fails permanently or the attempts
+// are exhausted.
+func (s *WebhookMgmtService) deliver(ctx context.Context, subscription model.WebhookSubscription, event model.ComputerEvent) {
+	backoff := s.retryBackoff
+
+	for attempt := 1; attempt <= maxWebhookAttempts; attempt++ {
+		delivery, err := s.attemptDelivery(ctx, subscription, event, attempt)
+		if err != nil {
+			log.Errorf("failed to deliver computer event with ID=%d to webhook subscription with ID=%d: %v", event.ID, subscription.ID, err)
+			return
+		}
+
+		if delivery.Succeeded || !isRetryableDelivery(delivery) || attempt == maxWebhookAttempts {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+// attemptDelivery posts the signed event to the subscription once and records the attempt. It only returns an
+// error if the attempt cannot be recorded or the payload cannot be built.
+func (s *WebhookMgmtService) attemptDelivery(ctx context.Context, subscription model.WebhookSubscription, event model.ComputerEvent, attempt int) (model.WebhookDelivery, error) {
+	body, err := json.Marshal(WebhookPayload{
+		ID:                   event.ID,
+		Type:                 string(event.Type),
+		ComputerID:           event.ComputerID,
+		EmployeeAbbreviation: event.EmployeeAbbreviation,
+		OccurredAt:           event.OccurredAt,
+	})
+	if err != nil {
+		return model.WebhookDelivery{}, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	delivery := model.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventType:      event.Type,
+		Attempt:        attempt,
+	}
+
+	if event.ID != 0 {
+		delivery.EventID = &event.ID
+	}
+
+	start := s.now()
+	statusCode, err := s.post(ctx, subscription, event.Type, body)
+	delivery.Duration = s.now().Sub(start)
+
+	if err != nil {
+		msg := err.Error()
+		delivery.Error = &msg
+	} else {
+		delivery.StatusCode = &statusCode
+		delivery.Succeeded = statusCode >= 200 && statusCode < 300
+
+		if !delivery.Succeeded {
+			msg := fmt.Sprintf("unexpected status code %d", statusCode)
+			delivery.Error = &msg
+		}
+	}
+
+	stored, err := s.repository.AddWebhookDelivery(convertWebhookDeliveryModelToDBO(delivery))
+	if err != nil {
+		return model.WebhookDelivery{}, fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+
+	return convertWebhookDeliveryDBOToModel(stored), nil
+}
+
+// post sends the body signed with the secret of the subscription and returns the status code of the response.
+func (s *WebhookMgmtService) post(ctx context.Context, subscription model.WebhookSubscription, eventType model.ComputerEventType, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventTypeHeader, string(eventType))
+	req.Header.Set(SignatureHeader, SignWebhookPayload(subscription.Secret, s.now(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns the value of the X-Signature header of a payload sent at the given time. The signature
+// is the HMAC-SHA256 of "<unix time>.<body>" keyed with the secret, so that receivers can reject replayed payloads
+// by their timestamp.
+func SignWebhookPayload(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "t=" + unix + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// isRetryableDelivery reports whether a failed delivery may succeed later, i.e. whether no response has been
+// received or the subscriber has answered with a timeout, rate limit or server error.
+func isRetryableDelivery(delivery model.WebhookDelivery) bool {
+	if delivery.StatusCode == nil {
+		return true
+	}
+
+	code := *delivery.StatusCode
+
+	return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"uhuaha/computers-management/internal/db/postgres/dbo"
+	"uhuaha/computers-management/internal/model"
+
+	errs "uhuaha/computers-management/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWebhookRepository keeps the webhook subscriptions and deliveries in memory.
+type fakeWebhookRepository struct {
+	mu            sync.Mutex
+	subscriptions []dbo.WebhookSubscription
+	deliveries    []dbo.WebhookDelivery
+}
+
+func (r *fakeWebhookRepository) AddWebhookSubscription(subscription dbo.WebhookSubscription) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	subscription.ID = len(r.subscriptions) + 1
+	r.subscriptions = append(r.subscriptions, subscription)
+
+	return subscription.ID, nil
+}
+
+func (r *fakeWebhookRepository) GetWebhookSubscription(subscriptionID int) (dbo.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, subscription := range r.subscriptions {
+		if subscription.ID == subscriptionID {
+			return subscription, nil
+		}
+	}
+
+	return dbo.WebhookSubscription{}, errs.NewNotFound("webhook subscription not found")
+}
+
+func (r *fakeWebhookRepository) GetAllWebhookSubscriptions(ctx context.Context) ([]dbo.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]dbo.WebhookSubscription(nil), r.subscriptions...), nil
+}
+
+func (r *fakeWebhookRepository) UpdateWebhookSubscription(subscriptionID int, data dbo.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscriptions[subscriptionID-1] = data
+
+	return nil
+}
+
+func (r *fakeWebhookRepository) DeleteWebhookSubscription(subscriptionID int) error {
+	return nil
+}
+
+func (r *fakeWebhookRepository) AddWebhookDelivery(delivery dbo.WebhookDelivery) (dbo.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delivery.ID = int64(len(r.deliveries) + 1)
+	r.deliveries = append(r.deliveries, delivery)
+
+	return delivery, nil
+}
+
+func (r *fakeWebhookRepository) GetWebhookDeliveries(subscriptionID, limit int) ([]dbo.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]dbo.WebhookDelivery(nil), r.deliveries...), nil
+}
+
+func (r *fakeWebhookRepository) recordedDeliveries() []dbo.WebhookDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]dbo.WebhookDelivery(nil), r.deliveries...)
+}
+
+// receivedWebhook is a request received by the test server.
+type receivedWebhook struct {
+	eventType string
+	signature string
+	body      string
+}
+
+// newWebhookServer returns a server that records the received requests and answers them with the given status
+// codes in turn, repeating the last one.
+func newWebhookServer(t *testing.T, statusCodes ...int) (*httptest.Server, func() []receivedWebhook) {
+	var (
+		mu       sync.Mutex
+		received []receivedWebhook
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		received = append(received, receivedWebhook{
+			eventType: r.Header.Get(EventTypeHeader),
+			signature: r.Header.Get(SignatureHeader),
+			body:      string(body),
+		})
+		statusCode := statusCodes[min(len(received), len(statusCodes))-1]
+		mu.Unlock()
+
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []receivedWebhook {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return append([]receivedWebhook(nil), received...)
+	}
+}
+
+func TestWebhookMgmtServiceDeliversSignedEvents(t *testing.T) {
+	server, received := newWebhookServer(t, http.StatusNoContent)
+
+	repo := &fakeWebhookRepository{}
+	s := NewWebhookMgmtService(repo)
+
+	subscription, err := s.AddWebhookSubscription(model.WebhookSubscription{
+		URL:        server.URL,
+		EventTypes: []model.ComputerEventType{model.ComputerCreated},
+		Secret:     "0123456789abcdef",
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go s.Run(ctx)
+
+	employee := "EMP"
+	occurredAt := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	s.Publish(
+		model.ComputerEvent{ID: 1, Type: model.ComputerUpdated, ComputerID: 7, OccurredAt: occurredAt},
+		model.ComputerEvent{ID: 2, Type: model.ComputerCreated, ComputerID: 7, EmployeeAbbreviation: &employee, OccurredAt: occurredAt},
+	)
+
+	require.Eventually(t, func() bool { return len(repo.recordedDeliveries()) == 1 }, time.Second, 5*time.Millisecond)
+
+	requests := received()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "computer.created", requests[0].eventType)
+	assert.JSONEq(t, `{"id":2,"type":"computer.created","computer_id":7,"employee_abbreviation":"EMP","occurred_at":"2025-03-10T08:00:00Z"}`, requests[0].body)
+
+	// The receiver verifies the signature with the shared secret and the timestamp of the header.
+	timestamp, err := strconv.ParseInt(strings.TrimPrefix(strings.Split(requests[0].signature, ",")[0], "t="), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, SignWebhookPayload(subscription.Secret, time.Unix(timestamp, 0), []byte(requests[0].body)), requests[0].signature)
+
+	delivery := repo.recordedDeliveries()[0]
+	assert.Equal(t, subscription.ID, delivery.SubscriptionID)
+	assert.Equal(t, int64(2), delivery.EventID.Int64)
+	assert.Equal(t, int64(http.StatusNoContent), delivery.StatusCode.Int64)
+	assert.True(t, delivery.Succeeded)
+}
+
+func TestWebhookMgmtServiceRetriesFailedDeliveries(t *testing.T) {
+	tests := []struct {
+		name              string
+		statusCodes       []int
+		expectedAttempts  int
+		expectedSucceeded bool
+	}{
+		{
+			name:              "server errors are retried until the delivery succeeds",
+			statusCodes:       []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK},
+			expectedAttempts:  3,
+			expectedSucceeded: true,
+		},
+		{
+			name:             "attempts are limited",
+			statusCodes:      []int{http.StatusServiceUnavailable},
+			expectedAttempts: maxWebhookAttempts,
+		},
+		{
+			name:             "client errors are not retried",
+			statusCodes:      []int{http.StatusBadRequest},
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, received := newWebhookServer(t, tt.statusCodes...)
+
+			repo := &fakeWebhookRepository{}
+			s := NewWebhookMgmtService(repo)
+			s.retryBackoff = time.Millisecond
+
+			_, err := s.AddWebhookSubscription(model.WebhookSubscription{URL: server.URL, EventTypes: []model.ComputerEventType{model.ComputerDeleted}})
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			go s.Run(ctx)
+
+			s.Publish(model.ComputerEvent{ID: 1, Type: model.ComputerDeleted, ComputerID: 7})
+
+			require.Eventually(t, func() bool { return len(repo.recordedDeliveries()) == tt.expectedAttempts }, time.Second, 5*time.Millisecond)
+
+			// No further attempt follows.
+			time.Sleep(50 * time.Millisecond)
+			deliveries := repo.recordedDeliveries()
+			require.Len(t, deliveries, tt.expectedAttempts)
+			assert.Len(t, received(), tt.expectedAttempts)
+
+			for i, delivery := range deliveries {
+				assert.Equal(t, i+1, delivery.Attempt)
+			}
+
+			assert.Equal(t, tt.expectedSucceeded, deliveries[len(deliveries)-1].Succeeded)
+		})
+	}
+}
+
+func TestTestWebhookSubscription(t *testing.T) {
+	server, received := newWebhookServer(t, http.StatusBadGateway)
+
+	repo := &fakeWebhookRepository{}
+	s := NewWebhookMgmtService(repo)
+	s.now = func() time.Time { return time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC) }
+
+	subscription, err := s.AddWebhookSubscription(model.WebhookSubscription{URL: server.URL, EventTypes: []model.ComputerEventType{model.ComputerCreated}})
+	require.NoError(t, err)
+	assert.Len(t, subscription.Secret, 64, "a secret is generated")
+
+	delivery, err := s.TestWebhookSubscription(context.Background(), subscription.ID)
+	require.NoError(t, err)
+
+	// A failed test is not retried.
+	assert.Equal(t, model.WebhookTest, delivery.EventType)
+	assert.Nil(t, delivery.EventID)
+	assert.Equal(t, http.StatusBadGateway, *delivery.StatusCode)
+	assert.Equal(t, "unexpected status code 502", *delivery.Error)
+	assert.False(t, delivery.Succeeded)
+
+	requests := received()
+	require.Len(t, requests, 1)
+	assert.JSONEq(t, `{"type":"webhook.test","occurred_at":"2025-03-10T08:00:00Z"}`, requests[0].body)
+	assert.Equal(t, SignWebhookPayload(subscription.Secret, s.now(), []byte(requests[0].body)), requests[0].signature)
+}
+
+func TestTestWebhookSubscriptionNotFound(t *testing.T) {
+	s := NewWebhookMgmtService(&fakeWebhookRepository{})
+
+	_, err := s.TestWebhookSubscription(context.Background(), 1)
+
+	require.ErrorAs(t, err, new(*errs.NotFoundError))
+}
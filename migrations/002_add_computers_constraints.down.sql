@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS computers_employee_abbreviation_idx;
+
+ALTER TABLE computers
+    DROP CONSTRAINT IF EXISTS computers_employee_abbreviation_length_check;
+
+ALTER TABLE computers
+    ALTER COLUMN ip_address TYPE TEXT USING host(ip_address),
+    ALTER COLUMN mac_address TYPE TEXT USING upper(mac_address::TEXT);
@@ -0,0 +1,39 @@
+-- The rows have not been validated before. Report all rows the type changes and the check below would reject
+-- instead of failing on the first one, so that they can be corrected before the migration is run again.
+DO $$
+DECLARE
+    computer RECORD;
+    invalid TEXT[] := '{}';
+BEGIN
+    FOR computer IN SELECT id, ip_address, mac_address, employee_abbreviation FROM computers ORDER BY id LOOP
+        BEGIN
+            PERFORM btrim(computer.ip_address)::INET, btrim(computer.mac_address)::MACADDR;
+        EXCEPTION WHEN data_exception THEN
+            invalid := invalid || format('id=%s: ip_address=%L, mac_address=%L',
+                computer.id, computer.ip_address, computer.mac_address);
+        END;
+
+        IF char_length(NULLIF(btrim(computer.employee_abbreviation), '')) <> 3 THEN
+            invalid := invalid || format('id=%s: employee_abbreviation=%L', computer.id, computer.employee_abbreviation);
+        END IF;
+    END LOOP;
+
+    IF cardinality(invalid) > 0 THEN
+        RAISE EXCEPTION 'computers with invalid addresses or employee abbreviations must be corrected first: %',
+            array_to_string(invalid, '; ');
+    END IF;
+END $$;
+
+ALTER TABLE computers
+    ALTER COLUMN ip_address TYPE INET USING btrim(ip_address)::INET,
+    ALTER COLUMN mac_address TYPE MACADDR USING btrim(mac_address)::MACADDR;
+
+UPDATE computers
+SET employee_abbreviation = NULLIF(btrim(employee_abbreviation), '')
+WHERE employee_abbreviation IS NOT NULL;
+
+ALTER TABLE computers
+    ADD CONSTRAINT computers_employee_abbreviation_length_check
+    CHECK (char_length(employee_abbreviation) = 3);
+
+CREATE INDEX computers_employee_abbreviation_idx ON computers (employee_abbreviation);
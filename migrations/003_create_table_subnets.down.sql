@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS ip_allocations;
+DROP TABLE IF EXISTS subnet_reserved_ranges;
+DROP TABLE IF EXISTS subnets;
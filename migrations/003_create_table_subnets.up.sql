@@ -0,0 +1,28 @@
+CREATE TABLE subnets (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    cidr CIDR NOT NULL,
+    gateway INET,
+    vlan INTEGER CHECK (vlan BETWEEN 1 AND 4094),
+    CONSTRAINT subnets_cidr_no_overlap EXCLUDE USING gist (cidr inet_ops WITH &&)
+);
+
+CREATE TABLE subnet_reserved_ranges (
+    id SERIAL PRIMARY KEY,
+    subnet_id INTEGER NOT NULL REFERENCES subnets (id) ON DELETE CASCADE,
+    start_address INET NOT NULL,
+    end_address INET NOT NULL,
+    description TEXT,
+    CHECK (start_address <= end_address)
+);
+
+CREATE INDEX subnet_reserved_ranges_subnet_id_idx ON subnet_reserved_ranges (subnet_id);
+
+CREATE TABLE ip_allocations (
+    id SERIAL PRIMARY KEY,
+    subnet_id INTEGER NOT NULL REFERENCES subnets (id) ON DELETE CASCADE,
+    ip_address INET NOT NULL UNIQUE,
+    allocated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ip_allocations_subnet_id_idx ON ip_allocations (subnet_id);
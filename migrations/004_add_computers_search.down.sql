@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS computers_mac_address_trgm_idx;
+DROP INDEX IF EXISTS computers_employee_abbreviation_trgm_idx;
+DROP INDEX IF EXISTS computers_description_trgm_idx;
+DROP INDEX IF EXISTS computers_name_trgm_idx;
+DROP INDEX IF EXISTS computers_search_vector_idx;
+
+ALTER TABLE computers DROP COLUMN IF EXISTS search_vector;
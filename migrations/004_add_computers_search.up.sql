@@ -0,0 +1,16 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE computers
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        to_tsvector('simple',
+            name || ' ' ||
+            coalesce(description, '') || ' ' ||
+            coalesce(employee_abbreviation, '') || ' ' ||
+            mac_address::TEXT)
+    ) STORED;
+
+CREATE INDEX computers_search_vector_idx ON computers USING gin (search_vector);
+CREATE INDEX computers_name_trgm_idx ON computers USING gin (name gin_trgm_ops);
+CREATE INDEX computers_description_trgm_idx ON computers USING gin (description gin_trgm_ops);
+CREATE INDEX computers_employee_abbreviation_trgm_idx ON computers USING gin (employee_abbreviation gin_trgm_ops);
+CREATE INDEX computers_mac_address_trgm_idx ON computers USING gin ((mac_address::TEXT) gin_trgm_ops);
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS computers_location_idx;
+DROP INDEX IF EXISTS computers_vendor_idx;
+DROP INDEX IF EXISTS computers_warranty_end_idx;
+
+ALTER TABLE computers
+    DROP CONSTRAINT IF EXISTS computers_warranty_end_after_purchase_check,
+    DROP COLUMN IF EXISTS location,
+    DROP COLUMN IF EXISTS operating_system,
+    DROP COLUMN IF EXISTS model,
+    DROP COLUMN IF EXISTS vendor,
+    DROP COLUMN IF EXISTS warranty_end,
+    DROP COLUMN IF EXISTS purchase_date,
+    DROP COLUMN IF EXISTS serial_number;
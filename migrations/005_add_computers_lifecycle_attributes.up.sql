@@ -0,0 +1,13 @@
+ALTER TABLE computers
+    ADD COLUMN serial_number TEXT,
+    ADD COLUMN purchase_date DATE,
+    ADD COLUMN warranty_end DATE,
+    ADD COLUMN vendor TEXT,
+    ADD COLUMN model TEXT,
+    ADD COLUMN operating_system TEXT,
+    ADD COLUMN location TEXT,
+    ADD CONSTRAINT computers_warranty_end_after_purchase_check CHECK (warranty_end >= purchase_date);
+
+CREATE INDEX computers_warranty_end_idx ON computers (warranty_end);
+CREATE INDEX computers_vendor_idx ON computers (lower(vendor));
+CREATE INDEX computers_location_idx ON computers (lower(location));
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS computer_status_transitions;
+
+DROP INDEX IF EXISTS computers_status_idx;
+
+ALTER TABLE computers
+    DROP CONSTRAINT IF EXISTS computers_status_check,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,19 @@
+ALTER TABLE computers
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'in_stock',
+    ADD CONSTRAINT computers_status_check CHECK (status IN ('in_stock', 'assigned', 'in_repair', 'lost', 'retired'));
+
+UPDATE computers SET status = 'assigned' WHERE employee_abbreviation IS NOT NULL;
+
+CREATE INDEX computers_status_idx ON computers (status);
+
+CREATE TABLE computer_status_transitions (
+    id SERIAL PRIMARY KEY,
+    computer_id INTEGER NOT NULL REFERENCES computers (id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX computer_status_transitions_computer_id_idx ON computer_status_transitions (computer_id, created_at);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS warranty_notifications;
@@ -0,0 +1,6 @@
+CREATE TABLE warranty_notifications (
+    computer_id INTEGER NOT NULL REFERENCES computers (id) ON DELETE CASCADE,
+    warranty_end DATE NOT NULL,
+    notified_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (computer_id, warranty_end)
+);
@@ -0,0 +1,30 @@
+ALTER TABLE computers
+    ADD COLUMN ip_address INET,
+    ADD COLUMN mac_address MACADDR;
+
+-- Only the primary interface of every computer can be kept.
+UPDATE computers
+SET ip_address = network_interfaces.ip_address, mac_address = network_interfaces.mac_address
+FROM network_interfaces
+WHERE network_interfaces.computer_id = computers.id AND network_interfaces.is_primary;
+
+ALTER TABLE computers
+    ALTER COLUMN ip_address SET NOT NULL,
+    ALTER COLUMN mac_address SET NOT NULL,
+    ADD CONSTRAINT computers_mac_address_key UNIQUE (mac_address);
+
+ALTER TABLE computers DROP COLUMN search_vector;
+
+ALTER TABLE computers
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        to_tsvector('simple',
+            name || ' ' ||
+            coalesce(description, '') || ' ' ||
+            coalesce(employee_abbreviation, '') || ' ' ||
+            mac_address::TEXT)
+    ) STORED;
+
+CREATE INDEX computers_search_vector_idx ON computers USING gin (search_vector);
+CREATE INDEX computers_mac_address_trgm_idx ON computers USING gin ((mac_address::TEXT) gin_trgm_ops);
+
+DROP TABLE IF EXISTS network_interfaces;
@@ -0,0 +1,40 @@
+CREATE TABLE network_interfaces (
+    id SERIAL PRIMARY KEY,
+    computer_id INTEGER NOT NULL REFERENCES computers (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    mac_address MACADDR NOT NULL,
+    ip_address INET,
+    type TEXT NOT NULL DEFAULT 'other',
+    is_primary BOOLEAN NOT NULL DEFAULT false,
+    CONSTRAINT network_interfaces_mac_address_key UNIQUE (mac_address),
+    CONSTRAINT network_interfaces_computer_id_name_key UNIQUE (computer_id, name),
+    CONSTRAINT network_interfaces_type_check CHECK (type IN ('ethernet', 'wifi', 'virtual', 'other')),
+    CONSTRAINT network_interfaces_primary_ip_address_check CHECK (NOT is_primary OR ip_address IS NOT NULL)
+);
+
+-- Every computer has at most one primary interface.
+CREATE UNIQUE INDEX network_interfaces_primary_idx ON network_interfaces (computer_id) WHERE is_primary;
+CREATE INDEX network_interfaces_ip_address_idx ON network_interfaces USING gist (ip_address inet_ops);
+CREATE INDEX network_interfaces_mac_address_trgm_idx ON network_interfaces USING gin ((mac_address::TEXT) gin_trgm_ops);
+
+-- The address pair of every computer becomes its primary interface.
+INSERT INTO network_interfaces (computer_id, name, mac_address, ip_address, type, is_primary)
+SELECT id, 'primary', mac_address, ip_address, 'other', true
+FROM computers;
+
+-- The search vector contains the MAC address, so it has to be rebuilt without it.
+ALTER TABLE computers DROP COLUMN search_vector;
+
+ALTER TABLE computers
+    DROP COLUMN ip_address,
+    DROP COLUMN mac_address;
+
+ALTER TABLE computers
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        to_tsvector('simple',
+            name || ' ' ||
+            coalesce(description, '') || ' ' ||
+            coalesce(employee_abbreviation, ''))
+    ) STORED;
+
+CREATE INDEX computers_search_vector_idx ON computers USING gin (search_vector);
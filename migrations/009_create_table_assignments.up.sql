@@ -0,0 +1,16 @@
+CREATE TABLE assignments (
+    id SERIAL PRIMARY KEY,
+    computer_id INTEGER NOT NULL REFERENCES computers (id) ON DELETE CASCADE,
+    employee_abbreviation TEXT NOT NULL CHECK (char_length(employee_abbreviation) = 3),
+    started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ended_at TIMESTAMPTZ,
+    CONSTRAINT assignments_period_check CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+-- A computer has at most one active assignment.
+CREATE UNIQUE INDEX assignments_active_idx ON assignments (computer_id) WHERE ended_at IS NULL;
+
+CREATE INDEX assignments_employee_abbreviation_idx ON assignments (employee_abbreviation, started_at);
+
+INSERT INTO assignments (computer_id, employee_abbreviation)
+SELECT id, employee_abbreviation FROM computers WHERE employee_abbreviation IS NOT NULL;
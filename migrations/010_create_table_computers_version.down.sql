@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS subnets_version_trigger ON subnets;
+DROP TRIGGER IF EXISTS network_interfaces_version_trigger ON network_interfaces;
+DROP TRIGGER IF EXISTS computers_version_trigger ON computers;
+DROP FUNCTION IF EXISTS bump_computers_version();
+DROP TABLE IF EXISTS computers_version;
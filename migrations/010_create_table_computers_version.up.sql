@@ -0,0 +1,29 @@
+-- computers_version holds a single row whose version is incremented by every statement changing the computers or
+-- the data derived into their representation, i.e. their network interfaces and the subnets. Since the row is
+-- updated within the changing transaction, a new version becomes visible together with the changes.
+CREATE TABLE computers_version (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    version BIGINT NOT NULL DEFAULT 1,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO computers_version DEFAULT VALUES;
+
+CREATE FUNCTION bump_computers_version() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE computers_version SET version = version + 1, changed_at = now();
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER computers_version_trigger
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON computers
+FOR EACH STATEMENT EXECUTE FUNCTION bump_computers_version();
+
+CREATE TRIGGER network_interfaces_version_trigger
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON network_interfaces
+FOR EACH STATEMENT EXECUTE FUNCTION bump_computers_version();
+
+CREATE TRIGGER subnets_version_trigger
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON subnets
+FOR EACH STATEMENT EXECUTE FUNCTION bump_computers_version();
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS computer_events;
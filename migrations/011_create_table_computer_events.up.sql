@@ -0,0 +1,9 @@
+-- computer_events is the log of changes of computers that are streamed to clients. Clients resume a stream from
+-- the ID of the last event they have received.
+CREATE TABLE computer_events (
+    id BIGSERIAL PRIMARY KEY,
+    type TEXT NOT NULL,
+    computer_id INTEGER NOT NULL,
+    employee_abbreviation TEXT,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,24 @@
+-- webhook_subscriptions holds the endpoints that are notified about the computer events of the given types.
+CREATE TABLE webhook_subscriptions (
+    id SERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    event_types TEXT[] NOT NULL CHECK (cardinality(event_types) > 0),
+    secret TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- webhook_deliveries logs every attempt to deliver an event to a subscription.
+CREATE TABLE webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id BIGINT,
+    event_type TEXT NOT NULL,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    succeeded BOOLEAN NOT NULL,
+    duration_ms INTEGER NOT NULL,
+    delivered_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX webhook_deliveries_subscription_id_idx ON webhook_deliveries (subscription_id, id);
@@ -0,0 +1,7 @@
+-- The last warning about the 3-computer threshold per employee. A row exists as long as the employee has been
+-- warned and not dropped back under the threshold.
+CREATE TABLE assignment_warnings (
+    employee_abbreviation TEXT PRIMARY KEY,
+    computer_count INTEGER NOT NULL,
+    notified_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
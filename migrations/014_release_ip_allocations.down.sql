@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS network_interfaces_release_ip_allocation_trigger ON network_interfaces;
+DROP FUNCTION IF EXISTS release_ip_allocation();
@@ -0,0 +1,15 @@
+-- An allocation only reserves an address until a network interface uses it. From then on, the address counts as
+-- used and becomes free again once the interface is deleted or its address is changed.
+DELETE FROM ip_allocations
+WHERE ip_address IN (SELECT ip_address FROM network_interfaces WHERE ip_address IS NOT NULL);
+
+CREATE FUNCTION release_ip_allocation() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM ip_allocations WHERE ip_address = NEW.ip_address;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER network_interfaces_release_ip_allocation_trigger
+AFTER INSERT OR UPDATE OF ip_address ON network_interfaces
+FOR EACH ROW WHEN (NEW.ip_address IS NOT NULL) EXECUTE FUNCTION release_ip_allocation();
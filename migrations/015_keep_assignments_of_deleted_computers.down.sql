@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS computers_end_assignments_trigger ON computers;
+DROP FUNCTION IF EXISTS end_assignments_of_deleted_computer();
+
+DELETE FROM assignments WHERE computer_id IS NULL;
+
+ALTER TABLE assignments
+    DROP COLUMN computer_name,
+    ALTER COLUMN computer_id SET NOT NULL,
+    DROP CONSTRAINT assignments_computer_id_fkey,
+    ADD CONSTRAINT assignments_computer_id_fkey FOREIGN KEY (computer_id) REFERENCES computers (id) ON DELETE CASCADE;
@@ -0,0 +1,28 @@
+-- Assignments are kept as history when their computer is deleted. Since the computer cannot be looked up anymore,
+-- its name is copied into the assignment and an active assignment is ended.
+ALTER TABLE assignments
+    ADD COLUMN computer_name TEXT,
+    ALTER COLUMN computer_id DROP NOT NULL,
+    DROP CONSTRAINT assignments_computer_id_fkey,
+    ADD CONSTRAINT assignments_computer_id_fkey FOREIGN KEY (computer_id) REFERENCES computers (id) ON DELETE SET NULL;
+
+CREATE FUNCTION end_assignments_of_deleted_computer() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE assignments SET computer_name = OLD.name, ended_at = coalesce(ended_at, now())
+    WHERE computer_id = OLD.id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER computers_end_assignments_trigger
+BEFORE DELETE ON computers
+FOR EACH ROW EXECUTE FUNCTION end_assignments_of_deleted_computer();
+
+-- The employee of a computer used to be changeable without checking it out or in. From now on a computer has an
+-- employee and an active assignment exactly if it is assigned.
+UPDATE computers SET status = 'assigned' WHERE status = 'in_stock' AND employee_abbreviation IS NOT NULL;
+
+UPDATE assignments SET ended_at = now()
+WHERE ended_at IS NULL AND computer_id IN (SELECT id FROM computers WHERE status <> 'assigned');
+
+UPDATE computers SET employee_abbreviation = NULL WHERE status <> 'assigned' AND employee_abbreviation IS NOT NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS computer_events_occurred_at_idx;
@@ -0,0 +1,2 @@
+-- Recorded events are deleted once they are older than the retention period.
+CREATE INDEX computer_events_occurred_at_idx ON computer_events (occurred_at);
@@ -0,0 +1,6 @@
+CREATE OR REPLACE FUNCTION bump_computers_version() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE computers_version SET version = version + 1, changed_at = now();
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,9 @@
+-- now() is the start time of the changing transaction, so a transaction that started before but committed after
+-- another one moved changed_at, and thereby Last-Modified, backwards. The clock time at the change is used instead
+-- and changed_at never decreases.
+CREATE OR REPLACE FUNCTION bump_computers_version() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE computers_version SET version = version + 1, changed_at = greatest(changed_at, clock_timestamp());
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migration files so that they are shipped
+// with the binary and can be applied without access to the source tree.
+package migrations
+
+import "embed"
+
+// FS holds all *.sql migration files of this directory.
+//
+//go:embed *.sql
+var FS embed.FS
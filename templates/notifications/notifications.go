@@ -0,0 +1,10 @@
+// Package notifications embeds the default notification templates so that they are shipped with the binary and
+// can be used without access to the source tree.
+package notifications
+
+import "embed"
+
+// FS holds a directory per locale with a *.tmpl file per event type.
+//
+//go:embed */*.tmpl
+var FS embed.FS